@@ -0,0 +1,98 @@
+package apierror
+
+import (
+	"errors"
+	"net/http"
+	"time"
+)
+
+// Codes identify the errors to clients, they are stable unlike the descriptions.
+const (
+	CodeValidation   = "validation_failed"
+	CodeUnauthorized = "unauthorized"
+	CodeForbidden    = "forbidden"
+	CodeNotFound     = "not_found"
+	CodeConflict     = "conflict"
+	CodeRateLimited  = "rate_limited"
+	CodeUnavailable  = "dependency_unavailable"
+	CodeInternal     = "internal_error"
+)
+
+// DefaultRetryAfter is the delay suggested to clients when a dependency is unavailable.
+const DefaultRetryAfter = 5 * time.Second
+
+// Error is an error safe to report to clients: Detail is sent to them while
+// the wrapped error is only logged.
+type Error struct {
+	Code       string
+	Status     int
+	Detail     string
+	RetryAfter time.Duration
+	Err        error
+}
+
+func (e *Error) Error() string {
+	if e.Err == nil {
+		return e.Detail
+	}
+	return e.Detail + ": " + e.Err.Error()
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+func Validation(detail string, err error) *Error {
+	return &Error{Code: CodeValidation, Status: http.StatusBadRequest, Detail: detail, Err: err}
+}
+
+func Unauthorized(detail string, err error) *Error {
+	return &Error{Code: CodeUnauthorized, Status: http.StatusUnauthorized, Detail: detail, Err: err}
+}
+
+func Forbidden(detail string, err error) *Error {
+	return &Error{Code: CodeForbidden, Status: http.StatusForbidden, Detail: detail, Err: err}
+}
+
+func NotFound(detail string) *Error {
+	return &Error{Code: CodeNotFound, Status: http.StatusNotFound, Detail: detail}
+}
+
+func Conflict(detail string, err error) *Error {
+	return &Error{Code: CodeConflict, Status: http.StatusConflict, Detail: detail, Err: err}
+}
+
+// TooManyRequests asks clients to retry after retryAfter, rounded up to a second.
+func TooManyRequests(retryAfter time.Duration) *Error {
+	return &Error{
+		Code:       CodeRateLimited,
+		Status:     http.StatusTooManyRequests,
+		Detail:     "rate limit exceeded",
+		RetryAfter: max(retryAfter, time.Second),
+	}
+}
+
+// Unavailable reports that dependency cannot be reached, clients are asked to
+// retry after DefaultRetryAfter.
+func Unavailable(dependency string, err error) *Error {
+	return &Error{
+		Code:       CodeUnavailable,
+		Status:     http.StatusServiceUnavailable,
+		Detail:     dependency + " is unavailable",
+		RetryAfter: DefaultRetryAfter,
+		Err:        err,
+	}
+}
+
+func Internal(err error) *Error {
+	return &Error{Code: CodeInternal, Status: http.StatusInternalServerError, Detail: "internal server error", Err: err}
+}
+
+// From returns the Error wrapped by err, other errors are internal errors.
+func From(err error) *Error {
+	var apiErr *Error
+	if errors.As(err, &apiErr) {
+		return apiErr
+	}
+	return Internal(err)
+}
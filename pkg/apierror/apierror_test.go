@@ -0,0 +1,62 @@
+package apierror
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFrom(t *testing.T) {
+	cause := errors.New("dial tcp: connection refused")
+
+	tests := []struct {
+		name           string
+		err            error
+		expectedCode   string
+		expectedStatus int
+		expectedDetail string
+	}{
+		{
+			name:           "unwraps api errors",
+			err:            fmt.Errorf("save score: %w", Unavailable("redis", cause)),
+			expectedCode:   CodeUnavailable,
+			expectedStatus: http.StatusServiceUnavailable,
+			expectedDetail: "redis is unavailable",
+		},
+		{
+			name:           "hides other errors",
+			err:            cause,
+			expectedCode:   CodeInternal,
+			expectedStatus: http.StatusInternalServerError,
+			expectedDetail: "internal server error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			apiErr := From(tt.err)
+
+			require.Equal(t, tt.expectedCode, apiErr.Code)
+			require.Equal(t, tt.expectedStatus, apiErr.Status)
+			require.Equal(t, tt.expectedDetail, apiErr.Detail)
+			require.ErrorIs(t, apiErr, cause)
+		})
+	}
+}
+
+func TestError_Problem(t *testing.T) {
+	problem := NotFound("player not found").Problem("/leaderboard/players/1", "request-1")
+
+	require.Equal(t, Problem{
+		Type:      "urn:problem-type:not_found",
+		Title:     "Not Found",
+		Status:    http.StatusNotFound,
+		Detail:    "player not found",
+		Instance:  "/leaderboard/players/1",
+		Code:      CodeNotFound,
+		RequestID: "request-1",
+	}, problem)
+}
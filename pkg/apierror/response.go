@@ -0,0 +1,43 @@
+package apierror
+
+import (
+	"net/http"
+)
+
+// ProblemContentType is the media type of RFC 7807 problem details.
+const ProblemContentType = "application/problem+json"
+
+// Body is the default JSON representation of errors.
+type Body struct {
+	Error       string `json:"error"`
+	Code        int    `json:"code"`
+	Description string `json:"description"`
+}
+
+// Problem is the RFC 7807 representation of errors, sent to the clients
+// accepting it. Code and RequestID are extension members.
+type Problem struct {
+	Type      string `json:"type"`
+	Title     string `json:"title"`
+	Status    int    `json:"status"`
+	Detail    string `json:"detail,omitempty"`
+	Instance  string `json:"instance,omitempty"`
+	Code      string `json:"code"`
+	RequestID string `json:"request_id,omitempty"`
+}
+
+func (e *Error) Body() Body {
+	return Body{Error: e.Code, Code: e.Status, Description: e.Detail}
+}
+
+func (e *Error) Problem(instance string, requestID string) Problem {
+	return Problem{
+		Type:      "urn:problem-type:" + e.Code,
+		Title:     http.StatusText(e.Status),
+		Status:    e.Status,
+		Detail:    e.Detail,
+		Instance:  instance,
+		Code:      e.Code,
+		RequestID: requestID,
+	}
+}
@@ -2,22 +2,44 @@ package app
 
 import (
 	"context"
+	"fmt"
 	"log/slog"
+	"strings"
 	"sync"
+	"time"
 )
 
 type Runnable interface {
 	Run(context.Context, chan error)
 }
 
+// Readier is implemented by processes that need time to become ready, processes
+// depending on them are only started once the Ready channel is closed.
+type Readier interface {
+	Ready() <-chan struct{}
+}
+
+const DefaultShutdownTimeout = 10 * time.Second
+
+type ShutdownError struct {
+	Hung []string
+}
+
+func (e *ShutdownError) Error() string {
+	return fmt.Sprintf("processes did not stop within their shutdown timeout: %s", strings.Join(e.Hung, ", "))
+}
+
 type Application struct {
-	processes map[string]Runnable
-	wg        sync.WaitGroup
+	processes   map[string]Runnable
+	supervisors map[string]*supervisor
+	stopped     chan struct{}
+	shutdownErr error
 }
 
 func NewApplication(processes map[string]Runnable) *Application {
 	return &Application{
 		processes: processes,
+		stopped:   make(chan struct{}),
 	}
 }
 
@@ -25,24 +47,114 @@ func (app *Application) Run(ctx context.Context) chan error {
 	slog.Info("starting application")
 	errChan := make(chan error)
 
+	app.supervisors = make(map[string]*supervisor, len(app.processes))
+	dependencies := make(map[string][]string, len(app.processes))
 	for name, process := range app.processes {
-		app.wg.Add(1)
+		app.supervisors[name] = newSupervisor(name, process)
+		dependencies[name] = app.supervisors[name].options.DependsOn
+	}
 
-		go func(name string, process Runnable) {
-			slog.Info("starting process", "name", name)
+	levels, err := startupLevels(dependencies)
+	if err != nil {
+		go func() {
+			errChan <- err
+		}()
+		close(app.stopped)
+		return errChan
+	}
+
+	for _, level := range levels {
+		for _, name := range level {
+			s := app.supervisors[name]
+			s.ctx, s.cancel = context.WithCancel(context.WithoutCancel(ctx))
 
-			defer app.wg.Done()
-			process.Run(ctx, errChan)
+			var dependencies []*supervisor
+			for _, dependency := range s.options.DependsOn {
+				dependencies = append(dependencies, app.supervisors[dependency])
+			}
 
-			slog.Info("process stopped", "name", name)
-		}(name, process)
+			go func() {
+				defer close(s.done)
+				if waitReady(s.ctx, dependencies) {
+					s.run(s.ctx, errChan)
+				}
+			}()
+		}
 	}
 
+	go func() {
+		defer close(app.stopped)
+
+		<-ctx.Done()
+		app.shutdownErr = app.stop(levels)
+	}()
+
 	return errChan
 }
 
-func (app *Application) Shutdown() {
+// stop cancels processes in reverse dependency order, processes in the same level are stopped concurrently.
+func (app *Application) stop(levels [][]string) error {
+	var hung []string
+
+	for i := len(levels) - 1; i >= 0; i-- {
+		var mu sync.Mutex
+		var wg sync.WaitGroup
+
+		for _, name := range levels[i] {
+			s := app.supervisors[name]
+			s.cancel()
+
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+
+				timer := time.NewTimer(s.shutdownTimeout())
+				defer timer.Stop()
+
+				select {
+				case <-s.done:
+				case <-timer.C:
+					slog.Error("process did not stop within shutdown timeout", "name", s.name, "timeout", s.shutdownTimeout())
+					mu.Lock()
+					hung = append(hung, s.name)
+					mu.Unlock()
+				}
+			}()
+		}
+
+		wg.Wait()
+	}
+
+	if len(hung) > 0 {
+		return &ShutdownError{Hung: hung}
+	}
+	return nil
+}
+
+func (app *Application) Shutdown() error {
 	slog.Info("application shutdown requested")
-	app.wg.Wait()
+	if app.supervisors == nil {
+		return nil
+	}
+
+	<-app.stopped
+
+	if app.shutdownErr != nil {
+		slog.Error("application shutdown incomplete", "error", app.shutdownErr.Error())
+		return app.shutdownErr
+	}
+
 	slog.Info("all processes stopped, application shutting down")
+	return nil
+}
+
+func waitReady(ctx context.Context, dependencies []*supervisor) bool {
+	for _, dependency := range dependencies {
+		select {
+		case <-ctx.Done():
+			return false
+		case <-dependency.ready:
+		}
+	}
+	return true
 }
@@ -102,3 +102,105 @@ func TestApplication(t *testing.T) {
 			})
 	}
 }
+
+type ReadyRunnable struct {
+	ready   chan struct{}
+	started chan string
+	stopped chan string
+	name    string
+	hang    bool
+}
+
+func NewReadyRunnable(name string, started, stopped chan string) *ReadyRunnable {
+	return &ReadyRunnable{
+		ready:   make(chan struct{}),
+		started: started,
+		stopped: stopped,
+		name:    name,
+	}
+}
+
+func (r *ReadyRunnable) Run(ctx context.Context, errChan chan error) {
+	r.started <- r.name
+	<-ctx.Done()
+	if r.hang {
+		select {}
+	}
+	r.stopped <- r.name
+}
+
+func (r *ReadyRunnable) Ready() <-chan struct{} {
+	return r.ready
+}
+
+func TestApplication_Dependencies(t *testing.T) {
+	started := make(chan string, 3)
+	stopped := make(chan string, 3)
+
+	consumer := NewReadyRunnable("consumer", started, stopped)
+	http := NewReadyRunnable("http", started, stopped)
+	metrics := NewReadyRunnable("metrics", started, stopped)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	application := NewApplication(map[string]Runnable{
+		"consumer": consumer,
+		"http":     Supervise(http, ProcessOptions{DependsOn: []string{"consumer", "metrics"}}),
+		"metrics":  metrics,
+	})
+	application.Run(ctx)
+
+	first := []string{<-started, <-started}
+	require.ElementsMatch(t, []string{"consumer", "metrics"}, first)
+
+	close(consumer.ready)
+	select {
+	case name := <-started:
+		t.Fatalf("%s started before all dependencies were ready", name)
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	close(metrics.ready)
+	require.Equal(t, "http", <-started)
+
+	cancel()
+	require.NoError(t, application.Shutdown())
+
+	require.Equal(t, "http", <-stopped)
+	require.ElementsMatch(t, []string{"consumer", "metrics"}, []string{<-stopped, <-stopped})
+}
+
+func TestApplication_ShutdownTimeout(t *testing.T) {
+	started := make(chan string, 2)
+	stopped := make(chan string, 2)
+
+	hung := NewReadyRunnable("hung", started, stopped)
+	hung.hang = true
+
+	ctx, cancel := context.WithCancel(context.Background())
+	application := NewApplication(map[string]Runnable{
+		"hung": Supervise(hung, ProcessOptions{ShutdownTimeout: 10 * time.Millisecond}),
+		"ok":   NewReadyRunnable("ok", started, stopped),
+	})
+	application.Run(ctx)
+	<-started
+	<-started
+
+	cancel()
+	err := application.Shutdown()
+
+	var shutdownErr *ShutdownError
+	require.ErrorAs(t, err, &shutdownErr)
+	require.Equal(t, []string{"hung"}, shutdownErr.Hung)
+	require.Equal(t, "ok", <-stopped)
+}
+
+func TestApplication_InvalidDependencies(t *testing.T) {
+	application := NewApplication(map[string]Runnable{
+		"http": Supervise(&MockRunnable{}, ProcessOptions{DependsOn: []string{"consumer"}}),
+	})
+
+	errChan := application.Run(context.Background())
+
+	require.EqualError(t, <-errChan, "process http depends on unknown process consumer")
+	require.NoError(t, application.Shutdown())
+}
@@ -0,0 +1,79 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+
+	"github.com/iamnotrodger/golang-projects/pkg/config"
+	"github.com/spf13/pflag"
+)
+
+// ConfigOptions are the options of MainWithConfig, T is the configuration spec.
+type ConfigOptions[T any] struct {
+	// NewLoader creates the loader of the configuration from the arguments,
+	// flags holds the flags of the binary.
+	NewLoader func(args []string, flags *pflag.FlagSet) *config.Loader[T]
+	// LogLevel reads the log level of a configuration.
+	LogLevel func(spec T) string
+	// Init builds the service once the configuration is loaded, logLevel
+	// follows the reloads of the configuration.
+	Init func(ctx context.Context, loader *config.Loader[T], logLevel *slog.LevelVar) (Service, error)
+}
+
+// MainWithConfig loads the configuration from the command line and runs the
+// service with Main, or prints the configuration when --print-config is set.
+// It returns the exit code.
+func MainWithConfig[T any](options ConfigOptions[T]) int {
+	return mainWithConfig(options, os.Args, os.Stdout, os.Stderr)
+}
+
+func mainWithConfig[T any](options ConfigOptions[T], args []string, stdout, stderr io.Writer) int {
+	flags := pflag.NewFlagSet(args[0], pflag.ContinueOnError)
+	printConfig := config.PrintFlag(flags)
+
+	loader := options.NewLoader(args[1:], flags)
+	spec, err := loader.Load()
+	if errors.Is(err, pflag.ErrHelp) {
+		return ExitOK
+	}
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return ExitInitFailure
+	}
+
+	if *printConfig != "" {
+		return printSnapshot(loader, *printConfig, stdout, stderr)
+	}
+
+	logLevel := new(slog.LevelVar)
+	logLevel.Set(ParseLogLevel(options.LogLevel(spec)))
+	loader.Subscribe(func(previous, current T) {
+		if level := options.LogLevel(current); level != options.LogLevel(previous) {
+			logLevel.Set(ParseLogLevel(level))
+		}
+	})
+
+	return Main(Options{
+		LogLevel: logLevel,
+		Reload:   loader.Reload,
+		Init: func(ctx context.Context) (Service, error) {
+			return options.Init(ctx, loader, logLevel)
+		},
+	})
+}
+
+func printSnapshot[T any](loader *config.Loader[T], format string, stdout, stderr io.Writer) int {
+	snapshot, err := loader.Snapshot()
+	if err == nil {
+		err = snapshot.Encode(stdout, format)
+	}
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return ExitFailure
+	}
+	return ExitOK
+}
@@ -0,0 +1,77 @@
+package app
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"path/filepath"
+	"testing"
+
+	"github.com/iamnotrodger/golang-projects/pkg/config"
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/require"
+)
+
+type testSpec struct {
+	LogLevel string `mapstructure:"log_level" validate:"oneof=debug info warn error" reload:"true"`
+}
+
+func testConfigOptions(t *testing.T) ConfigOptions[*testSpec] {
+	return ConfigOptions[*testSpec]{
+		NewLoader: func(args []string, flags *pflag.FlagSet) *config.Loader[*testSpec] {
+			return config.NewLoader(func() *testSpec { return &testSpec{LogLevel: "info"} }, config.Options{
+				Args:      args,
+				FlagSet:   flags,
+				EnvFile:   filepath.Join(t.TempDir(), ".env"),
+				LookupEnv: func(string) (string, bool) { return "", false },
+			})
+		},
+		LogLevel: func(spec *testSpec) string { return spec.LogLevel },
+		Init: func(ctx context.Context, loader *config.Loader[*testSpec], logLevel *slog.LevelVar) (Service, error) {
+			t.Fatal("the service is not started")
+			return nil, nil
+		},
+	}
+}
+
+func TestMainWithConfig(t *testing.T) {
+	tests := []struct {
+		name             string
+		args             []string
+		expectedExitCode int
+		expectedStdout   string
+		expectedStderr   string
+	}{
+		{
+			name:             "prints the help",
+			args:             []string{"service", "--help"},
+			expectedExitCode: ExitOK,
+		},
+		{
+			name:             "reports invalid configurations",
+			args:             []string{"service", "--log-level", "verbose"},
+			expectedExitCode: ExitInitFailure,
+			expectedStderr:   "invalid configuration:\n  log_level: must be one of [debug info warn error], got \"verbose\"\n",
+		},
+		{
+			name:             "prints the configuration",
+			args:             []string{"service", "--log-level", "debug", "--print-config"},
+			expectedExitCode: ExitOK,
+			expectedStdout:   "log_level",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+
+			exitCode := mainWithConfig(testConfigOptions(t), tt.args, &stdout, &stderr)
+
+			require.Equal(t, tt.expectedExitCode, exitCode)
+			require.Contains(t, stdout.String(), tt.expectedStdout)
+			if tt.expectedStderr != "" {
+				require.Equal(t, tt.expectedStderr, stderr.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,63 @@
+package app
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// startupLevels groups processes so that every process is in a later level than all of its dependencies.
+func startupLevels(dependencies map[string][]string) ([][]string, error) {
+	names := make([]string, 0, len(dependencies))
+	for name := range dependencies {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	levels := map[string]int{}
+	visiting := map[string]bool{}
+
+	var visit func(name string, path []string) (int, error)
+	visit = func(name string, path []string) (int, error) {
+		if level, ok := levels[name]; ok {
+			return level, nil
+		}
+		if visiting[name] {
+			return 0, fmt.Errorf("process dependency cycle: %s", strings.Join(append(path, name), " -> "))
+		}
+		visiting[name] = true
+
+		level := 0
+		for _, dependency := range dependencies[name] {
+			if _, ok := dependencies[dependency]; !ok {
+				return 0, fmt.Errorf("process %s depends on unknown process %s", name, dependency)
+			}
+			dependencyLevel, err := visit(dependency, append(path, name))
+			if err != nil {
+				return 0, err
+			}
+			level = max(level, dependencyLevel+1)
+		}
+
+		visiting[name] = false
+		levels[name] = level
+		return level, nil
+	}
+
+	var result [][]string
+	for _, name := range names {
+		level, err := visit(name, nil)
+		if err != nil {
+			return nil, err
+		}
+		for len(result) <= level {
+			result = append(result, nil)
+		}
+	}
+
+	for _, name := range names {
+		result[levels[name]] = append(result[levels[name]], name)
+	}
+
+	return result, nil
+}
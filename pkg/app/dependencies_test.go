@@ -0,0 +1,66 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestStartupLevels(t *testing.T) {
+	type testCase struct {
+		name          string
+		dependencies  map[string][]string
+		expected      [][]string
+		expectedError string
+	}
+
+	tests := []testCase{
+		{
+			name: "processes without dependencies share the first level",
+			dependencies: map[string][]string{
+				"b": nil,
+				"a": nil,
+			},
+			expected: [][]string{{"a", "b"}},
+		},
+		{
+			name: "dependents start after their dependencies",
+			dependencies: map[string][]string{
+				"http":     {"consumer", "cache"},
+				"consumer": {"cache"},
+				"cache":    nil,
+				"metrics":  nil,
+			},
+			expected: [][]string{{"cache", "metrics"}, {"consumer"}, {"http"}},
+		},
+		{
+			name: "unknown dependency",
+			dependencies: map[string][]string{
+				"http": {"consumer"},
+			},
+			expectedError: "process http depends on unknown process consumer",
+		},
+		{
+			name: "dependency cycle",
+			dependencies: map[string][]string{
+				"a": {"b"},
+				"b": {"c"},
+				"c": {"a"},
+			},
+			expectedError: "process dependency cycle: a -> b -> c -> a",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			levels, err := startupLevels(tt.dependencies)
+			if tt.expectedError != "" {
+				require.EqualError(t, err, tt.expectedError)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, levels)
+		})
+	}
+}
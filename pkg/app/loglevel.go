@@ -0,0 +1,64 @@
+package app
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strings"
+)
+
+type logLevelBody struct {
+	Level string `json:"level"`
+}
+
+// LogLevelHandler reports the level of the logger on GET and changes it on PUT
+// with a body such as {"level": "debug"}. set applies the new level, such as
+// by setting the configured log level so that it is reported along with the
+// rest of the configuration.
+func LogLevelHandler(level slog.Leveler, set func(slog.Level) error) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+		case http.MethodPut:
+			var body logLevelBody
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				writeLogLevelError(w, "invalid request body: "+err.Error())
+				return
+			}
+
+			var next slog.Level
+			if err := next.UnmarshalText([]byte(body.Level)); err != nil {
+				writeLogLevelError(w, "invalid log level: "+body.Level)
+				return
+			}
+
+			previous := level.Level()
+			if err := set(next); err != nil {
+				writeLogLevelError(w, err.Error())
+				return
+			}
+			slog.Info("log level changed", "previous", previous.String(), "level", next.String())
+		default:
+			w.Header().Set("Allow", "GET, PUT")
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(logLevelBody{Level: strings.ToLower(level.Level().String())})
+	})
+}
+
+// ConfigLogLevel returns the setter of LogLevelHandler changing the log level
+// setting key through set, such as Loader.Set, whose subscribers apply it.
+func ConfigLogLevel(set func(key string, value any) error, key string) func(slog.Level) error {
+	return func(level slog.Level) error {
+		return set(key, strings.ToLower(level.String()))
+	}
+}
+
+func writeLogLevelError(w http.ResponseWriter, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusBadRequest)
+	json.NewEncoder(w).Encode(map[string]string{"error": message})
+}
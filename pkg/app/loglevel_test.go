@@ -0,0 +1,93 @@
+package app
+
+import (
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLogLevelHandler(t *testing.T) {
+	tests := []struct {
+		name           string
+		method         string
+		body           string
+		expectedStatus int
+		expectedBody   string
+		expectedLevel  slog.Level
+	}{
+		{
+			name:           "reports the current level",
+			method:         http.MethodGet,
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"level":"info"}`,
+			expectedLevel:  slog.LevelInfo,
+		},
+		{
+			name:           "changes the level",
+			method:         http.MethodPut,
+			body:           `{"level":"debug"}`,
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"level":"debug"}`,
+			expectedLevel:  slog.LevelDebug,
+		},
+		{
+			name:           "rejects an unknown level",
+			method:         http.MethodPut,
+			body:           `{"level":"verbose"}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":"invalid log level: verbose"}`,
+			expectedLevel:  slog.LevelInfo,
+		},
+		{
+			name:           "rejects an invalid body",
+			method:         http.MethodPut,
+			body:           `debug`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":"invalid request body: invalid character 'd' looking for beginning of value"}`,
+			expectedLevel:  slog.LevelInfo,
+		},
+		{
+			name:           "rejects other methods",
+			method:         http.MethodPost,
+			expectedStatus: http.StatusMethodNotAllowed,
+			expectedLevel:  slog.LevelInfo,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			level := new(slog.LevelVar)
+			w := httptest.NewRecorder()
+
+			set := func(next slog.Level) error {
+				level.Set(next)
+				return nil
+			}
+
+			LogLevelHandler(level, set).ServeHTTP(w, httptest.NewRequest(tt.method, "/admin/log-level", strings.NewReader(tt.body)))
+
+			require.Equal(t, tt.expectedStatus, w.Code)
+			require.Equal(t, tt.expectedLevel, level.Level())
+			if tt.expectedBody != "" {
+				require.JSONEq(t, tt.expectedBody, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestConfigLogLevel(t *testing.T) {
+	var key string
+	var value any
+	set := ConfigLogLevel(func(k string, v any) error {
+		key, value = k, v
+		return nil
+	}, "log_level")
+
+	require.NoError(t, set(slog.LevelWarn))
+	require.Equal(t, "log_level", key)
+	require.Equal(t, "warn", value)
+}
@@ -0,0 +1,226 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"slices"
+	"syscall"
+	"time"
+
+	"github.com/iamnotrodger/golang-projects/pkg/telemetry"
+)
+
+const (
+	ExitOK = iota
+	ExitFailure
+	ExitInitFailure
+	ExitShutdownTimeout
+	ExitForced
+)
+
+const DefaultGracePeriod = 30 * time.Second
+
+// Service is the dependency container of a binary, it builds the processes and
+// releases shared resources once they have all stopped.
+type Service interface {
+	Processes() map[string]Runnable
+	Shutdown(ctx context.Context) error
+}
+
+// Reloader is implemented by processes that can apply configuration changes without a restart.
+type Reloader interface {
+	Reload(ctx context.Context) error
+}
+
+type Options struct {
+	// LogLevel is the level of the default logger, a slog.LevelVar allows changing it at runtime.
+	LogLevel slog.Leveler
+	// GracePeriod bounds the time spent stopping processes and shutting down the service.
+	GracePeriod time.Duration
+	Init        func(ctx context.Context) (Service, error)
+	// Reload is called on SIGHUP before the reload is delivered to the processes.
+	Reload func() error
+}
+
+// Main runs the service until it receives SIGINT or SIGTERM or a critical process
+// fails, and returns the exit code. SIGHUP triggers a reload, and a second
+// termination signal while shutting down forces the exit.
+func Main(options Options) int {
+	level := options.LogLevel
+	if level == nil {
+		level = slog.LevelInfo
+	}
+	slog.SetDefault(NewLogger(os.Stdout, level))
+
+	sigs := make(chan os.Signal, 2)
+	signal.Notify(sigs, syscall.SIGINT, syscall.SIGTERM, syscall.SIGHUP)
+	defer signal.Stop(sigs)
+
+	return run(options, sigs)
+}
+
+func run(options Options, sigs <-chan os.Signal) int {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	service, err := options.Init(ctx)
+	if err != nil {
+		slog.Error("failed to initialize application", "error", err.Error())
+		return ExitInitFailure
+	}
+
+	application := NewApplication(service.Processes())
+	errChan := application.Run(ctx)
+
+	exitCode := waitForTermination(ctx, options, application, errChan, sigs)
+
+	cancel()
+	if shutdownCode := waitForShutdown(options, application, service, sigs); shutdownCode != ExitOK {
+		return shutdownCode
+	}
+
+	return exitCode
+}
+
+func waitForTermination(ctx context.Context, options Options, application *Application, errChan <-chan error, sigs <-chan os.Signal) int {
+	for {
+		select {
+		case err := <-errChan:
+			if isCleanExit(err) {
+				slog.Info("application stopped")
+				return ExitOK
+			}
+			slog.Error("application error", "error", err.Error())
+			return ExitFailure
+		case sig := <-sigs:
+			if sig == syscall.SIGHUP {
+				reload(ctx, options, application)
+				continue
+			}
+			slog.Info("received signal, shutting down", "signal", sig.String())
+			return ExitOK
+		}
+	}
+}
+
+func waitForShutdown(options Options, application *Application, service Service, sigs <-chan os.Signal) int {
+	slog.Info("waiting for shutdown to complete")
+
+	gracePeriod := options.GracePeriod
+	if gracePeriod <= 0 {
+		gracePeriod = DefaultGracePeriod
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), gracePeriod)
+	defer cancel()
+
+	done := make(chan int, 1)
+	go func() {
+		exitCode := ExitOK
+		if err := application.Shutdown(); err != nil {
+			exitCode = ExitShutdownTimeout
+		}
+		if err := service.Shutdown(ctx); err != nil {
+			slog.Error("service shutdown failed", "error", err.Error())
+			exitCode = max(exitCode, ExitFailure)
+		}
+		done <- exitCode
+	}()
+
+	for {
+		select {
+		case exitCode := <-done:
+			if exitCode == ExitOK {
+				slog.Info("application shutdown successful")
+			}
+			return exitCode
+		case <-ctx.Done():
+			slog.Warn("ungraceful shutdown timeout reached", "grace_period", gracePeriod)
+			return ExitShutdownTimeout
+		case sig := <-sigs:
+			if sig == syscall.SIGHUP {
+				continue
+			}
+			slog.Warn("received second signal, forcing shutdown", "signal", sig.String())
+			return ExitForced
+		}
+	}
+}
+
+func reload(ctx context.Context, options Options, application *Application) {
+	slog.Info("reloading configuration")
+
+	if options.Reload != nil {
+		if err := options.Reload(); err != nil {
+			slog.Error("failed to reload configuration", "error", err.Error())
+			return
+		}
+	}
+
+	if err := application.Reload(ctx); err != nil {
+		slog.Error("failed to reload processes", "error", err.Error())
+	}
+}
+
+// Reload delivers a configuration reload to every process implementing Reloader.
+func (app *Application) Reload(ctx context.Context) error {
+	names := make([]string, 0, len(app.supervisors))
+	for name := range app.supervisors {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	var errs []error
+	for _, name := range names {
+		reloader, ok := app.supervisors[name].process.(Reloader)
+		if !ok {
+			continue
+		}
+		if err := reloader.Reload(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("reload %s: %w", name, err))
+			continue
+		}
+		slog.Info("process reloaded", "name", name)
+	}
+
+	return errors.Join(errs...)
+}
+
+func isCleanExit(err error) bool {
+	return err == nil || errors.Is(err, http.ErrServerClosed) || errors.Is(err, context.Canceled)
+}
+
+// NewLogger creates a JSON logger adding the trace and request ids of the
+// context to the records.
+func NewLogger(w io.Writer, level slog.Leveler) *slog.Logger {
+	return slog.New(telemetry.NewLogHandler(slog.NewJSONHandler(w, &slog.HandlerOptions{
+		Level: level,
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if a.Key == slog.TimeKey {
+				a.Key = "timestamp"
+			}
+			return a
+		},
+	})))
+}
+
+func ParseLogLevel(level string) slog.Level {
+	switch level {
+	case "debug":
+		return slog.LevelDebug
+	case "info":
+		return slog.LevelInfo
+	case "warn":
+		return slog.LevelWarn
+	case "error":
+		return slog.LevelError
+	default:
+		return slog.LevelInfo
+	}
+}
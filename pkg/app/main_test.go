@@ -0,0 +1,204 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"os"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type MockService struct {
+	processes   map[string]Runnable
+	shutdownErr error
+	isShutdown  atomic.Bool
+}
+
+func (s *MockService) Processes() map[string]Runnable {
+	return s.processes
+}
+
+func (s *MockService) Shutdown(ctx context.Context) error {
+	s.isShutdown.Store(true)
+	return s.shutdownErr
+}
+
+type ReloadRunnable struct {
+	reloads atomic.Int32
+}
+
+func (r *ReloadRunnable) Run(ctx context.Context, errChan chan error) {
+	<-ctx.Done()
+}
+
+func (r *ReloadRunnable) Reload(ctx context.Context) error {
+	r.reloads.Add(1)
+	return nil
+}
+
+func errorRunnable(err error) FuncRunnable {
+	return func(ctx context.Context, errChan chan error) {
+		errChan <- err
+		<-ctx.Done()
+	}
+}
+
+func runWithOptions(options Options) (chan os.Signal, chan int) {
+	sigs := make(chan os.Signal, 2)
+	exitCode := make(chan int, 1)
+	go func() {
+		exitCode <- run(options, sigs)
+	}()
+	return sigs, exitCode
+}
+
+func optionsFor(service Service) Options {
+	return Options{
+		GracePeriod: time.Second,
+		Init: func(ctx context.Context) (Service, error) {
+			return service, nil
+		},
+	}
+}
+
+func TestRun(t *testing.T) {
+	type testCase struct {
+		name             string
+		service          *MockService
+		signal           os.Signal
+		expectedExitCode int
+	}
+
+	tests := []testCase{
+		{
+			name: "terminates cleanly on signal",
+			service: &MockService{
+				processes: map[string]Runnable{"p1": &ReloadRunnable{}},
+			},
+			signal:           syscall.SIGTERM,
+			expectedExitCode: ExitOK,
+		},
+		{
+			name: "fails when a critical process fails",
+			service: &MockService{
+				processes: map[string]Runnable{"p1": errorRunnable(errors.New("listen failed"))},
+			},
+			expectedExitCode: ExitFailure,
+		},
+		{
+			name: "closed http server is a clean exit",
+			service: &MockService{
+				processes: map[string]Runnable{"p1": errorRunnable(http.ErrServerClosed)},
+			},
+			expectedExitCode: ExitOK,
+		},
+		{
+			name: "fails when service shutdown fails",
+			service: &MockService{
+				processes:   map[string]Runnable{"p1": &ReloadRunnable{}},
+				shutdownErr: errors.New("close failed"),
+			},
+			signal:           syscall.SIGINT,
+			expectedExitCode: ExitFailure,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sigs, exitCode := runWithOptions(optionsFor(tt.service))
+			if tt.signal != nil {
+				sigs <- tt.signal
+			}
+
+			select {
+			case code := <-exitCode:
+				require.Equal(t, tt.expectedExitCode, code)
+			case <-time.After(time.Second):
+				t.Fatal("application did not exit")
+			}
+			require.True(t, tt.service.isShutdown.Load())
+		})
+	}
+}
+
+func TestRun_InitFailure(t *testing.T) {
+	code := run(Options{
+		Init: func(ctx context.Context) (Service, error) {
+			return nil, errors.New("database unavailable")
+		},
+	}, make(chan os.Signal))
+
+	require.Equal(t, ExitInitFailure, code)
+}
+
+func TestRun_Reload(t *testing.T) {
+	process := &ReloadRunnable{}
+	configReloads := atomic.Int32{}
+
+	options := optionsFor(&MockService{processes: map[string]Runnable{"p1": process}})
+	options.Reload = func() error {
+		configReloads.Add(1)
+		return nil
+	}
+
+	sigs, exitCode := runWithOptions(options)
+	sigs <- syscall.SIGHUP
+
+	require.Eventually(t, func() bool { return process.reloads.Load() == 1 }, time.Second, time.Millisecond)
+	require.Equal(t, int32(1), configReloads.Load())
+
+	sigs <- syscall.SIGTERM
+	require.Equal(t, ExitOK, <-exitCode)
+}
+
+func TestRun_ForceQuit(t *testing.T) {
+	hung := FuncRunnable(func(ctx context.Context, errChan chan error) {
+		select {}
+	})
+
+	options := optionsFor(&MockService{
+		processes: map[string]Runnable{"p1": Supervise(hung, ProcessOptions{ShutdownTimeout: time.Minute})},
+	})
+	options.GracePeriod = time.Minute
+
+	sigs, exitCode := runWithOptions(options)
+	sigs <- syscall.SIGTERM
+	sigs <- syscall.SIGINT
+
+	select {
+	case code := <-exitCode:
+		require.Equal(t, ExitForced, code)
+	case <-time.After(time.Second):
+		t.Fatal("second signal did not force the exit")
+	}
+}
+
+func TestRun_GracePeriod(t *testing.T) {
+	hung := FuncRunnable(func(ctx context.Context, errChan chan error) {
+		select {}
+	})
+
+	options := optionsFor(&MockService{
+		processes: map[string]Runnable{"p1": Supervise(hung, ProcessOptions{ShutdownTimeout: time.Minute})},
+	})
+	options.GracePeriod = 10 * time.Millisecond
+
+	sigs, exitCode := runWithOptions(options)
+	sigs <- syscall.SIGTERM
+
+	require.Equal(t, ExitShutdownTimeout, <-exitCode)
+}
+
+func TestParseLogLevel(t *testing.T) {
+	require.Equal(t, slog.LevelDebug, ParseLogLevel("debug"))
+	require.Equal(t, slog.LevelInfo, ParseLogLevel("info"))
+	require.Equal(t, slog.LevelWarn, ParseLogLevel("warn"))
+	require.Equal(t, slog.LevelError, ParseLogLevel("error"))
+	require.Equal(t, slog.LevelInfo, ParseLogLevel("unknown"))
+}
@@ -0,0 +1,278 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math"
+	"math/rand/v2"
+	"sync"
+	"time"
+)
+
+type RestartPolicy int
+
+const (
+	RestartNever RestartPolicy = iota
+	RestartOnFailure
+	RestartAlways
+)
+
+func (p RestartPolicy) String() string {
+	switch p {
+	case RestartOnFailure:
+		return "on-failure"
+	case RestartAlways:
+		return "always"
+	default:
+		return "never"
+	}
+}
+
+type Backoff struct {
+	Initial    time.Duration
+	Max        time.Duration
+	Multiplier float64
+	// Jitter is the fraction of the delay that is randomly added or removed.
+	Jitter float64
+}
+
+var DefaultBackoff = Backoff{
+	Initial:    500 * time.Millisecond,
+	Max:        30 * time.Second,
+	Multiplier: 2,
+	Jitter:     0.2,
+}
+
+func (b Backoff) Delay(attempt int) time.Duration {
+	delay := float64(b.Initial) * math.Pow(b.Multiplier, float64(attempt))
+	if b.Max > 0 && delay > float64(b.Max) {
+		delay = float64(b.Max)
+	}
+	if b.Jitter > 0 {
+		delay += delay * b.Jitter * (2*rand.Float64() - 1)
+	}
+	return time.Duration(delay)
+}
+
+type ProcessOptions struct {
+	Restart RestartPolicy
+	Backoff Backoff
+	// MaxRestarts is the number of restarts allowed within Window, zero means unlimited.
+	MaxRestarts int
+	Window      time.Duration
+	// Critical processes stop the application once they fail and will not be restarted again.
+	Critical bool
+	// DependsOn lists the processes that must be ready before this process starts,
+	// they are stopped only after this process has stopped.
+	DependsOn       []string
+	ShutdownTimeout time.Duration
+}
+
+// Process attaches supervision options to a Runnable. Runnables that are not
+// wrapped are treated as critical and are never restarted.
+type Process struct {
+	Runnable
+	Options ProcessOptions
+}
+
+func Supervise(runnable Runnable, options ProcessOptions) *Process {
+	if options.Backoff == (Backoff{}) {
+		options.Backoff = DefaultBackoff
+	}
+	if options.Backoff.Multiplier < 1 {
+		options.Backoff.Multiplier = 1
+	}
+
+	return &Process{
+		Runnable: runnable,
+		Options:  options,
+	}
+}
+
+type supervisor struct {
+	name      string
+	process   Runnable
+	options   ProcessOptions
+	restarts  []time.Time
+	ctx       context.Context
+	cancel    context.CancelFunc
+	done      chan struct{}
+	ready     chan struct{}
+	readyOnce sync.Once
+}
+
+func newSupervisor(name string, runnable Runnable) *supervisor {
+	s := &supervisor{
+		name:    name,
+		process: runnable,
+		options: ProcessOptions{Restart: RestartNever, Critical: true},
+		done:    make(chan struct{}),
+		ready:   make(chan struct{}),
+	}
+
+	if process, ok := runnable.(*Process); ok {
+		s.process = process.Runnable
+		s.options = process.Options
+	}
+
+	return s
+}
+
+func (s *supervisor) shutdownTimeout() time.Duration {
+	if s.options.ShutdownTimeout > 0 {
+		return s.options.ShutdownTimeout
+	}
+	return DefaultShutdownTimeout
+}
+
+// awaitReady marks the process as ready once it signals readiness, processes that
+// do not implement Readier are ready as soon as they are started.
+func (s *supervisor) awaitReady(ctx context.Context) {
+	readier, ok := s.process.(Readier)
+	if !ok {
+		s.markReady()
+		return
+	}
+
+	go func() {
+		select {
+		case <-ctx.Done():
+		case <-readier.Ready():
+			slog.Info("process ready", "name", s.name)
+			s.markReady()
+		}
+	}()
+}
+
+func (s *supervisor) markReady() {
+	s.readyOnce.Do(func() {
+		close(s.ready)
+	})
+}
+
+func (s *supervisor) run(ctx context.Context, errChan chan error) {
+	for {
+		err := s.runOnce(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+
+		if !s.shouldRestart(err) {
+			s.fail(ctx, errChan, err)
+			return
+		}
+
+		if !s.allowRestart(time.Now()) {
+			s.fail(ctx, errChan, fmt.Errorf("process %s exceeded %d restarts in %s: %w", s.name, s.options.MaxRestarts, s.options.Window, err))
+			return
+		}
+
+		delay := s.options.Backoff.Delay(len(s.restarts) - 1)
+		slog.Warn("restarting process", "name", s.name, "error", errString(err), "restarts", len(s.restarts), "delay", delay)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(delay):
+		}
+	}
+}
+
+// runOnce runs the process until it exits and returns the first error it reported.
+// The process is stopped as soon as it reports an error.
+func (s *supervisor) runOnce(ctx context.Context) error {
+	runCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	errs := make(chan error)
+	done := make(chan struct{})
+
+	slog.Info("starting process", "name", s.name)
+	go func() {
+		defer close(done)
+		s.process.Run(runCtx, errs)
+	}()
+	s.awaitReady(runCtx)
+
+	var failure error
+	for {
+		select {
+		case err := <-errs:
+			if err == nil || runCtx.Err() != nil {
+				continue
+			}
+			failure = err
+			cancel()
+		case <-done:
+			slog.Info("process stopped", "name", s.name)
+			go discardErrors(ctx, errs)
+			return failure
+		}
+	}
+}
+
+func (s *supervisor) shouldRestart(err error) bool {
+	switch s.options.Restart {
+	case RestartAlways:
+		return true
+	case RestartOnFailure:
+		return err != nil
+	default:
+		return false
+	}
+}
+
+func (s *supervisor) allowRestart(now time.Time) bool {
+	if s.options.Window > 0 {
+		recent := s.restarts[:0]
+		for _, restart := range s.restarts {
+			if now.Sub(restart) < s.options.Window {
+				recent = append(recent, restart)
+			}
+		}
+		s.restarts = recent
+	}
+
+	if s.options.MaxRestarts > 0 && len(s.restarts) >= s.options.MaxRestarts {
+		return false
+	}
+
+	s.restarts = append(s.restarts, now)
+	return true
+}
+
+func (s *supervisor) fail(ctx context.Context, errChan chan error, err error) {
+	if err == nil {
+		slog.Info("process exited", "name", s.name)
+		return
+	}
+
+	if !s.options.Critical {
+		slog.Error("process failed and will not be restarted", "name", s.name, "error", err.Error())
+		return
+	}
+
+	select {
+	case errChan <- err:
+	case <-ctx.Done():
+	}
+}
+
+// discardErrors drains errors that a process sends after it has returned from Run.
+func discardErrors(ctx context.Context, errs chan error) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-errs:
+		}
+	}
+}
+
+func errString(err error) string {
+	if err == nil {
+		return ""
+	}
+	return err.Error()
+}
@@ -0,0 +1,154 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type FuncRunnable func(ctx context.Context, errChan chan error)
+
+func (f FuncRunnable) Run(ctx context.Context, errChan chan error) {
+	f(ctx, errChan)
+}
+
+func failingRunnable(runs *atomic.Int32, err error) FuncRunnable {
+	return func(ctx context.Context, errChan chan error) {
+		runs.Add(1)
+		go func() {
+			errChan <- err
+		}()
+		<-ctx.Done()
+	}
+}
+
+var fastBackoff = Backoff{Initial: time.Millisecond, Max: 5 * time.Millisecond, Multiplier: 2}
+
+func TestBackoff_Delay(t *testing.T) {
+	backoff := Backoff{Initial: 100 * time.Millisecond, Max: time.Second, Multiplier: 2}
+
+	require.Equal(t, 100*time.Millisecond, backoff.Delay(0))
+	require.Equal(t, 200*time.Millisecond, backoff.Delay(1))
+	require.Equal(t, 800*time.Millisecond, backoff.Delay(3))
+	require.Equal(t, time.Second, backoff.Delay(10))
+
+	backoff.Jitter = 0.5
+	for range 100 {
+		delay := backoff.Delay(0)
+		require.GreaterOrEqual(t, delay, 50*time.Millisecond)
+		require.LessOrEqual(t, delay, 150*time.Millisecond)
+	}
+}
+
+func TestSupervise(t *testing.T) {
+	process := Supervise(FuncRunnable(nil), ProcessOptions{Restart: RestartAlways})
+	require.Equal(t, DefaultBackoff, process.Options.Backoff)
+
+	process = Supervise(FuncRunnable(nil), ProcessOptions{Backoff: Backoff{Initial: time.Second}})
+	require.Equal(t, 1.0, process.Options.Backoff.Multiplier)
+}
+
+func TestApplication_Supervision(t *testing.T) {
+	type testCase struct {
+		name          string
+		options       *ProcessOptions
+		runErr        error
+		expectedRuns  int32
+		expectedError bool
+	}
+
+	runErr := errors.New("connection lost")
+
+	tests := []testCase{
+		{
+			name:          "unsupervised process failure stops the application",
+			options:       nil,
+			runErr:        runErr,
+			expectedRuns:  1,
+			expectedError: true,
+		},
+		{
+			name: "critical process is restarted until the restart limit is reached",
+			options: &ProcessOptions{
+				Restart:     RestartOnFailure,
+				Backoff:     fastBackoff,
+				MaxRestarts: 3,
+				Window:      time.Minute,
+				Critical:    true,
+			},
+			runErr:        runErr,
+			expectedRuns:  4,
+			expectedError: true,
+		},
+		{
+			name: "non-critical process failure does not stop the application",
+			options: &ProcessOptions{
+				Restart:     RestartOnFailure,
+				Backoff:     fastBackoff,
+				MaxRestarts: 2,
+				Window:      time.Minute,
+				Critical:    false,
+			},
+			runErr:        runErr,
+			expectedRuns:  3,
+			expectedError: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runs := &atomic.Int32{}
+			var process Runnable = failingRunnable(runs, tt.runErr)
+			if tt.options != nil {
+				process = Supervise(process, *tt.options)
+			}
+
+			ctx, cancel := context.WithCancel(context.Background())
+			application := NewApplication(map[string]Runnable{"p1": process})
+			errChan := application.Run(ctx)
+
+			select {
+			case err := <-errChan:
+				require.True(t, tt.expectedError, "unexpected error: %v", err)
+				require.ErrorIs(t, err, tt.runErr)
+			case <-time.After(200 * time.Millisecond):
+				require.False(t, tt.expectedError, "expected application error")
+			}
+
+			cancel()
+			application.Shutdown()
+
+			require.Equal(t, tt.expectedRuns, runs.Load())
+		})
+	}
+}
+
+func TestApplication_RestartAlways(t *testing.T) {
+	runs := &atomic.Int32{}
+	process := Supervise(FuncRunnable(func(ctx context.Context, errChan chan error) {
+		runs.Add(1)
+	}), ProcessOptions{Restart: RestartAlways, Backoff: fastBackoff})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	application := NewApplication(map[string]Runnable{"p1": process})
+	application.Run(ctx)
+
+	require.Eventually(t, func() bool { return runs.Load() >= 3 }, time.Second, time.Millisecond)
+
+	cancel()
+	application.Shutdown()
+}
+
+func TestSupervisor_AllowRestart(t *testing.T) {
+	s := &supervisor{options: ProcessOptions{MaxRestarts: 2, Window: time.Minute}}
+	now := time.Now()
+
+	require.True(t, s.allowRestart(now))
+	require.True(t, s.allowRestart(now.Add(time.Second)))
+	require.False(t, s.allowRestart(now.Add(2*time.Second)))
+	require.True(t, s.allowRestart(now.Add(time.Minute+time.Second)))
+}
@@ -0,0 +1,73 @@
+package auth
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+)
+
+const HeaderAPIKey = "X-API-Key"
+
+// APIKey is a stored API key, only the hash of the key is kept.
+type APIKey struct {
+	// Hash is the hex encoded SHA-256 of the key, see HashAPIKey.
+	Hash    string   `json:"hash"`
+	Subject string   `json:"subject"`
+	Scopes  []string `json:"scopes"`
+}
+
+// HashAPIKey returns the hash under which key is stored. API keys are random
+// and long, so an unsalted hash is enough to keep them secret.
+func HashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// APIKeys authenticates the requests carrying one of its keys in the X-API-Key header.
+type APIKeys struct {
+	keys map[string]APIKey
+}
+
+func NewAPIKeys(keys []APIKey) (*APIKeys, error) {
+	a := &APIKeys{keys: make(map[string]APIKey, len(keys))}
+	for i, key := range keys {
+		if decoded, err := hex.DecodeString(key.Hash); err != nil || len(decoded) != sha256.Size {
+			return nil, fmt.Errorf("api key %d: hash must be a hex encoded SHA-256", i)
+		}
+		if key.Subject == "" {
+			return nil, fmt.Errorf("api key %d: subject is required", i)
+		}
+		a.keys[key.Hash] = key
+	}
+	return a, nil
+}
+
+// LoadAPIKeys reads the keys from a JSON array of APIKey.
+func LoadAPIKeys(path string) (*APIKeys, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read api keys: %w", err)
+	}
+	var keys []APIKey
+	if err := json.Unmarshal(data, &keys); err != nil {
+		return nil, fmt.Errorf("decode api keys %s: %w", path, err)
+	}
+	return NewAPIKeys(keys)
+}
+
+func (a *APIKeys) Authenticate(r *http.Request) (Principal, error) {
+	presented := r.Header.Get(HeaderAPIKey)
+	if presented == "" {
+		return Principal{}, ErrNoCredentials
+	}
+
+	// The lookup is keyed by hash, so its timing reveals nothing about the stored keys.
+	key, ok := a.keys[HashAPIKey(presented)]
+	if !ok {
+		return Principal{}, ErrInvalidCredentials
+	}
+	return Principal{Subject: key.Subject, Method: MethodAPIKey, Scopes: key.Scopes}, nil
+}
@@ -0,0 +1,67 @@
+package auth
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAPIKeys_Authenticate(t *testing.T) {
+	apiKeys, err := NewAPIKeys([]APIKey{
+		{Hash: HashAPIKey("secret-key"), Subject: "game-server", Scopes: []string{"scores:write"}},
+	})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name              string
+		key               string
+		expectedPrincipal Principal
+		expectedError     error
+	}{
+		{
+			name:              "authenticates known keys",
+			key:               "secret-key",
+			expectedPrincipal: Principal{Subject: "game-server", Method: MethodAPIKey, Scopes: []string{"scores:write"}},
+		},
+		{
+			name:          "rejects unknown keys",
+			key:           "other-key",
+			expectedError: ErrInvalidCredentials,
+		},
+		{
+			name:          "skips requests without a key",
+			expectedError: ErrNoCredentials,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/score/", nil)
+			if tt.key != "" {
+				req.Header.Set(HeaderAPIKey, tt.key)
+			}
+
+			principal, err := apiKeys.Authenticate(req)
+
+			require.ErrorIs(t, err, tt.expectedError)
+			require.Equal(t, tt.expectedPrincipal, principal)
+		})
+	}
+}
+
+func TestLoadAPIKeys(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "api_keys.json")
+	require.NoError(t, os.WriteFile(path, []byte(`[{"hash":"`+HashAPIKey("secret-key")+`","subject":"game-server"}]`), 0o600))
+
+	apiKeys, err := LoadAPIKeys(path)
+	require.NoError(t, err)
+	require.Contains(t, apiKeys.keys, HashAPIKey("secret-key"))
+
+	require.NoError(t, os.WriteFile(path, []byte(`[{"hash":"secret-key","subject":"game-server"}]`), 0o600))
+	_, err = LoadAPIKeys(path)
+	require.EqualError(t, err, "api key 0: hash must be a hex encoded SHA-256")
+}
@@ -0,0 +1,78 @@
+package auth
+
+import (
+	"errors"
+	"time"
+)
+
+const (
+	AlgorithmHS256 = "HS256"
+	AlgorithmRS256 = "RS256"
+)
+
+type Config struct {
+	// Enabled requires the write endpoints to be authenticated.
+	Enabled bool `mapstructure:"enabled"`
+	// APIKeysFile is a JSON array of APIKey.
+	APIKeysFile string    `mapstructure:"api_keys_file"`
+	JWT         JWTConfig `mapstructure:"jwt"`
+}
+
+type JWTConfig struct {
+	Algorithms []string `mapstructure:"algorithms" validate:"min=1,dive,oneof=HS256 RS256"`
+	// Secret verifies the HS256 tokens without a kid.
+	Secret   string `mapstructure:"secret" json:"-"`
+	JWKSFile string `mapstructure:"jwks_file"`
+	JWKSURL  string `mapstructure:"jwks_url" validate:"omitempty,url"`
+	// JWKSRefresh is the age after which the keys fetched from JWKSURL are refreshed.
+	JWKSRefresh time.Duration `mapstructure:"jwks_refresh" validate:"min=0"`
+	Issuer      string        `mapstructure:"issuer"`
+	Audience    string        `mapstructure:"audience"`
+	Leeway      time.Duration `mapstructure:"leeway" validate:"min=0"`
+}
+
+func NewConfig() Config {
+	return Config{
+		JWT: JWTConfig{
+			Algorithms:  []string{AlgorithmHS256, AlgorithmRS256},
+			JWKSRefresh: time.Hour,
+			Leeway:      30 * time.Second,
+		},
+	}
+}
+
+// NewAuthenticator returns the authenticators of the configured credentials,
+// or nil when authentication is disabled.
+func (c Config) NewAuthenticator() (Authenticator, error) {
+	if !c.Enabled {
+		return nil, nil
+	}
+
+	var chain Chain
+	if c.APIKeysFile != "" {
+		apiKeys, err := LoadAPIKeys(c.APIKeysFile)
+		if err != nil {
+			return nil, err
+		}
+		chain = append(chain, apiKeys)
+	}
+
+	var keys *KeySet
+	switch {
+	case c.JWT.JWKSFile != "":
+		var err error
+		if keys, err = LoadKeySet(c.JWT.JWKSFile); err != nil {
+			return nil, err
+		}
+	case c.JWT.JWKSURL != "":
+		keys = NewRemoteKeySet(c.JWT.JWKSURL, c.JWT.JWKSRefresh, nil)
+	}
+	if keys != nil || c.JWT.Secret != "" {
+		chain = append(chain, NewJWT(c.JWT, keys))
+	}
+
+	if len(chain) == 0 {
+		return nil, errors.New("authentication is enabled without api keys, jwt secret or jwks")
+	}
+	return chain, nil
+}
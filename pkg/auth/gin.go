@@ -0,0 +1,43 @@
+package auth
+
+import (
+	"errors"
+
+	"github.com/gin-gonic/gin"
+	"github.com/iamnotrodger/golang-projects/pkg/apierror"
+)
+
+// Require authenticates the requests and checks that their principal has all
+// of scopes, the principal is added to the request context. A nil
+// authenticator disables authentication.
+func Require(authenticator Authenticator, scopes ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if authenticator == nil {
+			c.Next()
+			return
+		}
+
+		principal, err := authenticator.Authenticate(c.Request)
+		if err != nil {
+			detail := "invalid credentials"
+			if errors.Is(err, ErrNoCredentials) {
+				detail = "authentication is required"
+			}
+			c.Header("WWW-Authenticate", "Bearer")
+			c.Error(apierror.Unauthorized(detail, err))
+			c.Abort()
+			return
+		}
+
+		for _, scope := range scopes {
+			if !principal.HasScope(scope) {
+				c.Error(apierror.Forbidden("missing scope "+scope, nil))
+				c.Abort()
+				return
+			}
+		}
+
+		c.Request = c.Request.WithContext(NewContext(c.Request.Context(), principal))
+		c.Next()
+	}
+}
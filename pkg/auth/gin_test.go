@@ -0,0 +1,100 @@
+package auth
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/iamnotrodger/golang-projects/pkg/middleware"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequire(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	apiKeys, err := NewAPIKeys([]APIKey{
+		{Hash: HashAPIKey("writer-key"), Subject: "game-server", Scopes: []string{"scores:write"}},
+		{Hash: HashAPIKey("reader-key"), Subject: "dashboard", Scopes: []string{"scores:read"}},
+	})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name            string
+		authenticator   Authenticator
+		key             string
+		expectedStatus  int
+		expectedSubject string
+	}{
+		{
+			name:            "adds the principal to the context",
+			authenticator:   apiKeys,
+			key:             "writer-key",
+			expectedStatus:  http.StatusOK,
+			expectedSubject: "game-server",
+		},
+		{
+			name:           "rejects requests without credentials",
+			authenticator:  apiKeys,
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "rejects invalid credentials",
+			authenticator:  apiKeys,
+			key:            "unknown-key",
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "rejects principals missing a scope",
+			authenticator:  apiKeys,
+			key:            "reader-key",
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "allows every request when disabled",
+			expectedStatus: http.StatusOK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var subject string
+			engine := gin.New()
+			engine.Use(middleware.Errors())
+			engine.POST("/score/", Require(tt.authenticator, "scores:write"), func(c *gin.Context) {
+				principal, _ := FromContext(c.Request.Context())
+				subject = principal.Subject
+				c.Status(http.StatusOK)
+			})
+
+			req := httptest.NewRequest(http.MethodPost, "/score/", nil)
+			if tt.key != "" {
+				req.Header.Set(HeaderAPIKey, tt.key)
+			}
+			recorder := httptest.NewRecorder()
+			engine.ServeHTTP(recorder, req)
+
+			require.Equal(t, tt.expectedStatus, recorder.Code)
+			require.Equal(t, tt.expectedSubject, subject)
+			if tt.expectedStatus == http.StatusUnauthorized {
+				require.Equal(t, "Bearer", recorder.Header().Get("WWW-Authenticate"))
+			}
+		})
+	}
+}
+
+func TestConfig_NewAuthenticator(t *testing.T) {
+	authenticator, err := NewConfig().NewAuthenticator()
+	require.NoError(t, err)
+	require.Nil(t, authenticator)
+
+	config := NewConfig()
+	config.Enabled = true
+	_, err = config.NewAuthenticator()
+	require.EqualError(t, err, "authentication is enabled without api keys, jwt secret or jwks")
+
+	config.JWT.Secret = "shared-secret"
+	authenticator, err = config.NewAuthenticator()
+	require.NoError(t, err)
+	require.Len(t, authenticator, 1)
+}
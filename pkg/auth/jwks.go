@@ -0,0 +1,184 @@
+package auth
+
+import (
+	"context"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+)
+
+// minJWKSRefresh bounds how often unknown key ids trigger a fetch of the JWKS.
+const minJWKSRefresh = time.Minute
+
+var ErrUnknownKey = errors.New("unknown signing key")
+
+// JWK is a JSON Web Key, RSA and symmetric keys are supported.
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Alg string `json:"alg,omitempty"`
+	Use string `json:"use,omitempty"`
+	// N and E are the modulus and exponent of RSA keys.
+	N string `json:"n,omitempty"`
+	E string `json:"e,omitempty"`
+	// K is the value of symmetric keys.
+	K string `json:"k,omitempty"`
+}
+
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+// verificationKey returns the *rsa.PublicKey or []byte verifying the signatures of k.
+func (k JWK) verificationKey() (any, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := base64.RawURLEncoding.DecodeString(k.N)
+		if err != nil {
+			return nil, fmt.Errorf("decode modulus of key %q: %w", k.Kid, err)
+		}
+		e, err := base64.RawURLEncoding.DecodeString(k.E)
+		if err != nil {
+			return nil, fmt.Errorf("decode exponent of key %q: %w", k.Kid, err)
+		}
+		return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}, nil
+	case "oct":
+		key, err := base64.RawURLEncoding.DecodeString(k.K)
+		if err != nil {
+			return nil, fmt.Errorf("decode key %q: %w", k.Kid, err)
+		}
+		return key, nil
+	default:
+		return nil, fmt.Errorf("unsupported key type %q of key %q", k.Kty, k.Kid)
+	}
+}
+
+// KeySet holds the keys of a JWKS read from a file or fetched from a URL. Remote
+// sets are fetched on first use, refreshed once they are older than the refresh
+// interval and when a token is signed with an unknown key.
+type KeySet struct {
+	url        string
+	refresh    time.Duration
+	minRefresh time.Duration
+	client     *http.Client
+
+	mu        sync.RWMutex
+	keys      map[string]any
+	fetchedAt time.Time
+}
+
+func NewKeySet(jwks JWKS) (*KeySet, error) {
+	keys, err := parseJWKS(jwks)
+	if err != nil {
+		return nil, err
+	}
+	return &KeySet{keys: keys}, nil
+}
+
+func LoadKeySet(path string) (*KeySet, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read jwks: %w", err)
+	}
+	var jwks JWKS
+	if err := json.Unmarshal(data, &jwks); err != nil {
+		return nil, fmt.Errorf("decode jwks %s: %w", path, err)
+	}
+	return NewKeySet(jwks)
+}
+
+func NewRemoteKeySet(url string, refresh time.Duration, client *http.Client) *KeySet {
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+	return &KeySet{url: url, refresh: refresh, minRefresh: minJWKSRefresh, client: client, keys: map[string]any{}}
+}
+
+// Key returns the key with the given id. Known keys are still returned when
+// the JWKS cannot be refreshed.
+func (s *KeySet) Key(ctx context.Context, kid string) (any, error) {
+	s.mu.RLock()
+	key, ok := s.keys[kid]
+	fetchedAt := s.fetchedAt
+	s.mu.RUnlock()
+
+	if s.url != "" && s.needsFetch(ok, fetchedAt) {
+		if err := s.fetch(ctx); err != nil && !ok {
+			return nil, err
+		}
+		s.mu.RLock()
+		key, ok = s.keys[kid]
+		s.mu.RUnlock()
+	}
+
+	if !ok {
+		return nil, fmt.Errorf("%w %q", ErrUnknownKey, kid)
+	}
+	return key, nil
+}
+
+func (s *KeySet) needsFetch(known bool, fetchedAt time.Time) bool {
+	age := time.Since(fetchedAt)
+	switch {
+	case fetchedAt.IsZero():
+		return true
+	case known:
+		return s.refresh > 0 && age > s.refresh
+	default:
+		return age > s.minRefresh
+	}
+}
+
+func (s *KeySet) fetch(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.url, nil)
+	if err != nil {
+		return err
+	}
+	res, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("fetch jwks: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		io.Copy(io.Discard, res.Body)
+		return fmt.Errorf("fetch jwks: unexpected status %d", res.StatusCode)
+	}
+
+	var jwks JWKS
+	if err := json.NewDecoder(res.Body).Decode(&jwks); err != nil {
+		return fmt.Errorf("decode jwks: %w", err)
+	}
+	keys, err := parseJWKS(jwks)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.keys = keys
+	s.fetchedAt = time.Now()
+	return nil
+}
+
+func parseJWKS(jwks JWKS) (map[string]any, error) {
+	keys := make(map[string]any, len(jwks.Keys))
+	for _, jwk := range jwks.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+		key, err := jwk.verificationKey()
+		if err != nil {
+			return nil, err
+		}
+		keys[jwk.Kid] = key
+	}
+	return keys, nil
+}
@@ -0,0 +1,99 @@
+package auth
+
+import (
+	"context"
+	"crypto/rsa"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Claims are the claims read from tokens. Scopes are read from the space
+// separated scope claim and from the scp array.
+type Claims struct {
+	jwt.RegisteredClaims
+	Scope string   `json:"scope,omitempty"`
+	Scp   []string `json:"scp,omitempty"`
+}
+
+func (c Claims) Scopes() []string {
+	return append(strings.Fields(c.Scope), c.Scp...)
+}
+
+// JWT authenticates the requests carrying a bearer token. RS256 tokens are
+// verified with the key set and HS256 tokens with the symmetric key of the set
+// named by their kid or, without one, the shared secret.
+type JWT struct {
+	keys   *KeySet
+	secret []byte
+	parser *jwt.Parser
+}
+
+func NewJWT(config JWTConfig, keys *KeySet) *JWT {
+	options := []jwt.ParserOption{
+		jwt.WithValidMethods(config.Algorithms),
+		jwt.WithLeeway(config.Leeway),
+		jwt.WithExpirationRequired(),
+	}
+	if config.Issuer != "" {
+		options = append(options, jwt.WithIssuer(config.Issuer))
+	}
+	if config.Audience != "" {
+		options = append(options, jwt.WithAudience(config.Audience))
+	}
+	return &JWT{keys: keys, secret: []byte(config.Secret), parser: jwt.NewParser(options...)}
+}
+
+func (j *JWT) Authenticate(r *http.Request) (Principal, error) {
+	scheme, token, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return Principal{}, ErrNoCredentials
+	}
+
+	var claims Claims
+	if _, err := j.parser.ParseWithClaims(token, &claims, j.keyFunc(r.Context())); err != nil {
+		return Principal{}, fmt.Errorf("%w: %w", ErrInvalidCredentials, err)
+	}
+	if claims.Subject == "" {
+		return Principal{}, fmt.Errorf("%w: token has no subject", ErrInvalidCredentials)
+	}
+	return Principal{Subject: claims.Subject, Method: MethodJWT, Scopes: claims.Scopes()}, nil
+}
+
+func (j *JWT) keyFunc(ctx context.Context) jwt.Keyfunc {
+	return func(token *jwt.Token) (any, error) {
+		kid, _ := token.Header["kid"].(string)
+
+		switch token.Method.(type) {
+		case *jwt.SigningMethodHMAC:
+			if kid == "" || j.keys == nil {
+				if len(j.secret) == 0 {
+					return nil, errors.New("no secret to verify HS256 tokens")
+				}
+				return j.secret, nil
+			}
+			key, err := j.keys.Key(ctx, kid)
+			if err != nil {
+				return nil, err
+			}
+			if secret, ok := key.([]byte); ok {
+				return secret, nil
+			}
+		case *jwt.SigningMethodRSA:
+			if j.keys == nil {
+				return nil, errors.New("no key set to verify RS256 tokens")
+			}
+			key, err := j.keys.Key(ctx, kid)
+			if err != nil {
+				return nil, err
+			}
+			if publicKey, ok := key.(*rsa.PublicKey); ok {
+				return publicKey, nil
+			}
+		}
+		return nil, fmt.Errorf("key %q cannot verify %s tokens", kid, token.Method.Alg())
+	}
+}
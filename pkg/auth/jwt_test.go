@@ -0,0 +1,173 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/require"
+)
+
+func rsaJWK(kid string, key *rsa.PrivateKey) JWK {
+	return JWK{
+		Kty: "RSA",
+		Kid: kid,
+		Alg: AlgorithmRS256,
+		N:   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+		E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+	}
+}
+
+func signToken(t *testing.T, method jwt.SigningMethod, kid string, key any, claims Claims) string {
+	t.Helper()
+	token := jwt.NewWithClaims(method, claims)
+	if kid != "" {
+		token.Header["kid"] = kid
+	}
+	signed, err := token.SignedString(key)
+	require.NoError(t, err)
+	return signed
+}
+
+func bearerRequest(token string) *http.Request {
+	req := httptest.NewRequest(http.MethodPost, "/score/", nil)
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	return req
+}
+
+func TestJWT_Authenticate(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	octKey := []byte("jwks-symmetric-key-of-32-bytes!!")
+
+	keysFile := filepath.Join(t.TempDir(), "jwks.json")
+	data, err := json.Marshal(JWKS{Keys: []JWK{
+		rsaJWK("rsa-1", rsaKey),
+		{Kty: "oct", Kid: "oct-1", K: base64.RawURLEncoding.EncodeToString(octKey)},
+	}})
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(keysFile, data, 0o600))
+	keys, err := LoadKeySet(keysFile)
+	require.NoError(t, err)
+
+	config := NewConfig().JWT
+	config.Secret = "shared-secret"
+	config.Issuer = "https://auth.example.com"
+	authenticator := NewJWT(config, keys)
+
+	claims := func(subject string, expiresIn time.Duration) Claims {
+		return Claims{
+			RegisteredClaims: jwt.RegisteredClaims{
+				Subject:   subject,
+				Issuer:    "https://auth.example.com",
+				ExpiresAt: jwt.NewNumericDate(time.Now().Add(expiresIn)),
+			},
+			Scope: "scores:write profile",
+		}
+	}
+
+	tests := []struct {
+		name              string
+		token             string
+		expectedPrincipal Principal
+		expectedError     error
+	}{
+		{
+			name:              "verifies RS256 tokens with the key set",
+			token:             signToken(t, jwt.SigningMethodRS256, "rsa-1", rsaKey, claims("player-1", time.Hour)),
+			expectedPrincipal: Principal{Subject: "player-1", Method: MethodJWT, Scopes: []string{"scores:write", "profile"}},
+		},
+		{
+			name:              "verifies HS256 tokens with the shared secret",
+			token:             signToken(t, jwt.SigningMethodHS256, "", []byte("shared-secret"), claims("player-2", time.Hour)),
+			expectedPrincipal: Principal{Subject: "player-2", Method: MethodJWT, Scopes: []string{"scores:write", "profile"}},
+		},
+		{
+			name:              "verifies HS256 tokens with a symmetric key of the set",
+			token:             signToken(t, jwt.SigningMethodHS256, "oct-1", octKey, claims("player-3", time.Hour)),
+			expectedPrincipal: Principal{Subject: "player-3", Method: MethodJWT, Scopes: []string{"scores:write", "profile"}},
+		},
+		{
+			name:          "rejects tokens signed with another key",
+			token:         signToken(t, jwt.SigningMethodRS256, "rsa-1", otherKey, claims("player-1", time.Hour)),
+			expectedError: ErrInvalidCredentials,
+		},
+		{
+			name:          "rejects HS256 tokens signed with the public key",
+			token:         signToken(t, jwt.SigningMethodHS256, "rsa-1", rsaKey.PublicKey.N.Bytes(), claims("player-1", time.Hour)),
+			expectedError: ErrInvalidCredentials,
+		},
+		{
+			name:          "rejects expired tokens",
+			token:         signToken(t, jwt.SigningMethodHS256, "", []byte("shared-secret"), claims("player-1", -time.Hour)),
+			expectedError: ErrInvalidCredentials,
+		},
+		{
+			name:          "rejects tokens without a subject",
+			token:         signToken(t, jwt.SigningMethodHS256, "", []byte("shared-secret"), claims("", time.Hour)),
+			expectedError: ErrInvalidCredentials,
+		},
+		{
+			name:          "skips requests without a token",
+			expectedError: ErrNoCredentials,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			principal, err := authenticator.Authenticate(bearerRequest(tt.token))
+
+			require.ErrorIs(t, err, tt.expectedError)
+			require.Equal(t, tt.expectedPrincipal, principal)
+		})
+	}
+}
+
+func TestJWT_RemoteKeySet(t *testing.T) {
+	firstKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	rotatedKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	var fetches atomic.Int32
+	var jwks atomic.Value
+	jwks.Store(JWKS{Keys: []JWK{rsaJWK("key-1", firstKey)}})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fetches.Add(1)
+		json.NewEncoder(w).Encode(jwks.Load())
+	}))
+	defer server.Close()
+
+	keys := NewRemoteKeySet(server.URL, time.Hour, server.Client())
+	keys.minRefresh = 0
+	authenticator := NewJWT(NewConfig().JWT, keys)
+	claims := Claims{RegisteredClaims: jwt.RegisteredClaims{Subject: "player-1", ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour))}}
+
+	_, err = authenticator.Authenticate(bearerRequest(signToken(t, jwt.SigningMethodRS256, "key-1", firstKey, claims)))
+	require.NoError(t, err)
+	_, err = authenticator.Authenticate(bearerRequest(signToken(t, jwt.SigningMethodRS256, "key-1", firstKey, claims)))
+	require.NoError(t, err)
+	require.Equal(t, int32(1), fetches.Load(), "known keys are cached")
+
+	jwks.Store(JWKS{Keys: []JWK{rsaJWK("key-2", rotatedKey)}})
+	_, err = authenticator.Authenticate(bearerRequest(signToken(t, jwt.SigningMethodRS256, "key-2", rotatedKey, claims)))
+	require.NoError(t, err)
+	require.Equal(t, int32(2), fetches.Load(), "unknown keys refresh the set")
+
+	_, err = authenticator.Authenticate(bearerRequest(signToken(t, jwt.SigningMethodRS256, "key-3", rotatedKey, claims)))
+	require.ErrorIs(t, err, ErrUnknownKey)
+}
@@ -0,0 +1,357 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/go-viper/mapstructure/v2"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+	"github.com/subosito/gotenv"
+)
+
+const (
+	DefaultEnvFile = ".env"
+	// FileSuffix marks environment variables that hold the path of a file containing the value.
+	FileSuffix = "_FILE"
+	// ConfigFileEnv is the environment variable selecting the YAML file when the --config flag is not set.
+	ConfigFileEnv = "CONFIG_FILE"
+)
+
+// Source is the layer a configuration value was loaded from.
+type Source string
+
+const (
+	SourceDefault Source = "default"
+	SourceFile    Source = "file"
+	SourceDotEnv  Source = "dotenv"
+	SourceEnv     Source = "env"
+	SourceFlag    Source = "flag"
+	// SourceRuntime marks the values changed at runtime with Loader.Set.
+	SourceRuntime Source = "runtime"
+)
+
+// Sources maps every configuration key to the layer that set its value.
+type Sources map[string]Source
+
+type Options struct {
+	// File is the YAML file to load, it can be overridden with ConfigFileEnv or the --config flag.
+	File string
+	// EnvFile is the dotenv file to load, DefaultEnvFile is used when it is empty.
+	EnvFile string
+	// Args are the command line flags, without the program name.
+	Args []string
+	// LookupEnv reads environment variables, os.LookupEnv is used when it is nil.
+	LookupEnv func(key string) (string, bool)
+	// FlagSet registers additional flags parsed along with the configuration flags.
+	FlagSet *pflag.FlagSet
+}
+
+// Load populates spec from its defaults, the YAML file, the dotenv file, the
+// environment and the flags, each layer overriding the previous one, and then
+// validates it. spec must be a pointer to a struct holding the defaults.
+func Load(spec any, options Options) (Sources, error) {
+	sources, _, err := load(spec, options)
+	return sources, err
+}
+
+// load is Load that also returns the files the configuration is read from.
+func load(spec any, options Options) (Sources, []string, error) {
+	keys, err := Keys(spec)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if options.LookupEnv == nil {
+		options.LookupEnv = os.LookupEnv
+	}
+	if options.EnvFile == "" {
+		options.EnvFile = DefaultEnvFile
+	}
+	if file, ok := options.LookupEnv(ConfigFileEnv); ok {
+		options.File = file
+	}
+
+	flags := newFlagSet(keys, options.FlagSet)
+	flags.StringVar(&options.File, "config", options.File, "YAML configuration file, overrides "+ConfigFileEnv)
+	if err := flags.Parse(options.Args); err != nil {
+		return nil, nil, err
+	}
+
+	sources := make(Sources, len(keys))
+	for _, key := range keys {
+		sources[key] = SourceDefault
+	}
+
+	values := map[string]any{}
+	set := func(key string, value any, source Source) {
+		values[key] = value
+		sources[key] = source
+	}
+
+	if options.File != "" {
+		fileValues, err := readFile(options.File)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, key := range keys {
+			if value, ok := fileValues[key]; ok {
+				set(key, value, SourceFile)
+			}
+		}
+	}
+
+	dotenv, err := readDotEnv(options.EnvFile)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var errs []error
+	lookups := []struct {
+		source Source
+		lookup func(string) (string, bool)
+	}{
+		{SourceDotEnv, func(name string) (string, bool) { value, ok := dotenv[name]; return value, ok }},
+		{SourceEnv, options.LookupEnv},
+	}
+	for _, layer := range lookups {
+		for _, key := range keys {
+			value, ok, err := lookupEnv(layer.lookup, EnvName(key))
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			if ok {
+				set(key, value, layer.source)
+			}
+		}
+	}
+	if len(errs) > 0 {
+		return nil, nil, errors.Join(errs...)
+	}
+
+	for _, key := range keys {
+		if flag := flags.Lookup(FlagName(key)); flag != nil && flag.Changed {
+			set(key, flag.Value.String(), SourceFlag)
+		}
+	}
+
+	if err := decode(nest(values), spec); err != nil {
+		return nil, nil, err
+	}
+
+	if err := Validate(spec); err != nil {
+		return nil, nil, err
+	}
+
+	files := []string{options.EnvFile}
+	if options.File != "" {
+		files = append([]string{options.File}, files...)
+	}
+	return sources, files, nil
+}
+
+// Keys returns the configuration keys of spec, nested structs are joined with a
+// dot and embedded structs are flattened into their parent.
+func Keys(spec any) ([]string, error) {
+	var keys []string
+	err := walk(spec, func(f field) {
+		keys = append(keys, f.key)
+	})
+	return keys, err
+}
+
+type field struct {
+	key   string
+	value reflect.Value
+	// secret is set for the fields of structs excluded from JSON with a "-" tag.
+	secret bool
+	// reloadable is set for fields tagged with reload:"true", they can change without a restart.
+	reloadable bool
+	// indirect is set for fields reached through a pointer, they are shared by copies of the spec.
+	indirect bool
+}
+
+func walk(spec any, fn func(field)) error {
+	v := reflect.ValueOf(spec)
+	if v.Kind() != reflect.Pointer || v.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("config spec must be a pointer to a struct, got %T", spec)
+	}
+
+	walkStruct(v.Elem().Type(), v.Elem(), "", false, false, fn)
+	return nil
+}
+
+func walkStruct(t reflect.Type, v reflect.Value, prefix string, secret bool, indirect bool, fn func(field)) {
+	for i := range t.NumField() {
+		structField := t.Field(i)
+		if !structField.IsExported() {
+			continue
+		}
+
+		fieldType := structField.Type
+		fieldValue := reflect.Value{}
+		if v.IsValid() {
+			fieldValue = v.Field(i)
+		}
+		fieldIndirect := indirect
+		if fieldType.Kind() == reflect.Pointer {
+			fieldIndirect = true
+			fieldType = fieldType.Elem()
+			if fieldValue.IsValid() {
+				fieldValue = fieldValue.Elem()
+			}
+		}
+
+		fieldSecret := secret || structField.Tag.Get("json") == "-"
+
+		if structField.Anonymous && fieldType.Kind() == reflect.Struct {
+			walkStruct(fieldType, fieldValue, prefix, fieldSecret, fieldIndirect, fn)
+			continue
+		}
+
+		name, _, _ := strings.Cut(structField.Tag.Get("mapstructure"), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = strings.ToLower(structField.Name)
+		}
+
+		if fieldType.Kind() == reflect.Struct && fieldType != reflect.TypeFor[time.Time]() {
+			walkStruct(fieldType, fieldValue, prefix+name+".", fieldSecret, fieldIndirect, fn)
+			continue
+		}
+		fn(field{
+			key:        prefix + name,
+			value:      fieldValue,
+			secret:     fieldSecret,
+			reloadable: structField.Tag.Get("reload") == "true",
+			indirect:   fieldIndirect,
+		})
+	}
+}
+
+// EnvName returns the environment variable of a key, log_level is read from LOG_LEVEL.
+func EnvName(key string) string {
+	return strings.ToUpper(strings.ReplaceAll(key, ".", "_"))
+}
+
+// FlagName returns the flag of a key, log_level is read from --log-level.
+func FlagName(key string) string {
+	return strings.NewReplacer(".", "-", "_", "-").Replace(key)
+}
+
+// lookupEnv reads name, or the file referenced by name with the FileSuffix when name is not set.
+func lookupEnv(lookup func(string) (string, bool), name string) (string, bool, error) {
+	if value, ok := lookup(name); ok {
+		return value, true, nil
+	}
+
+	path, ok := lookup(name + FileSuffix)
+	if !ok {
+		return "", false, nil
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return "", false, fmt.Errorf("read %s%s: %w", name, FileSuffix, err)
+	}
+	return strings.TrimRight(string(content), "\r\n"), true, nil
+}
+
+func newFlagSet(keys []string, extra *pflag.FlagSet) *pflag.FlagSet {
+	flags := pflag.NewFlagSet("config", pflag.ContinueOnError)
+	if extra != nil {
+		flags.AddFlagSet(extra)
+	}
+
+	for _, key := range keys {
+		flags.String(FlagName(key), "", "overrides "+EnvName(key))
+	}
+	return flags
+}
+
+func readFile(path string) (map[string]any, error) {
+	v := viper.New()
+	v.SetConfigFile(path)
+	if err := v.ReadInConfig(); err != nil {
+		return nil, fmt.Errorf("read config file %s: %w", path, err)
+	}
+
+	values := map[string]any{}
+	flatten("", v.AllSettings(), values)
+	return values, nil
+}
+
+func readDotEnv(path string) (map[string]string, error) {
+	file, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read env file %s: %w", path, err)
+	}
+	defer file.Close()
+
+	values, err := gotenv.StrictParse(file)
+	if err != nil {
+		return nil, fmt.Errorf("parse env file %s: %w", path, err)
+	}
+	return values, nil
+}
+
+func flatten(prefix string, settings map[string]any, values map[string]any) {
+	for key, value := range settings {
+		if nested, ok := value.(map[string]any); ok {
+			flatten(prefix+key+".", nested, values)
+			continue
+		}
+		values[prefix+key] = value
+	}
+}
+
+// nest turns dotted keys back into the nested maps expected by the decoder.
+func nest(values map[string]any) map[string]any {
+	nested := map[string]any{}
+	for _, key := range slices.Sorted(maps.Keys(values)) {
+		parts := strings.Split(key, ".")
+		current := nested
+		for _, part := range parts[:len(parts)-1] {
+			next, ok := current[part].(map[string]any)
+			if !ok {
+				next = map[string]any{}
+				current[part] = next
+			}
+			current = next
+		}
+		current[parts[len(parts)-1]] = values[key]
+	}
+	return nested
+}
+
+func decode(values map[string]any, spec any) error {
+	decoder, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
+		Result:           spec,
+		Squash:           true,
+		WeaklyTypedInput: true,
+		DecodeHook: mapstructure.ComposeDecodeHookFunc(
+			mapstructure.StringToTimeDurationHookFunc(),
+			mapstructure.StringToSliceHookFunc(","),
+		),
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := decoder.Decode(values); err != nil {
+		return fmt.Errorf("decode config: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,199 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/require"
+)
+
+type TestSecret struct {
+	Password string `mapstructure:"password" validate:"required"`
+}
+
+type testKafka struct {
+	Brokers []string      `mapstructure:"brokers" validate:"min=1,dive,hostname_port"`
+	Timeout time.Duration `mapstructure:"timeout"`
+}
+
+type testSpec struct {
+	*TestSecret
+	Port     int       `mapstructure:"port" validate:"min=1,max=65535"`
+	LogLevel string    `mapstructure:"log_level" validate:"oneof=debug info warn error" reload:"true"`
+	Name     string    `mapstructure:"name"`
+	Kafka    testKafka `mapstructure:"kafka"`
+}
+
+func newTestSpec() *testSpec {
+	return &testSpec{
+		TestSecret: &TestSecret{Password: "default"},
+		Port:       8080,
+		LogLevel:   "info",
+		Name:       "default",
+		Kafka: testKafka{
+			Brokers: []string{"localhost:9092"},
+			Timeout: time.Second,
+		},
+	}
+}
+
+func writeFile(t *testing.T, name string, content string) string {
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func envMap(values map[string]string) func(string) (string, bool) {
+	return func(key string) (string, bool) {
+		value, ok := values[key]
+		return value, ok
+	}
+}
+
+func TestKeys(t *testing.T) {
+	keys, err := Keys(newTestSpec())
+	require.NoError(t, err)
+	require.Equal(t, []string{"password", "port", "log_level", "name", "kafka.brokers", "kafka.timeout"}, keys)
+
+	_, err = Keys(testSpec{})
+	require.EqualError(t, err, "config spec must be a pointer to a struct, got config.testSpec")
+}
+
+func TestLoad(t *testing.T) {
+	yamlFile := writeFile(t, "config.yaml", "port: 9000\nname: file\nlog_level: warn\nkafka:\n  timeout: 5s\n")
+	dotEnvFile := writeFile(t, ".env", "NAME=dotenv\nLOG_LEVEL=error\nKAFKA_TIMEOUT=3s\n")
+	secretFile := writeFile(t, "password", "from-file\n")
+
+	spec := newTestSpec()
+	sources, err := Load(spec, Options{
+		File:    yamlFile,
+		EnvFile: dotEnvFile,
+		Args:    []string{"--log-level", "debug"},
+		LookupEnv: envMap(map[string]string{
+			"NAME":          "env",
+			"KAFKA_BROKERS": "broker-1:9092,broker-2:9092",
+			"PASSWORD_FILE": secretFile,
+		}),
+	})
+	require.NoError(t, err)
+
+	require.Equal(t, &testSpec{
+		TestSecret: &TestSecret{Password: "from-file"},
+		Port:       9000,
+		LogLevel:   "debug",
+		Name:       "env",
+		Kafka: testKafka{
+			Brokers: []string{"broker-1:9092", "broker-2:9092"},
+			Timeout: 3 * time.Second,
+		},
+	}, spec)
+
+	require.Equal(t, Sources{
+		"password":      SourceEnv,
+		"port":          SourceFile,
+		"log_level":     SourceFlag,
+		"name":          SourceEnv,
+		"kafka.brokers": SourceEnv,
+		"kafka.timeout": SourceDotEnv,
+	}, sources)
+}
+
+func TestLoad_Defaults(t *testing.T) {
+	spec := newTestSpec()
+	sources, err := Load(spec, Options{
+		EnvFile:   filepath.Join(t.TempDir(), ".env"),
+		LookupEnv: envMap(nil),
+	})
+	require.NoError(t, err)
+	require.Equal(t, newTestSpec(), spec)
+	require.Equal(t, SourceDefault, sources["port"])
+}
+
+func TestLoad_ConfigFileFromEnvAndFlag(t *testing.T) {
+	envFile := writeFile(t, "env.yaml", "name: env-file\n")
+	flagFile := writeFile(t, "flag.yaml", "name: flag-file\n")
+
+	spec := newTestSpec()
+	_, err := Load(spec, Options{
+		EnvFile:   filepath.Join(t.TempDir(), ".env"),
+		LookupEnv: envMap(map[string]string{ConfigFileEnv: envFile}),
+	})
+	require.NoError(t, err)
+	require.Equal(t, "env-file", spec.Name)
+
+	spec = newTestSpec()
+	_, err = Load(spec, Options{
+		EnvFile:   filepath.Join(t.TempDir(), ".env"),
+		Args:      []string{"--config", flagFile},
+		LookupEnv: envMap(map[string]string{ConfigFileEnv: envFile}),
+	})
+	require.NoError(t, err)
+	require.Equal(t, "flag-file", spec.Name)
+}
+
+func TestLoad_ExtraFlags(t *testing.T) {
+	extra := pflag.NewFlagSet("extra", pflag.ContinueOnError)
+	verbose := extra.Bool("verbose", false, "")
+
+	_, err := Load(newTestSpec(), Options{
+		EnvFile:   filepath.Join(t.TempDir(), ".env"),
+		Args:      []string{"--verbose"},
+		LookupEnv: envMap(nil),
+		FlagSet:   extra,
+	})
+	require.NoError(t, err)
+	require.True(t, *verbose)
+}
+
+func TestLoad_Errors(t *testing.T) {
+	tests := []struct {
+		name          string
+		env           map[string]string
+		args          []string
+		expectedError string
+	}{
+		{
+			name: "aggregates validation errors",
+			env: map[string]string{
+				"PASSWORD":      "",
+				"PORT":          "0",
+				"LOG_LEVEL":     "verbose",
+				"KAFKA_BROKERS": "not-an-address",
+			},
+			expectedError: "invalid configuration:" +
+				"\n  password: is required" +
+				"\n  port: must be at least 1, got 0" +
+				"\n  log_level: must be one of [debug info warn error], got \"verbose\"" +
+				"\n  kafka.brokers[0]: must be a host:port address, got \"not-an-address\"",
+		},
+		{
+			name:          "missing secret file",
+			env:           map[string]string{"PASSWORD_FILE": "/does/not/exist"},
+			expectedError: "read PASSWORD_FILE: open /does/not/exist: no such file or directory",
+		},
+		{
+			name:          "unknown flag",
+			args:          []string{"--unknown"},
+			expectedError: "unknown flag: --unknown",
+		},
+		{
+			name:          "invalid value",
+			args:          []string{"--port", "eighty"},
+			expectedError: "decode config: decoding failed due to the following error(s):\n\n'port' cannot parse value as 'int': strconv.ParseInt: invalid syntax",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Load(newTestSpec(), Options{
+				EnvFile:   filepath.Join(t.TempDir(), ".env"),
+				Args:      tt.args,
+				LookupEnv: envMap(tt.env),
+			})
+			require.EqualError(t, err, tt.expectedError)
+		})
+	}
+}
@@ -0,0 +1,277 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"maps"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// DefaultDebounce is the time the Loader waits for file changes to settle before reloading.
+const DefaultDebounce = 250 * time.Millisecond
+
+// Loader loads a spec and reloads it on demand or when one of its files change.
+// Only fields tagged with reload:"true" are applied on reload, changes to the
+// other fields are logged and take effect on the next restart.
+type Loader[T any] struct {
+	// Debounce is the time to wait for file changes to settle, DefaultDebounce is used when it is zero.
+	Debounce time.Duration
+
+	newSpec func() T
+	options Options
+	// reloadMu serializes reloads, the flags of the options are parsed again on every reload.
+	reloadMu    sync.Mutex
+	mu          sync.RWMutex
+	current     T
+	sources     Sources
+	files       []string
+	subscribers []func(previous, current T)
+}
+
+// NewLoader creates a Loader for the specs returned by newSpec, which must
+// return a pointer to a struct holding the defaults.
+func NewLoader[T any](newSpec func() T, options Options) *Loader[T] {
+	return &Loader[T]{
+		newSpec: newSpec,
+		options: options,
+	}
+}
+
+// Load loads the spec, it must be called before the Loader is used.
+func (l *Loader[T]) Load() (T, error) {
+	spec := l.newSpec()
+	sources, files, err := load(spec, l.options)
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.current, l.sources, l.files = spec, sources, files
+	return spec, nil
+}
+
+// Current returns the latest spec, it must not be modified.
+func (l *Loader[T]) Current() T {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.current
+}
+
+func (l *Loader[T]) Snapshot() (Snapshot, error) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return NewSnapshot(l.current, l.sources)
+}
+
+// Subscribe registers fn to be called with the previous and the current spec
+// after a reload changed at least one reloadable setting.
+func (l *Loader[T]) Subscribe(fn func(previous, current T)) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.subscribers = append(l.subscribers, fn)
+}
+
+// Reload loads the spec again and applies its reloadable settings. The current
+// spec is kept when the new one is invalid.
+func (l *Loader[T]) Reload() error {
+	l.reloadMu.Lock()
+	defer l.reloadMu.Unlock()
+
+	next := l.newSpec()
+	nextSources, _, err := load(next, l.options)
+	if err != nil {
+		return err
+	}
+
+	l.mu.Lock()
+	previous := l.current
+	merged, changed, ignored := mergeReloadable(previous, next)
+	sources := make(Sources, len(l.sources))
+	for key, source := range l.sources {
+		sources[key] = source
+	}
+	for _, key := range changed {
+		sources[key] = nextSources[key]
+	}
+	current := merged.(T)
+	l.current, l.sources = current, sources
+	subscribers := slices.Clone(l.subscribers)
+	l.mu.Unlock()
+
+	if len(ignored) > 0 {
+		slog.Warn("configuration changes require a restart", "keys", ignored)
+	}
+	if len(changed) == 0 {
+		return nil
+	}
+
+	slog.Info("configuration reloaded", "keys", changed)
+	for _, subscriber := range subscribers {
+		subscriber(previous, current)
+	}
+	return nil
+}
+
+// Set changes the reloadable setting key at runtime, such as the log level
+// changed through an admin endpoint. The value is validated, reported with
+// SourceRuntime and the subscribers are notified like after a reload. The
+// next reload applies the configured value again.
+func (l *Loader[T]) Set(key string, value any) error {
+	l.reloadMu.Lock()
+	defer l.reloadMu.Unlock()
+
+	l.mu.Lock()
+	previous := l.current
+	merged := reflect.New(reflect.TypeOf(previous).Elem())
+	merged.Elem().Set(reflect.ValueOf(previous).Elem())
+
+	f, ok := fieldsByKey(merged.Interface())[key]
+	if !ok || !f.reloadable || f.secret || f.indirect || !f.value.CanSet() {
+		l.mu.Unlock()
+		return fmt.Errorf("%s cannot be changed at runtime", key)
+	}
+	next := reflect.ValueOf(value)
+	if !next.IsValid() || !next.Type().AssignableTo(f.value.Type()) {
+		l.mu.Unlock()
+		return fmt.Errorf("%s must be a %s, got %T", key, f.value.Type(), value)
+	}
+	if equalValues(f.value, next) {
+		l.mu.Unlock()
+		return nil
+	}
+	f.value.Set(next)
+	if err := Validate(merged.Interface()); err != nil {
+		l.mu.Unlock()
+		return err
+	}
+
+	current := merged.Interface().(T)
+	sources := maps.Clone(l.sources)
+	sources[key] = SourceRuntime
+	l.current, l.sources = current, sources
+	subscribers := slices.Clone(l.subscribers)
+	l.mu.Unlock()
+
+	slog.Info("configuration changed at runtime", "key", key)
+	for _, subscriber := range subscribers {
+		subscriber(previous, current)
+	}
+	return nil
+}
+
+// Run reloads the spec whenever one of the files it was loaded from changes.
+// The directories of the files are watched so files replaced by a rename, as
+// done by editors and mounted config maps, are picked up.
+func (l *Loader[T]) Run(ctx context.Context, errChan chan error) {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		errChan <- err
+		return
+	}
+	defer watcher.Close()
+
+	l.mu.RLock()
+	files := map[string]bool{}
+	for _, file := range l.files {
+		files[filepath.Clean(file)] = true
+	}
+	l.mu.RUnlock()
+
+	for dir := range watchedDirs(files) {
+		if err := watcher.Add(dir); err != nil {
+			errChan <- fmt.Errorf("watch %s: %w", dir, err)
+			return
+		}
+	}
+
+	debounce := l.Debounce
+	if debounce <= 0 {
+		debounce = DefaultDebounce
+	}
+	timer := time.NewTimer(debounce)
+	timer.Stop()
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return
+			}
+			if files[filepath.Clean(event.Name)] && !event.Has(fsnotify.Chmod) {
+				timer.Reset(debounce)
+			}
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return
+			}
+			slog.Error("config watcher error", "error", err.Error())
+		case <-timer.C:
+			if err := l.Reload(); err != nil {
+				slog.Error("failed to reload configuration", "error", err.Error())
+			}
+		}
+	}
+}
+
+func watchedDirs(files map[string]bool) map[string]bool {
+	dirs := map[string]bool{}
+	for file := range files {
+		dirs[filepath.Dir(file)] = true
+	}
+	return dirs
+}
+
+// mergeReloadable returns a copy of current with the reloadable fields of next,
+// along with the keys that were applied and the changed keys that were not.
+func mergeReloadable(current any, next any) (any, []string, []string) {
+	merged := reflect.New(reflect.TypeOf(current).Elem())
+	merged.Elem().Set(reflect.ValueOf(current).Elem())
+
+	currentFields := fieldsByKey(current)
+	mergedFields := fieldsByKey(merged.Interface())
+
+	var changed, ignored []string
+	walk(next, func(f field) {
+		if equalValues(currentFields[f.key].value, f.value) {
+			return
+		}
+
+		target := mergedFields[f.key].value
+		if !f.reloadable || f.secret || f.indirect || !target.CanSet() {
+			ignored = append(ignored, f.key)
+			return
+		}
+
+		target.Set(f.value)
+		changed = append(changed, f.key)
+	})
+
+	return merged.Interface(), changed, ignored
+}
+
+func fieldsByKey(spec any) map[string]field {
+	fields := map[string]field{}
+	walk(spec, func(f field) {
+		fields[f.key] = f
+	})
+	return fields
+}
+
+func equalValues(a reflect.Value, b reflect.Value) bool {
+	if !a.IsValid() || !b.IsValid() {
+		return a.IsValid() == b.IsValid()
+	}
+	return reflect.DeepEqual(a.Interface(), b.Interface())
+}
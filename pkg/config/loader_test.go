@@ -0,0 +1,112 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newTestLoader(t *testing.T, yaml string) (*Loader[*testSpec], string) {
+	file := writeFile(t, "config.yaml", yaml)
+	loader := NewLoader(newTestSpec, Options{
+		File:      file,
+		EnvFile:   filepath.Join(t.TempDir(), ".env"),
+		LookupEnv: envMap(nil),
+	})
+	_, err := loader.Load()
+	require.NoError(t, err)
+	return loader, file
+}
+
+func TestLoader_Reload(t *testing.T) {
+	loader, file := newTestLoader(t, "log_level: info\nport: 8080\n")
+	initial := loader.Current()
+
+	var notified atomic.Int32
+	loader.Subscribe(func(previous, current *testSpec) {
+		require.Equal(t, "info", previous.LogLevel)
+		require.Equal(t, "debug", current.LogLevel)
+		notified.Add(1)
+	})
+
+	require.NoError(t, os.WriteFile(file, []byte("log_level: debug\nport: 9090\n"), 0o600))
+	require.NoError(t, loader.Reload())
+
+	current := loader.Current()
+	require.Equal(t, "debug", current.LogLevel)
+	require.Equal(t, 8080, current.Port, "port is not reloadable")
+	require.Equal(t, "info", initial.LogLevel, "the previous spec is not modified")
+	require.Same(t, initial.TestSecret, current.TestSecret)
+	require.Equal(t, int32(1), notified.Load())
+
+	snapshot, err := loader.Snapshot()
+	require.NoError(t, err)
+	require.Equal(t, Value{Value: "debug", Source: SourceFile}, snapshot["log_level"])
+}
+
+func TestLoader_Reload_NoChanges(t *testing.T) {
+	loader, _ := newTestLoader(t, "log_level: info\n")
+
+	loader.Subscribe(func(previous, current *testSpec) {
+		t.Fatal("subscribers must not be notified without changes")
+	})
+
+	require.NoError(t, loader.Reload())
+}
+
+func TestLoader_Reload_Invalid(t *testing.T) {
+	loader, file := newTestLoader(t, "log_level: info\n")
+
+	require.NoError(t, os.WriteFile(file, []byte("log_level: verbose\n"), 0o600))
+	err := loader.Reload()
+
+	require.EqualError(t, err, "invalid configuration:\n  log_level: must be one of [debug info warn error], got \"verbose\"")
+	require.Equal(t, "info", loader.Current().LogLevel)
+}
+
+func TestLoader_Set(t *testing.T) {
+	loader, _ := newTestLoader(t, "log_level: info\n")
+
+	var notified atomic.Int32
+	loader.Subscribe(func(previous, current *testSpec) {
+		require.Equal(t, "debug", current.LogLevel)
+		notified.Add(1)
+	})
+
+	require.NoError(t, loader.Set("log_level", "debug"))
+	require.Equal(t, "debug", loader.Current().LogLevel)
+	require.Equal(t, int32(1), notified.Load())
+	snapshot, err := loader.Snapshot()
+	require.NoError(t, err)
+	require.Equal(t, Value{Value: "debug", Source: SourceRuntime}, snapshot["log_level"])
+
+	require.EqualError(t, loader.Set("port", 9090), "port cannot be changed at runtime")
+	require.EqualError(t, loader.Set("log_level", 1), "log_level must be a string, got int")
+	require.EqualError(t, loader.Set("log_level", "verbose"), "invalid configuration:\n  log_level: must be one of [debug info warn error], got \"verbose\"")
+	require.Equal(t, "debug", loader.Current().LogLevel)
+	require.Equal(t, int32(1), notified.Load())
+}
+
+func TestLoader_Run(t *testing.T) {
+	loader, file := newTestLoader(t, "log_level: info\n")
+	loader.Debounce = 10 * time.Millisecond
+
+	ctx := t.Context()
+	errChan := make(chan error, 1)
+	go loader.Run(ctx, errChan)
+
+	// Replace the file with a rename like editors and config maps do.
+	require.Eventually(t, func() bool {
+		next := file + ".tmp"
+		if os.WriteFile(next, []byte("log_level: warn\n"), 0o600) != nil || os.Rename(next, file) != nil {
+			return false
+		}
+		return loader.Current().LogLevel == "warn"
+	}, 2*time.Second, 50*time.Millisecond)
+
+	require.Empty(t, errChan)
+}
@@ -0,0 +1,115 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"time"
+
+	"github.com/spf13/pflag"
+	"go.yaml.in/yaml/v3"
+)
+
+const Redacted = "[REDACTED]"
+
+const (
+	FormatJSON = "json"
+	FormatYAML = "yaml"
+)
+
+type Value struct {
+	Value  any    `json:"value" yaml:"value"`
+	Source Source `json:"source" yaml:"source"`
+}
+
+// Snapshot is the effective configuration, keyed like Sources, with secrets redacted.
+type Snapshot map[string]Value
+
+// NewSnapshot captures the values of spec along with the source of each of them.
+// Fields of structs tagged with json:"-", such as the embedded Secret, are redacted.
+func NewSnapshot(spec any, sources Sources) (Snapshot, error) {
+	snapshot := Snapshot{}
+	err := walk(spec, func(f field) {
+		source, ok := sources[f.key]
+		if !ok {
+			source = SourceDefault
+		}
+		snapshot[f.key] = Value{Value: displayValue(f), Source: source}
+	})
+	return snapshot, err
+}
+
+func displayValue(f field) any {
+	if !f.value.IsValid() {
+		if f.secret {
+			return ""
+		}
+		return nil
+	}
+	if f.secret {
+		if f.value.IsZero() {
+			return ""
+		}
+		return Redacted
+	}
+	if f.value.Type() == reflect.TypeFor[time.Duration]() {
+		return time.Duration(f.value.Int()).String()
+	}
+	return f.value.Interface()
+}
+
+// Encode writes the snapshot in the given format, FormatJSON or FormatYAML.
+func (s Snapshot) Encode(w io.Writer, format string) error {
+	switch format {
+	case FormatJSON, "":
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(s)
+	case FormatYAML:
+		encoder := yaml.NewEncoder(w)
+		encoder.SetIndent(2)
+		if err := encoder.Encode(s); err != nil {
+			return err
+		}
+		return encoder.Close()
+	default:
+		return fmt.Errorf("unsupported config format %q, expected %s or %s", format, FormatJSON, FormatYAML)
+	}
+}
+
+// PrintFlag registers the --print-config flag, the returned format is empty unless the flag is set.
+func PrintFlag(flags *pflag.FlagSet) *string {
+	format := flags.String("print-config", "", "print the effective configuration as json or yaml and exit")
+	flags.Lookup("print-config").NoOptDefVal = FormatJSON
+	return format
+}
+
+// Handler serves the snapshot returned by snapshot, as JSON unless the format
+// query parameter is set to yaml.
+func Handler(snapshot func() (Snapshot, error)) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		format := r.URL.Query().Get("format")
+		if format == "" {
+			format = FormatJSON
+		}
+		if format != FormatJSON && format != FormatYAML {
+			http.Error(w, fmt.Sprintf("unsupported format %q", format), http.StatusBadRequest)
+			return
+		}
+
+		s, err := snapshot()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		if format == FormatYAML {
+			w.Header().Set("Content-Type", "application/yaml")
+		} else {
+			w.Header().Set("Content-Type", "application/json")
+		}
+		s.Encode(w, format)
+	})
+}
@@ -0,0 +1,160 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/require"
+)
+
+type redactedSpec struct {
+	*TestSecret `json:"-"`
+	Port        int `mapstructure:"port"`
+}
+
+func TestNewSnapshot(t *testing.T) {
+	spec := newTestSpec()
+	spec.Kafka.Brokers = []string{"broker:9092"}
+
+	snapshot, err := NewSnapshot(spec, Sources{"port": SourceEnv})
+	require.NoError(t, err)
+	require.Equal(t, Snapshot{
+		"password":      {Value: "default", Source: SourceDefault},
+		"port":          {Value: 8080, Source: SourceEnv},
+		"log_level":     {Value: "info", Source: SourceDefault},
+		"name":          {Value: "default", Source: SourceDefault},
+		"kafka.brokers": {Value: []string{"broker:9092"}, Source: SourceDefault},
+		"kafka.timeout": {Value: "1s", Source: SourceDefault},
+	}, snapshot)
+}
+
+func TestNewSnapshot_RedactsSecrets(t *testing.T) {
+	tests := []struct {
+		name          string
+		spec          *redactedSpec
+		expectedValue any
+	}{
+		{
+			name:          "set secret is redacted",
+			spec:          &redactedSpec{TestSecret: &TestSecret{Password: "hunter2"}},
+			expectedValue: Redacted,
+		},
+		{
+			name:          "empty secret stays empty",
+			spec:          &redactedSpec{TestSecret: &TestSecret{}},
+			expectedValue: "",
+		},
+		{
+			name:          "nil secret is empty",
+			spec:          &redactedSpec{},
+			expectedValue: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			snapshot, err := NewSnapshot(tt.spec, Sources{"password": SourceFile})
+			require.NoError(t, err)
+			require.Equal(t, Value{Value: tt.expectedValue, Source: SourceFile}, snapshot["password"])
+		})
+	}
+}
+
+func TestSnapshot_Encode(t *testing.T) {
+	snapshot := Snapshot{"port": {Value: 8080, Source: SourceFlag}}
+
+	tests := []struct {
+		format        string
+		expected      string
+		expectedError string
+	}{
+		{format: FormatJSON, expected: "{\n  \"port\": {\n    \"value\": 8080,\n    \"source\": \"flag\"\n  }\n}\n"},
+		{format: FormatYAML, expected: "port:\n  value: 8080\n  source: flag\n"},
+		{format: "toml", expectedError: `unsupported config format "toml", expected json or yaml`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			var buf bytes.Buffer
+			err := snapshot.Encode(&buf, tt.format)
+			if tt.expectedError != "" {
+				require.EqualError(t, err, tt.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, buf.String())
+		})
+	}
+}
+
+func TestPrintFlag(t *testing.T) {
+	flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	format := PrintFlag(flags)
+	require.NoError(t, flags.Parse([]string{"--print-config"}))
+	require.Equal(t, FormatJSON, *format)
+
+	flags = pflag.NewFlagSet("test", pflag.ContinueOnError)
+	format = PrintFlag(flags)
+	require.NoError(t, flags.Parse([]string{"--print-config=yaml"}))
+	require.Equal(t, FormatYAML, *format)
+}
+
+func TestHandler(t *testing.T) {
+	snapshot := Snapshot{"port": {Value: 8080, Source: SourceDefault}}
+
+	tests := []struct {
+		name                string
+		query               string
+		snapshotErr         error
+		expectedStatus      int
+		expectedContentType string
+		expectedBody        string
+	}{
+		{
+			name:                "json by default",
+			expectedStatus:      http.StatusOK,
+			expectedContentType: "application/json",
+			expectedBody:        "{\n  \"port\": {\n    \"value\": 8080,\n    \"source\": \"default\"\n  }\n}\n",
+		},
+		{
+			name:                "yaml",
+			query:               "?format=yaml",
+			expectedStatus:      http.StatusOK,
+			expectedContentType: "application/yaml",
+			expectedBody:        "port:\n  value: 8080\n  source: default\n",
+		},
+		{
+			name:                "unsupported format",
+			query:               "?format=xml",
+			expectedStatus:      http.StatusBadRequest,
+			expectedContentType: "text/plain; charset=utf-8",
+			expectedBody:        "unsupported format \"xml\"\n",
+		},
+		{
+			name:                "snapshot failure",
+			snapshotErr:         errors.New("invalid spec"),
+			expectedStatus:      http.StatusInternalServerError,
+			expectedContentType: "text/plain; charset=utf-8",
+			expectedBody:        "invalid spec\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := Handler(func() (Snapshot, error) {
+				return snapshot, tt.snapshotErr
+			})
+
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/debug/config"+tt.query, nil))
+
+			require.Equal(t, tt.expectedStatus, w.Code)
+			require.Equal(t, tt.expectedContentType, w.Header().Get("Content-Type"))
+			require.Equal(t, tt.expectedBody, w.Body.String())
+		})
+	}
+}
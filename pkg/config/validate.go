@@ -0,0 +1,145 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"unicode"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// ValidationError aggregates every invalid field of a configuration.
+type ValidationError struct {
+	Fields []FieldError
+}
+
+type FieldError struct {
+	Key     string
+	Message string
+}
+
+func (e *ValidationError) Error() string {
+	var b strings.Builder
+	b.WriteString("invalid configuration:")
+	for _, field := range e.Fields {
+		fmt.Fprintf(&b, "\n  %s: %s", field.Key, field.Message)
+	}
+	return b.String()
+}
+
+var validate = newValidator()
+
+func newValidator() *validator.Validate {
+	v := validator.New(validator.WithRequiredStructEnabled())
+	v.RegisterTagNameFunc(func(field reflect.StructField) string {
+		// Embedded structs keep their Go name so they can be dropped from the key.
+		if field.Anonymous {
+			return ""
+		}
+		name, _, _ := strings.Cut(field.Tag.Get("mapstructure"), ",")
+		if name == "-" {
+			return ""
+		}
+		if name == "" {
+			return strings.ToLower(field.Name)
+		}
+		return name
+	})
+	return v
+}
+
+// Checker is implemented by specs with rules the validate tags cannot express,
+// its fields are reported along with the invalid tags.
+type Checker interface {
+	Check() []FieldError
+}
+
+// Validate checks spec against its validate struct tags and reports every invalid field at once.
+func Validate(spec any) error {
+	validationError := &ValidationError{}
+	if err := validate.Struct(spec); err != nil {
+		var fieldErrors validator.ValidationErrors
+		if !errors.As(err, &fieldErrors) {
+			return err
+		}
+		for _, fieldError := range fieldErrors {
+			validationError.Fields = append(validationError.Fields, FieldError{
+				Key:     fieldKey(fieldError.Namespace()),
+				Message: message(fieldError),
+			})
+		}
+	}
+	if checker, ok := spec.(Checker); ok {
+		validationError.Fields = append(validationError.Fields, checker.Check()...)
+	}
+
+	if len(validationError.Fields) == 0 {
+		return nil
+	}
+	return validationError
+}
+
+// fieldKey drops the root struct and the embedded structs, which keep their capitalized
+// Go names, from the namespace of a field.
+func fieldKey(namespace string) string {
+	parts := strings.Split(namespace, ".")[1:]
+	key := parts[:0]
+	for _, part := range parts {
+		if part != "" && part[0] >= 'A' && part[0] <= 'Z' {
+			continue
+		}
+		key = append(key, part)
+	}
+	return strings.Join(key, ".")
+}
+
+func message(fieldError validator.FieldError) string {
+	switch fieldError.Tag() {
+	case "required":
+		return "is required"
+	case "min":
+		if fieldError.Kind() == reflect.Slice || fieldError.Kind() == reflect.String {
+			return fmt.Sprintf("must have at least %s elements", fieldError.Param())
+		}
+		return fmt.Sprintf("must be at least %s, got %v", fieldError.Param(), fieldError.Value())
+	case "max":
+		if fieldError.Kind() == reflect.Slice || fieldError.Kind() == reflect.String {
+			return fmt.Sprintf("must have at most %s elements", fieldError.Param())
+		}
+		return fmt.Sprintf("must be at most %s, got %v", fieldError.Param(), fieldError.Value())
+	case "oneof":
+		return fmt.Sprintf("must be one of [%s], got %q", fieldError.Param(), fieldError.Value())
+	case "hostname_port":
+		return fmt.Sprintf("must be a host:port address, got %q", fieldError.Value())
+	case "required_with":
+		return fmt.Sprintf("is required when %s is set", snakeCase(fieldError.Param()))
+	case "required_if":
+		field, value, _ := strings.Cut(fieldError.Param(), " ")
+		return fmt.Sprintf("is required when %s is %s", snakeCase(field), value)
+	case "required_without":
+		return fmt.Sprintf("is required when %s is not set", snakeCase(fieldError.Param()))
+	case "url":
+		return "must be a valid URL"
+	case "ip|cidr":
+		return fmt.Sprintf("must be an IP address or CIDR, got %q", fieldError.Value())
+	default:
+		return fmt.Sprintf("failed the %s validation", fieldError.Tag())
+	}
+}
+
+// snakeCase converts the Go field names used as validation parameters to their configuration key.
+func snakeCase(name string) string {
+	var b strings.Builder
+	for i, r := range name {
+		if unicode.IsUpper(r) {
+			if i > 0 && !unicode.IsUpper(rune(name[i-1])) {
+				b.WriteByte('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
@@ -0,0 +1,166 @@
+package eventmeta
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"os"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+)
+
+const (
+	HeaderContentType      = "content-type"
+	HeaderSchemaID         = "schema-id"
+	HeaderEventType        = "event-type"
+	HeaderEventVersion     = "event-version"
+	HeaderProducerService  = "producer-service"
+	HeaderProducerInstance = "producer-instance"
+	HeaderRequestID        = "request-id"
+	HeaderTraceparent      = "traceparent"
+	HeaderEventTime        = "event-time"
+)
+
+// HTTPHeaderRequestID is the HTTP header carrying the request id.
+const HTTPHeaderRequestID = "X-Request-ID"
+
+var traceparentPattern = regexp.MustCompile(`^[0-9a-f]{2}-[0-9a-f]{32}-[0-9a-f]{16}-[0-9a-f]{2}$`)
+
+// Metadata describes an event independently of its payload, it travels as
+// the headers of the kafka message. Traceparent is only read, the trace
+// context is written to the headers by the propagators.
+type Metadata struct {
+	ContentType      string
+	SchemaID         int
+	EventType        string
+	EventVersion     string
+	ProducerService  string
+	ProducerInstance string
+	RequestID        string
+	Traceparent      string
+	EventTime        time.Time
+}
+
+// Headers returns the kafka headers of the set fields.
+func (m Metadata) Headers() []kafka.Header {
+	var headers []kafka.Header
+	add := func(key string, value string) {
+		if value != "" {
+			headers = append(headers, kafka.Header{Key: key, Value: []byte(value)})
+		}
+	}
+
+	add(HeaderContentType, m.ContentType)
+	if m.SchemaID != 0 {
+		add(HeaderSchemaID, strconv.Itoa(m.SchemaID))
+	}
+	add(HeaderEventType, m.EventType)
+	add(HeaderEventVersion, m.EventVersion)
+	add(HeaderProducerService, m.ProducerService)
+	add(HeaderProducerInstance, m.ProducerInstance)
+	add(HeaderRequestID, m.RequestID)
+	add(HeaderTraceparent, m.Traceparent)
+	if !m.EventTime.IsZero() {
+		add(HeaderEventTime, m.EventTime.UTC().Format(time.RFC3339Nano))
+	}
+	return headers
+}
+
+// FromHeaders reads the metadata of a kafka message, malformed values are ignored.
+func FromHeaders(headers []kafka.Header) Metadata {
+	var m Metadata
+	for _, header := range headers {
+		value := string(header.Value)
+		switch header.Key {
+		case HeaderContentType:
+			m.ContentType = value
+		case HeaderSchemaID:
+			m.SchemaID, _ = strconv.Atoi(value)
+		case HeaderEventType:
+			m.EventType = value
+		case HeaderEventVersion:
+			m.EventVersion = value
+		case HeaderProducerService:
+			m.ProducerService = value
+		case HeaderProducerInstance:
+			m.ProducerInstance = value
+		case HeaderRequestID:
+			m.RequestID = value
+		case HeaderTraceparent:
+			if ValidTraceparent(value) {
+				m.Traceparent = value
+			}
+		case HeaderEventTime:
+			m.EventTime, _ = time.Parse(time.RFC3339Nano, value)
+		}
+	}
+	return m
+}
+
+// LogAttrs returns the set fields as slog key-value pairs. The request and
+// trace ids are added from the context by the log handler, and the schema id
+// is left to the decoder which reads it from the payload.
+func (m Metadata) LogAttrs() []any {
+	var attrs []any
+	add := func(key string, value string) {
+		if value != "" {
+			attrs = append(attrs, key, value)
+		}
+	}
+
+	add("event_type", m.EventType)
+	add("event_version", m.EventVersion)
+	add("producer_service", m.ProducerService)
+	add("producer_instance", m.ProducerInstance)
+	if !m.EventTime.IsZero() {
+		attrs = append(attrs, "event_time", m.EventTime)
+	}
+	return attrs
+}
+
+type metadataKey struct{}
+type requestIDKey struct{}
+
+func NewContext(ctx context.Context, m Metadata) context.Context {
+	ctx = WithRequestID(ctx, m.RequestID)
+	return context.WithValue(ctx, metadataKey{}, m)
+}
+
+func FromContext(ctx context.Context) (Metadata, bool) {
+	m, ok := ctx.Value(metadataKey{}).(Metadata)
+	return m, ok
+}
+
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	if requestID == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, requestIDKey{}, requestID)
+}
+
+func RequestID(ctx context.Context) string {
+	requestID, _ := ctx.Value(requestIDKey{}).(string)
+	return requestID
+}
+
+func ValidTraceparent(traceparent string) bool {
+	return traceparentPattern.MatchString(traceparent) && traceparent[:2] != "ff"
+}
+
+func NewRequestID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// Instance identifies the running process, it is the host name or, in containers, the pod name.
+func Instance() string {
+	hostname, err := os.Hostname()
+	if err != nil {
+		return "unknown"
+	}
+	return hostname
+}
@@ -0,0 +1,93 @@
+package eventmeta
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+	"github.com/stretchr/testify/require"
+)
+
+const traceparent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+
+func TestMetadata_Headers(t *testing.T) {
+	metadata := Metadata{
+		ContentType:      "application/x-protobuf",
+		SchemaID:         3,
+		EventType:        "ticket.created",
+		EventVersion:     "1",
+		ProducerService:  "producer",
+		ProducerInstance: "producer-0",
+		RequestID:        "request-1",
+		Traceparent:      traceparent,
+		EventTime:        time.Date(2025, 10, 25, 12, 0, 0, 500, time.UTC),
+	}
+
+	headers := metadata.Headers()
+
+	require.Equal(t, []kafka.Header{
+		{Key: "content-type", Value: []byte("application/x-protobuf")},
+		{Key: "schema-id", Value: []byte("3")},
+		{Key: "event-type", Value: []byte("ticket.created")},
+		{Key: "event-version", Value: []byte("1")},
+		{Key: "producer-service", Value: []byte("producer")},
+		{Key: "producer-instance", Value: []byte("producer-0")},
+		{Key: "request-id", Value: []byte("request-1")},
+		{Key: "traceparent", Value: []byte(traceparent)},
+		{Key: "event-time", Value: []byte("2025-10-25T12:00:00.0000005Z")},
+	}, headers)
+	require.Equal(t, metadata, FromHeaders(headers))
+	require.Empty(t, Metadata{}.Headers())
+}
+
+func TestFromHeaders_IgnoresMalformedValues(t *testing.T) {
+	metadata := FromHeaders([]kafka.Header{
+		{Key: "schema-id", Value: []byte("three")},
+		{Key: "traceparent", Value: []byte("00-invalid")},
+		{Key: "event-time", Value: []byte("yesterday")},
+		{Key: "unknown", Value: []byte("value")},
+		{Key: "request-id", Value: []byte("request-1")},
+	})
+
+	require.Equal(t, Metadata{RequestID: "request-1"}, metadata)
+}
+
+func TestContext(t *testing.T) {
+	ctx := context.Background()
+	_, ok := FromContext(ctx)
+	require.False(t, ok)
+	require.Empty(t, RequestID(ctx))
+
+	ctx = NewContext(ctx, Metadata{RequestID: "request-1", Traceparent: traceparent})
+
+	metadata, ok := FromContext(ctx)
+	require.True(t, ok)
+	require.Equal(t, traceparent, metadata.Traceparent)
+	require.Equal(t, "request-1", RequestID(ctx))
+	require.Len(t, NewRequestID(), 32)
+}
+
+func TestValidTraceparent(t *testing.T) {
+	tests := []struct {
+		traceparent string
+		expected    bool
+	}{
+		{traceparent: traceparent, expected: true},
+		{traceparent: "ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", expected: false},
+		{traceparent: "00-4BF92F3577B34DA6A3CE929D0E0E4736-00f067aa0ba902b7-01", expected: false},
+		{traceparent: "", expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.traceparent, func(t *testing.T) {
+			require.Equal(t, tt.expected, ValidTraceparent(tt.traceparent))
+		})
+	}
+}
+
+func TestMetadata_LogAttrs(t *testing.T) {
+	attrs := Metadata{RequestID: "request-1", ProducerService: "producer", SchemaID: 2}.LogAttrs()
+
+	require.Equal(t, []any{"producer_service", "producer"}, attrs)
+}
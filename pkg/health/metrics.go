@@ -0,0 +1,39 @@
+package health
+
+import "github.com/prometheus/client_golang/prometheus"
+
+type metrics struct {
+	DependencyUp      *prometheus.GaugeVec
+	DependencyLatency *prometheus.GaugeVec
+}
+
+var metric = metrics{
+	DependencyUp: prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "dependency_up",
+			Help: "whether the last health check of a dependency succeeded",
+		},
+		[]string{"name"},
+	),
+	DependencyLatency: prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "dependency_check_latency_seconds",
+			Help: "latency of the last health check of a dependency",
+		},
+		[]string{"name"},
+	),
+}
+
+func MustRegister() {
+	prometheus.MustRegister(metric.DependencyUp)
+	prometheus.MustRegister(metric.DependencyLatency)
+}
+
+func recordResult(result CheckResult) {
+	up := 0.0
+	if result.Status == StatusHealthy {
+		up = 1
+	}
+	metric.DependencyUp.WithLabelValues(result.Name).Set(up)
+	metric.DependencyLatency.WithLabelValues(result.Name).Set(result.Latency.Seconds())
+}
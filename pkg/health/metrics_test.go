@@ -0,0 +1,31 @@
+package health
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMustRegister(t *testing.T) {
+	MustRegister()
+
+	assert.True(t, prometheus.Unregister(metric.DependencyUp))
+	assert.True(t, prometheus.Unregister(metric.DependencyLatency))
+}
+
+func TestRecordResult(t *testing.T) {
+	metric.DependencyUp.Reset()
+	metric.DependencyLatency.Reset()
+
+	recordResult(CheckResult{Name: "kafka", Status: StatusHealthy, Latency: 250 * time.Millisecond})
+	recordResult(CheckResult{Name: "postgres", Status: StatusUnhealthy, Latency: time.Second})
+
+	require.Equal(t, 1.0, testutil.ToFloat64(metric.DependencyUp.WithLabelValues("kafka")))
+	require.Equal(t, 0.0, testutil.ToFloat64(metric.DependencyUp.WithLabelValues("postgres")))
+	require.Equal(t, 0.25, testutil.ToFloat64(metric.DependencyLatency.WithLabelValues("kafka")))
+	require.Equal(t, 1.0, testutil.ToFloat64(metric.DependencyLatency.WithLabelValues("postgres")))
+}
@@ -0,0 +1,98 @@
+package health
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+)
+
+// Monitor runs the health checks of a service in the background so that
+// requests are answered from the last report instead of pinging dependencies.
+type Monitor struct {
+	service  *Service
+	interval time.Duration
+	maxAge   time.Duration
+
+	refreshMu sync.Mutex
+	mu        sync.RWMutex
+	report    Report
+	updatedAt time.Time
+}
+
+// NewMonitor refreshes the report every interval, reports older than maxAge are
+// considered stale and are refreshed on the next Check.
+func NewMonitor(service *Service, interval, maxAge time.Duration) *Monitor {
+	return &Monitor{
+		service:  service,
+		interval: interval,
+		maxAge:   maxAge,
+	}
+}
+
+func (m *Monitor) Run(ctx context.Context, errChan chan error) {
+	ticker := time.NewTicker(m.interval)
+	defer ticker.Stop()
+
+	m.Refresh(ctx)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			m.Refresh(ctx)
+		}
+	}
+}
+
+func (m *Monitor) Check(ctx context.Context) Report {
+	if report, ok := m.cached(); ok {
+		return report
+	}
+
+	m.refreshMu.Lock()
+	defer m.refreshMu.Unlock()
+
+	if report, ok := m.cached(); ok {
+		return report
+	}
+
+	// The report is shared with the waiting requests, the refresh outlives the
+	// request that started it.
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), m.service.timeout())
+	defer cancel()
+	return m.refresh(ctx)
+}
+
+func (m *Monitor) Refresh(ctx context.Context) Report {
+	m.refreshMu.Lock()
+	defer m.refreshMu.Unlock()
+
+	return m.refresh(ctx)
+}
+
+func (m *Monitor) refresh(ctx context.Context) Report {
+	report := m.service.Check(ctx)
+
+	m.mu.Lock()
+	m.report = report
+	m.updatedAt = time.Now()
+	m.mu.Unlock()
+
+	return cloneReport(report)
+}
+
+func (m *Monitor) cached() (Report, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if m.updatedAt.IsZero() || time.Since(m.updatedAt) > m.maxAge {
+		return Report{}, false
+	}
+	return cloneReport(m.report), true
+}
+
+func cloneReport(report Report) Report {
+	report.Checks = slices.Clone(report.Checks)
+	return report
+}
@@ -0,0 +1,114 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMonitor_Check(t *testing.T) {
+	type testCase struct {
+		name          string
+		maxAge        time.Duration
+		expectedPings int
+	}
+
+	tests := []testCase{
+		{
+			name:          "fresh report is served from the cache",
+			maxAge:        time.Minute,
+			expectedPings: 1,
+		},
+		{
+			name:          "stale report is refreshed",
+			maxAge:        0,
+			expectedPings: 3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			check := &MockHealthCheck{}
+			check.On("Ping").Return(nil)
+
+			monitor := NewMonitor(NewService(map[string]HealthCheck{"check1": check}), time.Minute, tt.maxAge)
+
+			for range 3 {
+				report := monitor.Check(context.Background())
+				require.Equal(t, StatusHealthy, report.Status)
+				require.Len(t, report.Checks, 1)
+			}
+
+			check.AssertNumberOfCalls(t, "Ping", tt.expectedPings)
+		})
+	}
+}
+
+type CountingHealthCheck struct {
+	pings atomic.Int32
+	err   error
+}
+
+func (c *CountingHealthCheck) Ping(ctx context.Context) error {
+	c.pings.Add(1)
+	return c.err
+}
+
+func TestMonitor_Run(t *testing.T) {
+	check := &CountingHealthCheck{err: errors.New("connection refused")}
+
+	monitor := NewMonitor(NewService(map[string]HealthCheck{"check1": check}), 5*time.Millisecond, time.Minute)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		monitor.Run(ctx, make(chan error))
+	}()
+
+	require.Eventually(t, func() bool {
+		return check.pings.Load() >= 3
+	}, time.Second, time.Millisecond)
+
+	cancel()
+	<-done
+
+	pings := check.pings.Load()
+	report := monitor.Check(context.Background())
+	require.Equal(t, StatusUnhealthy, report.Status)
+	require.Equal(t, "connection refused", report.Checks[0].Error)
+	require.Equal(t, pings, check.pings.Load())
+}
+
+func TestMonitor_CheckReturnsCopy(t *testing.T) {
+	check := &MockHealthCheck{}
+	check.On("Ping").Return(nil)
+
+	monitor := NewMonitor(NewService(map[string]HealthCheck{"check1": check}), time.Minute, time.Minute)
+
+	report := monitor.Check(context.Background())
+	report.Checks[0].Status = StatusUnhealthy
+
+	require.Equal(t, StatusHealthy, monitor.Check(context.Background()).Checks[0].Status)
+	check.AssertNumberOfCalls(t, "Ping", 1)
+}
+
+type ContextHealthCheck struct{}
+
+func (ContextHealthCheck) Ping(ctx context.Context) error {
+	return ctx.Err()
+}
+
+func TestMonitor_CheckOutlivesTheRequest(t *testing.T) {
+	monitor := NewMonitor(NewService(map[string]HealthCheck{"check1": ContextHealthCheck{}}), time.Minute, time.Minute)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	require.Equal(t, StatusHealthy, monitor.Check(ctx).Status)
+	require.Equal(t, StatusHealthy, monitor.Check(context.Background()).Status, "the report of the cancelled request is cached")
+}
@@ -2,54 +2,218 @@ package health
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"log/slog"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+const DefaultTimeout = 2 * time.Second
+
+type Status string
+
+const (
+	StatusHealthy   Status = "healthy"
+	StatusDegraded  Status = "degraded"
+	StatusUnhealthy Status = "unhealthy"
 )
 
 type HealthCheck interface {
 	Ping(ctx context.Context) error
 }
 
-type Service struct {
-	checks map[string]HealthCheck
+// Inspector is implemented by checks that report details about the dependency along with its health.
+type Inspector interface {
+	Inspect(ctx context.Context) (map[string]string, error)
 }
 
-func NewService(checks map[string]HealthCheck) *Service {
-	return &Service{checks: checks}
+type CheckOptions struct {
+	// NonCritical checks report a degraded status instead of unhealthy when they fail.
+	NonCritical bool
+	Timeout     time.Duration
 }
 
-type healthCheckResult struct {
-	name string
-	err  error
+// Check attaches options to a HealthCheck. Checks that are not wrapped are
+// critical and use DefaultTimeout.
+type Check struct {
+	HealthCheck
+	Options CheckOptions
 }
 
-func (s *Service) Ping(ctx context.Context) error {
-	if len(s.checks) == 0 {
-		return nil
+func NewCheck(check HealthCheck, options CheckOptions) *Check {
+	return &Check{
+		HealthCheck: check,
+		Options:     options,
 	}
+}
 
-	checkCtx, cancel := context.WithCancel(ctx)
-	defer cancel()
+type CheckResult struct {
+	Name      string            `json:"name"`
+	Status    Status            `json:"status"`
+	Critical  bool              `json:"critical"`
+	Latency   time.Duration     `json:"-"`
+	LatencyMs float64           `json:"latency_ms"`
+	Error     string            `json:"error,omitempty"`
+	Details   map[string]string `json:"details,omitempty"`
+}
+
+type Report struct {
+	Status Status        `json:"status"`
+	Checks []CheckResult `json:"checks,omitempty"`
+}
+
+func (r Report) HTTPStatus() int {
+	if r.Status == StatusUnhealthy {
+		return http.StatusServiceUnavailable
+	}
+	return http.StatusOK
+}
+
+type Service struct {
+	checks   map[string]*Check
+	mu       sync.Mutex
+	statuses map[string]Status
+}
 
-	results := make(chan healthCheckResult, len(s.checks))
+func NewService(checks map[string]HealthCheck) *Service {
+	service := &Service{
+		checks:   make(map[string]*Check, len(checks)),
+		statuses: make(map[string]Status, len(checks)),
+	}
+	for name, check := range checks {
+		if c, ok := check.(*Check); ok {
+			service.checks[name] = c
+			continue
+		}
+		service.checks[name] = NewCheck(check, CheckOptions{})
+	}
+	return service
+}
 
+// Check runs every health check concurrently, each bounded by its own timeout,
+// and reports the result of all of them.
+func (s *Service) Check(ctx context.Context) Report {
+	results := make(chan CheckResult, len(s.checks))
 	for name, check := range s.checks {
-		go func(name string, check HealthCheck) {
-			err := check.Ping(checkCtx)
-			results <- healthCheckResult{name: name, err: err}
+		go func(name string, check *Check) {
+			results <- runCheck(ctx, name, check)
 		}(name, check)
 	}
 
+	report := Report{Status: StatusHealthy, Checks: make([]CheckResult, 0, len(s.checks))}
 	for range len(s.checks) {
-		select {
-		case <-ctx.Done():
-			return ctx.Err()
-		case res := <-results:
-			if res.err != nil {
-				slog.Error("health check failed", "service", res.name, "error", res.err.Error())
-				return res.err
+		result := <-results
+		report.Checks = append(report.Checks, result)
+		s.record(result)
+
+		if result.Status == StatusUnhealthy {
+			if result.Critical {
+				report.Status = StatusUnhealthy
+			} else if report.Status == StatusHealthy {
+				report.Status = StatusDegraded
 			}
 		}
 	}
 
-	return nil
+	slices.SortFunc(report.Checks, func(a, b CheckResult) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	return report
+}
+
+// record exports the result and logs it only when the status of the check changed.
+func (s *Service) record(result CheckResult) {
+	recordResult(result)
+
+	s.mu.Lock()
+	previous, seen := s.statuses[result.Name]
+	s.statuses[result.Name] = result.Status
+	s.mu.Unlock()
+
+	if previous == result.Status || (!seen && result.Status == StatusHealthy) {
+		return
+	}
+
+	if result.Status == StatusHealthy {
+		slog.Info("health check recovered", "service", result.Name, "latency_ms", result.LatencyMs)
+	} else {
+		slog.Error("health check failed", "service", result.Name, "critical", result.Critical, "error", result.Error)
+	}
+}
+
+// Ping returns an error describing every failed critical check.
+func (s *Service) Ping(ctx context.Context) error {
+	var errs []error
+	for _, result := range s.Check(ctx).Checks {
+		if result.Critical && result.Status == StatusUnhealthy {
+			errs = append(errs, fmt.Errorf("%s: %s", result.Name, result.Error))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// timeout returns the longest timeout of the checks.
+func (s *Service) timeout() time.Duration {
+	longest := DefaultTimeout
+	for _, check := range s.checks {
+		longest = max(longest, check.Options.Timeout)
+	}
+	return longest
+}
+
+func runCheck(ctx context.Context, name string, check *Check) CheckResult {
+	timeout := check.Options.Timeout
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+
+	checkCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	type outcome struct {
+		details map[string]string
+		err     error
+	}
+
+	start := time.Now()
+	outcomes := make(chan outcome, 1)
+	go func() {
+		if inspector, ok := check.HealthCheck.(Inspector); ok {
+			details, err := inspector.Inspect(checkCtx)
+			outcomes <- outcome{details: details, err: err}
+			return
+		}
+		outcomes <- outcome{err: check.Ping(checkCtx)}
+	}()
+
+	var details map[string]string
+	var err error
+	select {
+	case o := <-outcomes:
+		details, err = o.details, o.err
+	case <-checkCtx.Done():
+		err = fmt.Errorf("health check timed out after %s: %w", timeout, checkCtx.Err())
+	}
+
+	latency := time.Since(start)
+	result := CheckResult{
+		Name:      name,
+		Status:    StatusHealthy,
+		Critical:  !check.Options.NonCritical,
+		Latency:   latency,
+		LatencyMs: float64(latency.Microseconds()) / 1000,
+		Details:   details,
+	}
+
+	if err != nil {
+		result.Status = StatusUnhealthy
+		result.Error = err.Error()
+	}
+
+	return result
 }
@@ -3,6 +3,7 @@ package health
 import (
 	"context"
 	"errors"
+	"net/http"
 	"testing"
 	"time"
 
@@ -65,7 +66,7 @@ func TestService_Ping(t *testing.T) {
 					"check3": check3,
 				}
 			}(),
-			expectedError: errors.New("check2 error"),
+			expectedError: errors.New("check2: check2 error"),
 		},
 		{
 			name: "multiple checks fail",
@@ -87,7 +88,23 @@ func TestService_Ping(t *testing.T) {
 					"check3": check3,
 				}
 			}(),
-			expectedError: errors.New("check2 error"),
+			expectedError: errors.Join(errors.New("check2: check2 error"), errors.New("check3: check3 error")),
+		},
+		{
+			name: "non-critical check fails",
+			checks: func() map[string]HealthCheck {
+				check1 := &MockHealthCheck{}
+				check1.On("Ping").Return(nil)
+
+				check2 := &MockHealthCheck{}
+				check2.On("Ping").Return(errors.New("check2 error"))
+
+				return map[string]HealthCheck{
+					"check1": check1,
+					"check2": NewCheck(check2, CheckOptions{NonCritical: true}),
+				}
+			}(),
+			expectedError: nil,
 		},
 		{
 			name: "no checks configured",
@@ -103,9 +120,16 @@ func TestService_Ping(t *testing.T) {
 			service := NewService(tt.checks)
 
 			err := service.Ping(t.Context())
-			require.Equal(t, tt.expectedError, err)
+			if tt.expectedError != nil {
+				require.EqualError(t, err, tt.expectedError.Error())
+			} else {
+				require.NoError(t, err)
+			}
 
 			for _, check := range tt.checks {
+				if c, ok := check.(*Check); ok {
+					check = c.HealthCheck
+				}
 				if mockCheck, ok := check.(*MockHealthCheck); ok {
 					mockCheck.AssertExpectations(t)
 				}
@@ -113,3 +137,128 @@ func TestService_Ping(t *testing.T) {
 		})
 	}
 }
+
+type MockInspector struct {
+	MockHealthCheck
+}
+
+func (m *MockInspector) Inspect(ctx context.Context) (map[string]string, error) {
+	args := m.Called()
+	return args.Get(0).(map[string]string), args.Error(1)
+}
+
+func TestService_Check(t *testing.T) {
+	type testCase struct {
+		name           string
+		checks         map[string]HealthCheck
+		expectedStatus Status
+		expectedHTTP   int
+		expectedChecks []CheckResult
+	}
+
+	tests := []testCase{
+		{
+			name: "all checks pass",
+			checks: func() map[string]HealthCheck {
+				check1 := &MockHealthCheck{}
+				check1.On("Ping").Return(nil)
+				return map[string]HealthCheck{"check1": check1}
+			}(),
+			expectedStatus: StatusHealthy,
+			expectedHTTP:   http.StatusOK,
+			expectedChecks: []CheckResult{
+				{Name: "check1", Status: StatusHealthy, Critical: true},
+			},
+		},
+		{
+			name: "non-critical failure is degraded",
+			checks: func() map[string]HealthCheck {
+				check1 := &MockHealthCheck{}
+				check1.On("Ping").Return(nil)
+
+				check2 := &MockHealthCheck{}
+				check2.On("Ping").Return(errors.New("check2 error"))
+
+				return map[string]HealthCheck{
+					"check1": check1,
+					"check2": NewCheck(check2, CheckOptions{NonCritical: true}),
+				}
+			}(),
+			expectedStatus: StatusDegraded,
+			expectedHTTP:   http.StatusOK,
+			expectedChecks: []CheckResult{
+				{Name: "check1", Status: StatusHealthy, Critical: true},
+				{Name: "check2", Status: StatusUnhealthy, Critical: false, Error: "check2 error"},
+			},
+		},
+		{
+			name: "critical failure is unhealthy",
+			checks: func() map[string]HealthCheck {
+				check1 := &MockHealthCheck{}
+				check1.On("Ping").Return(errors.New("check1 error"))
+
+				check2 := &MockHealthCheck{}
+				check2.On("Ping").Return(errors.New("check2 error"))
+
+				return map[string]HealthCheck{
+					"check1": check1,
+					"check2": NewCheck(check2, CheckOptions{NonCritical: true}),
+				}
+			}(),
+			expectedStatus: StatusUnhealthy,
+			expectedHTTP:   http.StatusServiceUnavailable,
+			expectedChecks: []CheckResult{
+				{Name: "check1", Status: StatusUnhealthy, Critical: true, Error: "check1 error"},
+				{Name: "check2", Status: StatusUnhealthy, Critical: false, Error: "check2 error"},
+			},
+		},
+		{
+			name: "inspector reports details",
+			checks: func() map[string]HealthCheck {
+				check1 := &MockInspector{}
+				check1.On("Inspect").Return(map[string]string{"group_state": "Stable"}, nil)
+				return map[string]HealthCheck{"check1": check1}
+			}(),
+			expectedStatus: StatusHealthy,
+			expectedHTTP:   http.StatusOK,
+			expectedChecks: []CheckResult{
+				{Name: "check1", Status: StatusHealthy, Critical: true, Details: map[string]string{"group_state": "Stable"}},
+			},
+		},
+		{
+			name: "slow check times out without a caller deadline",
+			checks: func() map[string]HealthCheck {
+				check1 := &MockHealthCheck{}
+				check1.On("Ping").Run(func(args mock.Arguments) {
+					time.Sleep(200 * time.Millisecond)
+				}).Return(nil)
+
+				return map[string]HealthCheck{
+					"check1": NewCheck(check1, CheckOptions{Timeout: 10 * time.Millisecond}),
+				}
+			}(),
+			expectedStatus: StatusUnhealthy,
+			expectedHTTP:   http.StatusServiceUnavailable,
+			expectedChecks: []CheckResult{
+				{Name: "check1", Status: StatusUnhealthy, Critical: true, Error: "health check timed out after 10ms: context deadline exceeded"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := NewService(tt.checks)
+
+			report := service.Check(context.Background())
+			require.Equal(t, tt.expectedStatus, report.Status)
+			require.Equal(t, tt.expectedHTTP, report.HTTPStatus())
+
+			for i := range report.Checks {
+				require.GreaterOrEqual(t, report.Checks[i].Latency, time.Duration(0))
+				report.Checks[i].Latency = 0
+				report.Checks[i].LatencyMs = 0
+			}
+			require.Equal(t, tt.expectedChecks, report.Checks)
+		})
+	}
+}
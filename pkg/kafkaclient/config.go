@@ -0,0 +1,165 @@
+package kafkaclient
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"os"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+	"github.com/segmentio/kafka-go/sasl"
+	"github.com/segmentio/kafka-go/sasl/plain"
+	"github.com/segmentio/kafka-go/sasl/scram"
+)
+
+const (
+	MechanismPlain       = "plain"
+	MechanismSCRAMSHA256 = "scram-sha-256"
+	MechanismSCRAMSHA512 = "scram-sha-512"
+)
+
+const DefaultDialTimeout = 10 * time.Second
+
+// Config is the connection configuration shared by the writers, readers and
+// clients of a service so they all reach the brokers the same way.
+type Config struct {
+	Brokers []string `mapstructure:"brokers" validate:"min=1,dive,hostname_port"`
+	TLS     TLS      `mapstructure:"tls"`
+	SASL    SASL     `mapstructure:"sasl"`
+}
+
+type TLS struct {
+	Enabled bool `mapstructure:"enabled"`
+	// CAFile verifies the brokers with the given CA bundle instead of the system roots.
+	CAFile   string `mapstructure:"ca_file"`
+	CertFile string `mapstructure:"cert_file" validate:"required_with=KeyFile"`
+	KeyFile  string `mapstructure:"key_file" validate:"required_with=CertFile"`
+	// ServerName overrides the host name used to verify the broker certificates.
+	ServerName         string `mapstructure:"server_name"`
+	InsecureSkipVerify bool   `mapstructure:"insecure_skip_verify"`
+}
+
+type SASL struct {
+	// Mechanism is empty when SASL is disabled.
+	Mechanism string `mapstructure:"mechanism" validate:"omitempty,oneof=plain scram-sha-256 scram-sha-512"`
+	Username  string `mapstructure:"username" validate:"required_with=Mechanism"`
+	Password  string `mapstructure:"password" json:"-" validate:"required_with=Mechanism"`
+}
+
+func New(brokers ...string) Config {
+	return Config{Brokers: brokers}
+}
+
+// Addr returns the seed brokers, the first reachable one is used to discover the cluster.
+func (c Config) Addr() net.Addr {
+	return kafka.TCP(c.Brokers...)
+}
+
+// TLSConfig returns nil when TLS is disabled.
+func (c Config) TLSConfig() (*tls.Config, error) {
+	if !c.TLS.Enabled {
+		return nil, nil
+	}
+
+	config := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		ServerName:         c.TLS.ServerName,
+		InsecureSkipVerify: c.TLS.InsecureSkipVerify,
+	}
+
+	if c.TLS.CAFile != "" {
+		ca, err := os.ReadFile(c.TLS.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("read kafka ca file: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(ca) {
+			return nil, fmt.Errorf("kafka ca file %s contains no certificates", c.TLS.CAFile)
+		}
+		config.RootCAs = pool
+	}
+
+	if c.TLS.CertFile != "" {
+		certificate, err := tls.LoadX509KeyPair(c.TLS.CertFile, c.TLS.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("load kafka client certificate: %w", err)
+		}
+		config.Certificates = []tls.Certificate{certificate}
+	}
+
+	return config, nil
+}
+
+// SASLMechanism returns nil when SASL is disabled.
+func (c Config) SASLMechanism() (sasl.Mechanism, error) {
+	switch c.SASL.Mechanism {
+	case "":
+		return nil, nil
+	case MechanismPlain:
+		return plain.Mechanism{Username: c.SASL.Username, Password: c.SASL.Password}, nil
+	case MechanismSCRAMSHA256:
+		return scram.Mechanism(scram.SHA256, c.SASL.Username, c.SASL.Password)
+	case MechanismSCRAMSHA512:
+		return scram.Mechanism(scram.SHA512, c.SASL.Username, c.SASL.Password)
+	default:
+		return nil, fmt.Errorf("unsupported kafka sasl mechanism %q", c.SASL.Mechanism)
+	}
+}
+
+// Dialer is used by readers and direct connections.
+func (c Config) Dialer() (*kafka.Dialer, error) {
+	tlsConfig, mechanism, err := c.security()
+	if err != nil {
+		return nil, err
+	}
+
+	return &kafka.Dialer{
+		Timeout:       DefaultDialTimeout,
+		DualStack:     true,
+		TLS:           tlsConfig,
+		SASLMechanism: mechanism,
+	}, nil
+}
+
+// Transport is used by writers and clients.
+func (c Config) Transport() (*kafka.Transport, error) {
+	tlsConfig, mechanism, err := c.security()
+	if err != nil {
+		return nil, err
+	}
+
+	return &kafka.Transport{
+		DialTimeout: DefaultDialTimeout,
+		TLS:         tlsConfig,
+		SASL:        mechanism,
+	}, nil
+}
+
+// Client returns a client reaching every seed broker through the configured transport.
+func (c Config) Client() (*kafka.Client, error) {
+	transport, err := c.Transport()
+	if err != nil {
+		return nil, err
+	}
+
+	return &kafka.Client{
+		Addr:      c.Addr(),
+		Transport: transport,
+	}, nil
+}
+
+func (c Config) security() (*tls.Config, sasl.Mechanism, error) {
+	tlsConfig, err := c.TLSConfig()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	mechanism, err := c.SASLMechanism()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return tlsConfig, mechanism, nil
+}
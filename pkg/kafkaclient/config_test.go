@@ -0,0 +1,182 @@
+package kafkaclient
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	pkgconfig "github.com/iamnotrodger/golang-projects/pkg/config"
+	"github.com/stretchr/testify/require"
+)
+
+// writeCertificate writes a self-signed certificate and its key, it is used both as the CA and the client certificate.
+func writeCertificate(t *testing.T) (string, string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "kafka-test"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	dir := t.TempDir()
+	certFile := filepath.Join(dir, "cert.pem")
+	keyFile := filepath.Join(dir, "key.pem")
+	require.NoError(t, os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600))
+	require.NoError(t, os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer}), 0o600))
+	return certFile, keyFile
+}
+
+func TestConfig_TLSConfig(t *testing.T) {
+	certFile, keyFile := writeCertificate(t)
+
+	t.Run("disabled", func(t *testing.T) {
+		tlsConfig, err := New("broker:9092").TLSConfig()
+		require.NoError(t, err)
+		require.Nil(t, tlsConfig)
+	})
+
+	t.Run("ca, client certificate and server name", func(t *testing.T) {
+		config := New("broker:9092")
+		config.TLS = TLS{
+			Enabled:    true,
+			CAFile:     certFile,
+			CertFile:   certFile,
+			KeyFile:    keyFile,
+			ServerName: "kafka.internal",
+		}
+
+		tlsConfig, err := config.TLSConfig()
+		require.NoError(t, err)
+		require.Equal(t, "kafka.internal", tlsConfig.ServerName)
+		require.Equal(t, uint16(tls.VersionTLS12), tlsConfig.MinVersion)
+		require.NotNil(t, tlsConfig.RootCAs)
+		require.Len(t, tlsConfig.Certificates, 1)
+	})
+
+	t.Run("invalid ca file", func(t *testing.T) {
+		config := New("broker:9092")
+		config.TLS = TLS{Enabled: true, CAFile: keyFile}
+
+		_, err := config.TLSConfig()
+		require.EqualError(t, err, "kafka ca file "+keyFile+" contains no certificates")
+	})
+
+	t.Run("missing client key", func(t *testing.T) {
+		config := New("broker:9092")
+		config.TLS = TLS{Enabled: true, CertFile: certFile, KeyFile: filepath.Join(t.TempDir(), "missing.pem")}
+
+		_, err := config.TLSConfig()
+		require.ErrorContains(t, err, "load kafka client certificate")
+	})
+}
+
+func TestConfig_SASLMechanism(t *testing.T) {
+	tests := []struct {
+		mechanism     string
+		expectedName  string
+		expectedError string
+	}{
+		{mechanism: ""},
+		{mechanism: MechanismPlain, expectedName: "PLAIN"},
+		{mechanism: MechanismSCRAMSHA256, expectedName: "SCRAM-SHA-256"},
+		{mechanism: MechanismSCRAMSHA512, expectedName: "SCRAM-SHA-512"},
+		{mechanism: "gssapi", expectedError: `unsupported kafka sasl mechanism "gssapi"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.mechanism, func(t *testing.T) {
+			config := New("broker:9092")
+			config.SASL = SASL{Mechanism: tt.mechanism, Username: "user", Password: "secret"}
+
+			mechanism, err := config.SASLMechanism()
+			if tt.expectedError != "" {
+				require.EqualError(t, err, tt.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			if tt.expectedName == "" {
+				require.Nil(t, mechanism)
+				return
+			}
+			require.Equal(t, tt.expectedName, mechanism.Name())
+		})
+	}
+}
+
+func TestConfig_DialerAndTransport(t *testing.T) {
+	certFile, _ := writeCertificate(t)
+
+	config := New("broker-1:9092", "broker-2:9092")
+	config.TLS = TLS{Enabled: true, CAFile: certFile}
+	config.SASL = SASL{Mechanism: MechanismSCRAMSHA512, Username: "user", Password: "secret"}
+
+	dialer, err := config.Dialer()
+	require.NoError(t, err)
+	require.NotNil(t, dialer.TLS)
+	require.Equal(t, "SCRAM-SHA-512", dialer.SASLMechanism.Name())
+
+	transport, err := config.Transport()
+	require.NoError(t, err)
+	require.NotNil(t, transport.TLS.RootCAs)
+	require.Equal(t, "SCRAM-SHA-512", transport.SASL.Name())
+
+	client, err := config.Client()
+	require.NoError(t, err)
+	require.Equal(t, "broker-1:9092,broker-2:9092", client.Addr.String())
+
+	config.SASL.Mechanism = "gssapi"
+	_, err = config.Dialer()
+	require.Error(t, err)
+	_, err = config.Transport()
+	require.Error(t, err)
+}
+
+type testSpec struct {
+	Kafka Config `mapstructure:"kafka"`
+}
+
+func TestConfig_Validate(t *testing.T) {
+	config := &testSpec{Kafka: Config{
+		TLS:  TLS{Enabled: true, CertFile: "cert.pem"},
+		SASL: SASL{Mechanism: "oauth"},
+	}}
+
+	err := pkgconfig.Validate(config)
+
+	require.EqualError(t, err, "invalid configuration:"+
+		"\n  kafka.brokers: must have at least 1 elements"+
+		"\n  kafka.tls.key_file: is required when cert_file is set"+
+		"\n  kafka.sasl.mechanism: must be one of [plain scram-sha-256 scram-sha-512], got \"oauth\""+
+		"\n  kafka.sasl.username: is required when mechanism is set"+
+		"\n  kafka.sasl.password: is required when mechanism is set")
+}
+
+func TestConfig_SnapshotRedactsPassword(t *testing.T) {
+	config := &testSpec{Kafka: New("broker:9092")}
+	config.Kafka.SASL = SASL{Mechanism: MechanismPlain, Username: "user", Password: "secret"}
+
+	snapshot, err := pkgconfig.NewSnapshot(config, nil)
+
+	require.NoError(t, err)
+	require.Equal(t, pkgconfig.Redacted, snapshot["kafka.sasl.password"].Value)
+	require.Equal(t, "user", snapshot["kafka.sasl.username"].Value)
+}
@@ -0,0 +1,36 @@
+package kafkaclient
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/segmentio/kafka-go"
+)
+
+// ValidateTopic checks that the topic exists in metadata and that every
+// partition has a leader, it returns the number of partitions.
+func ValidateTopic(topic string, metadata *kafka.MetadataResponse) (int, error) {
+	for _, t := range metadata.Topics {
+		if t.Name != topic {
+			continue
+		}
+		if t.Error != nil {
+			return 0, fmt.Errorf("topic %s: %w", topic, t.Error)
+		}
+		if len(t.Partitions) == 0 {
+			return 0, fmt.Errorf("topic %s has no partitions", topic)
+		}
+
+		var errs []error
+		for _, partition := range t.Partitions {
+			if partition.Error != nil {
+				errs = append(errs, fmt.Errorf("topic %s partition %d: %w", topic, partition.ID, partition.Error))
+			} else if partition.Leader.Host == "" {
+				errs = append(errs, fmt.Errorf("topic %s partition %d has no leader", topic, partition.ID))
+			}
+		}
+		return len(t.Partitions), errors.Join(errs...)
+	}
+
+	return 0, fmt.Errorf("topic %s not found", topic)
+}
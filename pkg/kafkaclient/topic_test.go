@@ -0,0 +1,74 @@
+package kafkaclient
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/segmentio/kafka-go"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateTopic(t *testing.T) {
+	leader := kafka.Broker{Host: "broker", Port: 9092, ID: 1}
+
+	tests := []struct {
+		name               string
+		metadata           *kafka.MetadataResponse
+		expectedPartitions int
+		expectedError      string
+	}{
+		{
+			name: "every partition has a leader",
+			metadata: &kafka.MetadataResponse{Topics: []kafka.Topic{{
+				Name:       "tickets",
+				Partitions: []kafka.Partition{{ID: 0, Leader: leader}, {ID: 1, Leader: leader}},
+			}}},
+			expectedPartitions: 2,
+		},
+		{
+			name:          "topic missing from metadata",
+			metadata:      &kafka.MetadataResponse{},
+			expectedError: "topic tickets not found",
+		},
+		{
+			name: "topic error",
+			metadata: &kafka.MetadataResponse{Topics: []kafka.Topic{{
+				Name:  "tickets",
+				Error: kafka.UnknownTopicOrPartition,
+			}}},
+			expectedError: "topic tickets: " + kafka.UnknownTopicOrPartition.Error(),
+		},
+		{
+			name: "topic without partitions",
+			metadata: &kafka.MetadataResponse{Topics: []kafka.Topic{{
+				Name: "tickets",
+			}}},
+			expectedError: "topic tickets has no partitions",
+		},
+		{
+			name: "partitions without leader",
+			metadata: &kafka.MetadataResponse{Topics: []kafka.Topic{{
+				Name: "tickets",
+				Partitions: []kafka.Partition{
+					{ID: 0, Leader: leader},
+					{ID: 1, Leader: kafka.Broker{ID: -1}},
+					{ID: 2, Error: errors.New("leader not available")},
+				},
+			}}},
+			expectedPartitions: 3,
+			expectedError:      "topic tickets partition 1 has no leader\ntopic tickets partition 2: leader not available",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			partitions, err := ValidateTopic("tickets", tt.metadata)
+			require.Equal(t, tt.expectedPartitions, partitions)
+			if tt.expectedError == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.EqualError(t, err, tt.expectedError)
+		})
+	}
+}
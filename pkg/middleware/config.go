@@ -0,0 +1,51 @@
+package middleware
+
+import (
+	"net/http"
+	"time"
+)
+
+// Config is the configuration of the middlewares shared by the HTTP servers.
+type Config struct {
+	AccessLog AccessLogConfig `mapstructure:"access_log"`
+	CORS      CORSConfig      `mapstructure:"cors"`
+	// TrustedProxies are the addresses or CIDRs of the proxies whose
+	// forwarding headers are trusted for the client IP, the remote address is
+	// used when it is empty.
+	TrustedProxies []string `mapstructure:"trusted_proxies" validate:"dive,ip|cidr"`
+}
+
+type AccessLogConfig struct {
+	// SampleRate is the fraction of successful requests logged, client and
+	// server errors are always logged.
+	SampleRate float64 `mapstructure:"sample_rate" validate:"min=0,max=1"`
+	// SkipPaths are never logged, they are matched against the route template
+	// or, for unmatched requests, the path.
+	SkipPaths []string `mapstructure:"skip_paths"`
+}
+
+// CORSConfig allows cross-origin requests from AllowedOrigins, CORS is
+// disabled when it is empty and "*" allows any origin.
+type CORSConfig struct {
+	AllowedOrigins   []string      `mapstructure:"allowed_origins"`
+	AllowedMethods   []string      `mapstructure:"allowed_methods"`
+	AllowedHeaders   []string      `mapstructure:"allowed_headers"`
+	ExposedHeaders   []string      `mapstructure:"exposed_headers"`
+	AllowCredentials bool          `mapstructure:"allow_credentials"`
+	MaxAge           time.Duration `mapstructure:"max_age" validate:"min=0"`
+}
+
+func NewConfig() Config {
+	return Config{
+		AccessLog: AccessLogConfig{
+			SampleRate: 1,
+			SkipPaths:  []string{"/health", "/livez", "/readyz", "/metrics"},
+		},
+		CORS: CORSConfig{
+			AllowedMethods: []string{http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete},
+			AllowedHeaders: []string{"Authorization", "Content-Type", "X-Request-ID"},
+			ExposedHeaders: []string{"X-Request-ID"},
+			MaxAge:         10 * time.Minute,
+		},
+	}
+}
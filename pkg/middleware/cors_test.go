@@ -0,0 +1,97 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCORS(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name            string
+		config          CORSConfig
+		method          string
+		origin          string
+		preflight       bool
+		expectedStatus  int
+		expectedHeaders map[string]string
+	}{
+		{
+			name:            "allows any origin",
+			config:          CORSConfig{AllowedOrigins: []string{"*"}, ExposedHeaders: []string{"X-Request-ID"}},
+			method:          http.MethodGet,
+			origin:          "https://example.com",
+			expectedStatus:  http.StatusOK,
+			expectedHeaders: map[string]string{"Access-Control-Allow-Origin": "*", "Access-Control-Expose-Headers": "X-Request-ID"},
+		},
+		{
+			name:            "echoes the origin with credentials",
+			config:          CORSConfig{AllowedOrigins: []string{"*"}, AllowCredentials: true},
+			method:          http.MethodGet,
+			origin:          "https://example.com",
+			expectedStatus:  http.StatusOK,
+			expectedHeaders: map[string]string{"Access-Control-Allow-Origin": "https://example.com", "Access-Control-Allow-Credentials": "true"},
+		},
+		{
+			name:            "omits the headers for other origins",
+			config:          CORSConfig{AllowedOrigins: []string{"https://example.com"}},
+			method:          http.MethodGet,
+			origin:          "https://other.com",
+			expectedStatus:  http.StatusOK,
+			expectedHeaders: map[string]string{"Access-Control-Allow-Origin": "", "Vary": "Origin"},
+		},
+		{
+			name:            "is disabled without allowed origins",
+			method:          http.MethodGet,
+			origin:          "https://example.com",
+			expectedStatus:  http.StatusOK,
+			expectedHeaders: map[string]string{"Access-Control-Allow-Origin": ""},
+		},
+		{
+			name: "answers preflight requests",
+			config: CORSConfig{
+				AllowedOrigins: []string{"https://example.com"},
+				AllowedMethods: []string{http.MethodGet, http.MethodPost},
+				AllowedHeaders: []string{"Content-Type"},
+				MaxAge:         time.Minute,
+			},
+			method:         http.MethodOptions,
+			origin:         "https://example.com",
+			preflight:      true,
+			expectedStatus: http.StatusNoContent,
+			expectedHeaders: map[string]string{
+				"Access-Control-Allow-Origin":  "https://example.com",
+				"Access-Control-Allow-Methods": "GET, POST",
+				"Access-Control-Allow-Headers": "Content-Type",
+				"Access-Control-Max-Age":       "60",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			engine := gin.New()
+			engine.Use(CORS(tt.config))
+			engine.GET("/leaderboard", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+			req := httptest.NewRequest(tt.method, "/leaderboard", nil)
+			req.Header.Set("Origin", tt.origin)
+			if tt.preflight {
+				req.Header.Set("Access-Control-Request-Method", http.MethodPost)
+			}
+			recorder := httptest.NewRecorder()
+			engine.ServeHTTP(recorder, req)
+
+			require.Equal(t, tt.expectedStatus, recorder.Code)
+			for key, value := range tt.expectedHeaders {
+				require.Equal(t, value, recorder.Header().Get(key), key)
+			}
+		})
+	}
+}
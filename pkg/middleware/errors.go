@@ -0,0 +1,47 @@
+package middleware
+
+import (
+	"log/slog"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/iamnotrodger/golang-projects/pkg/apierror"
+	"github.com/iamnotrodger/golang-projects/pkg/eventmeta"
+)
+
+// Errors answers the requests whose handler added an error to the context
+// without writing a response. Errors other than apierror.Error are reported
+// as internal errors so their text never reaches clients.
+func Errors() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		if len(c.Errors) == 0 || c.Writer.Written() {
+			return
+		}
+
+		err := c.Errors.Last().Err
+		apiErr := apierror.From(err)
+		if apiErr.Status >= 500 {
+			slog.ErrorContext(c.Request.Context(), "request failed", "error", err.Error(), "code", apiErr.Code)
+		}
+		writeError(c, apiErr)
+	}
+}
+
+// writeError writes err as problem details to the clients accepting them and
+// as an apierror.Body otherwise.
+func writeError(c *gin.Context, err *apierror.Error) {
+	if err.RetryAfter > 0 {
+		c.Header("Retry-After", strconv.Itoa(int(math.Ceil(err.RetryAfter.Seconds()))))
+	}
+
+	if strings.Contains(c.GetHeader("Accept"), apierror.ProblemContentType) {
+		c.Header("Content-Type", apierror.ProblemContentType)
+		c.AbortWithStatusJSON(err.Status, err.Problem(c.Request.URL.Path, eventmeta.RequestID(c.Request.Context())))
+		return
+	}
+	c.AbortWithStatusJSON(err.Status, err.Body())
+}
@@ -0,0 +1,94 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/iamnotrodger/golang-projects/pkg/apierror"
+	"github.com/stretchr/testify/require"
+)
+
+func TestErrors(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name                string
+		err                 error
+		accept              string
+		expectedStatus      int
+		expectedContentType string
+		expectedRetryAfter  string
+		expectedBody        string
+	}{
+		{
+			name:                "writes api errors",
+			err:                 apierror.Validation("invalid score", errors.New("json: cannot unmarshal")),
+			expectedStatus:      http.StatusBadRequest,
+			expectedContentType: "application/json; charset=utf-8",
+			expectedBody:        `{"error":"validation_failed","code":400,"description":"invalid score"}`,
+		},
+		{
+			name:                "hides the text of other errors",
+			err:                 errors.New("dial tcp 10.0.0.1:6379: connection refused"),
+			expectedStatus:      http.StatusInternalServerError,
+			expectedContentType: "application/json; charset=utf-8",
+			expectedBody:        `{"error":"internal_error","code":500,"description":"internal server error"}`,
+		},
+		{
+			name:                "asks to retry when a dependency is unavailable",
+			err:                 apierror.Unavailable("redis", errors.New("connection refused")),
+			expectedStatus:      http.StatusServiceUnavailable,
+			expectedContentType: "application/json; charset=utf-8",
+			expectedRetryAfter:  "5",
+			expectedBody:        `{"error":"dependency_unavailable","code":503,"description":"redis is unavailable"}`,
+		},
+		{
+			name:                "writes problem details when accepted",
+			err:                 apierror.NotFound("player not found"),
+			accept:              "application/problem+json, application/json",
+			expectedStatus:      http.StatusNotFound,
+			expectedContentType: apierror.ProblemContentType,
+			expectedBody:        `{"type":"urn:problem-type:not_found","title":"Not Found","status":404,"detail":"player not found","instance":"/fail","code":"not_found"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			engine := gin.New()
+			engine.Use(Errors())
+			engine.GET("/fail", func(c *gin.Context) {
+				c.Error(tt.err)
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/fail", nil)
+			req.Header.Set("Accept", tt.accept)
+			recorder := httptest.NewRecorder()
+			engine.ServeHTTP(recorder, req)
+
+			require.Equal(t, tt.expectedStatus, recorder.Code)
+			require.Equal(t, tt.expectedContentType, recorder.Header().Get("Content-Type"))
+			require.Equal(t, tt.expectedRetryAfter, recorder.Header().Get("Retry-After"))
+			require.JSONEq(t, tt.expectedBody, recorder.Body.String())
+		})
+	}
+}
+
+func TestErrors_Written(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	engine := gin.New()
+	engine.Use(Errors())
+	engine.GET("/fail", func(c *gin.Context) {
+		c.Error(errors.New("already answered"))
+		c.String(http.StatusTeapot, "teapot")
+	})
+
+	recorder := httptest.NewRecorder()
+	engine.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/fail", nil))
+
+	require.Equal(t, http.StatusTeapot, recorder.Code)
+	require.Equal(t, "teapot", recorder.Body.String())
+}
@@ -0,0 +1,86 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLogger(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name          string
+		sampleRate    float64
+		path          string
+		expectedLevel string
+	}{
+		{
+			name:          "logs successful requests",
+			sampleRate:    1,
+			path:          "/tickets/1",
+			expectedLevel: "INFO",
+		},
+		{
+			name:       "samples successful requests",
+			sampleRate: 0,
+			path:       "/tickets/1",
+		},
+		{
+			name:          "always logs client errors",
+			sampleRate:    0,
+			path:          "/unknown",
+			expectedLevel: "WARN",
+		},
+		{
+			name:          "always logs server errors",
+			sampleRate:    0,
+			path:          "/fail",
+			expectedLevel: "ERROR",
+		},
+		{
+			name:       "skips excluded routes",
+			sampleRate: 1,
+			path:       "/health",
+		},
+		{
+			name:       "skips excluded paths without a route",
+			sampleRate: 1,
+			path:       "/metrics",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			defaultLogger := slog.Default()
+			slog.SetDefault(slog.New(slog.NewJSONHandler(&buf, nil)))
+			t.Cleanup(func() { slog.SetDefault(defaultLogger) })
+
+			engine := gin.New()
+			engine.Use(Logger(AccessLogConfig{SampleRate: tt.sampleRate, SkipPaths: []string{"/health", "/metrics"}}))
+			engine.GET("/tickets/:id", func(c *gin.Context) { c.Status(http.StatusOK) })
+			engine.GET("/health", func(c *gin.Context) { c.Status(http.StatusOK) })
+			engine.GET("/fail", func(c *gin.Context) { c.Status(http.StatusInternalServerError) })
+
+			engine.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+			if tt.expectedLevel == "" {
+				require.Empty(t, buf.String())
+				return
+			}
+
+			var entry map[string]any
+			require.NoError(t, json.Unmarshal(buf.Bytes(), &entry))
+			require.Equal(t, tt.expectedLevel, entry["level"])
+			require.Equal(t, "request", entry["msg"])
+			require.Equal(t, tt.path, entry["path"])
+		})
+	}
+}
@@ -0,0 +1,84 @@
+package middleware
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// unmatchedRoute labels the requests matching no route, keeping the label set bounded.
+const unmatchedRoute = "unmatched"
+
+type metrics struct {
+	Requests *prometheus.CounterVec
+	Errors   *prometheus.CounterVec
+	Duration *prometheus.HistogramVec
+	InFlight *prometheus.GaugeVec
+}
+
+var metric = metrics{
+	Requests: prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "http_requests_total",
+			Help: "total number of http requests",
+		},
+		[]string{"route", "method", "status"},
+	),
+	Errors: prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "http_request_errors_total",
+			Help: "total number of http requests answered with a server error",
+		},
+		[]string{"route", "method", "status"},
+	),
+	Duration: prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "http_request_duration_seconds",
+			Help:    "latency of http requests",
+			Buckets: prometheus.DefBuckets,
+		},
+		[]string{"route", "method", "status"},
+	),
+	InFlight: prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "http_requests_in_flight",
+			Help: "number of http requests being served",
+		},
+		[]string{"route", "method"},
+	),
+}
+
+func MustRegister() {
+	prometheus.MustRegister(metric.Requests)
+	prometheus.MustRegister(metric.Errors)
+	prometheus.MustRegister(metric.Duration)
+	prometheus.MustRegister(metric.InFlight)
+}
+
+// Metrics records the rate, errors and duration of requests labelled by route
+// template, method and status. Only 5xx responses count as errors.
+func Metrics() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		route := c.FullPath()
+		if route == "" {
+			route = unmatchedRoute
+		}
+		method := c.Request.Method
+
+		inFlight := metric.InFlight.WithLabelValues(route, method)
+		inFlight.Inc()
+		defer inFlight.Dec()
+
+		start := time.Now()
+		c.Next()
+
+		status := strconv.Itoa(c.Writer.Status())
+		metric.Requests.WithLabelValues(route, method, status).Inc()
+		metric.Duration.WithLabelValues(route, method, status).Observe(time.Since(start).Seconds())
+		if c.Writer.Status() >= 500 {
+			metric.Errors.WithLabelValues(route, method, status).Inc()
+		}
+	}
+}
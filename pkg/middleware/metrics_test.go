@@ -0,0 +1,59 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMustRegister(t *testing.T) {
+	MustRegister()
+
+	assert.True(t, prometheus.Unregister(metric.Requests))
+	assert.True(t, prometheus.Unregister(metric.Errors))
+	assert.True(t, prometheus.Unregister(metric.Duration))
+	assert.True(t, prometheus.Unregister(metric.InFlight))
+}
+
+func TestMetrics(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	metric.Requests.Reset()
+	metric.Errors.Reset()
+	metric.Duration.Reset()
+	metric.InFlight.Reset()
+
+	var inFlight float64
+	engine := gin.New()
+	engine.Use(Metrics())
+	engine.GET("/tickets/:id", func(c *gin.Context) {
+		inFlight = testutil.ToFloat64(metric.InFlight.WithLabelValues("/tickets/:id", http.MethodGet))
+		c.Status(http.StatusOK)
+	})
+	engine.POST("/tickets", func(c *gin.Context) {
+		c.Status(http.StatusInternalServerError)
+	})
+
+	for _, req := range []*http.Request{
+		httptest.NewRequest(http.MethodGet, "/tickets/1", nil),
+		httptest.NewRequest(http.MethodGet, "/tickets/2", nil),
+		httptest.NewRequest(http.MethodPost, "/tickets", nil),
+		httptest.NewRequest(http.MethodGet, "/unknown", nil),
+	} {
+		engine.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	require.Equal(t, 1.0, inFlight)
+	require.Equal(t, 0.0, testutil.ToFloat64(metric.InFlight.WithLabelValues("/tickets/:id", http.MethodGet)))
+	require.Equal(t, 2.0, testutil.ToFloat64(metric.Requests.WithLabelValues("/tickets/:id", http.MethodGet, "200")))
+	require.Equal(t, 1.0, testutil.ToFloat64(metric.Requests.WithLabelValues("/tickets", http.MethodPost, "500")))
+	require.Equal(t, 1.0, testutil.ToFloat64(metric.Requests.WithLabelValues("unmatched", http.MethodGet, "404")))
+	require.Equal(t, 1.0, testutil.ToFloat64(metric.Errors.WithLabelValues("/tickets", http.MethodPost, "500")))
+	require.Equal(t, 1, testutil.CollectAndCount(metric.Errors))
+	require.Equal(t, 3, testutil.CollectAndCount(metric.Duration))
+}
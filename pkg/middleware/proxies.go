@@ -0,0 +1,17 @@
+package middleware
+
+import (
+	"log/slog"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TrustProxies makes engine read the client IP from the forwarding headers
+// only for requests coming from proxies. No proxy is trusted when proxies is
+// invalid.
+func TrustProxies(engine *gin.Engine, proxies []string) {
+	if err := engine.SetTrustedProxies(proxies); err != nil {
+		slog.Error("invalid trusted proxies, forwarding headers are ignored", "error", err.Error())
+		_ = engine.SetTrustedProxies(nil)
+	}
+}
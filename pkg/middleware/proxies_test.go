@@ -0,0 +1,63 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTrustProxies(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name       string
+		proxies    []string
+		remoteAddr string
+		expectedIP string
+	}{
+		{
+			name:       "ignores the forwarding headers without trusted proxies",
+			remoteAddr: "10.0.0.1:1234",
+			expectedIP: "10.0.0.1",
+		},
+		{
+			name:       "reads the forwarding headers of trusted proxies",
+			proxies:    []string{"10.0.0.0/8"},
+			remoteAddr: "10.0.0.1:1234",
+			expectedIP: "203.0.113.7",
+		},
+		{
+			name:       "ignores the forwarding headers of other clients",
+			proxies:    []string{"10.0.0.0/8"},
+			remoteAddr: "192.0.2.1:1234",
+			expectedIP: "192.0.2.1",
+		},
+		{
+			name:       "trusts no proxy when they are invalid",
+			proxies:    []string{"proxy"},
+			remoteAddr: "10.0.0.1:1234",
+			expectedIP: "10.0.0.1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			engine := gin.New()
+			TrustProxies(engine, tt.proxies)
+			engine.GET("/", func(c *gin.Context) {
+				c.String(http.StatusOK, c.ClientIP())
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.RemoteAddr = tt.remoteAddr
+			req.Header.Set("X-Forwarded-For", "203.0.113.7")
+			w := httptest.NewRecorder()
+			engine.ServeHTTP(w, req)
+
+			require.Equal(t, tt.expectedIP, w.Body.String())
+		})
+	}
+}
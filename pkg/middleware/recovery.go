@@ -0,0 +1,47 @@
+package middleware
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"runtime/debug"
+
+	"github.com/gin-gonic/gin"
+	"github.com/iamnotrodger/golang-projects/pkg/apierror"
+)
+
+// Recovery answers the requests whose handler panicked with a 500 error and
+// logs the panic along with its stack. It must run after the middlewares
+// recording the status of the response.
+func Recovery() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		defer func() {
+			recovered := recover()
+			if recovered == nil {
+				return
+			}
+			// The client went away, there is no one to answer.
+			if err, ok := recovered.(error); ok && errors.Is(err, http.ErrAbortHandler) {
+				c.Abort()
+				return
+			}
+
+			slog.ErrorContext(
+				c.Request.Context(),
+				"recovered from panic",
+				"panic", recovered,
+				"method", c.Request.Method,
+				"path", c.Request.URL.Path,
+				"stack", string(debug.Stack()),
+			)
+
+			if c.Writer.Written() {
+				c.Abort()
+				return
+			}
+			writeError(c, apierror.Internal(fmt.Errorf("panic: %v", recovered)))
+		}()
+		c.Next()
+	}
+}
@@ -0,0 +1,42 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecovery(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	engine := gin.New()
+	engine.Use(Recovery())
+	engine.GET("/panic", func(c *gin.Context) {
+		panic("handler failed")
+	})
+	engine.GET("/abort", func(c *gin.Context) {
+		panic(http.ErrAbortHandler)
+	})
+
+	recorder := httptest.NewRecorder()
+	engine.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/panic", nil))
+
+	require.Equal(t, http.StatusInternalServerError, recorder.Code)
+	var body map[string]any
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &body))
+	require.Equal(t, map[string]any{
+		"error":       "internal_error",
+		"code":        float64(http.StatusInternalServerError),
+		"description": "internal server error",
+	}, body)
+
+	recorder = httptest.NewRecorder()
+	require.NotPanics(t, func() {
+		engine.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/abort", nil))
+	})
+	require.Empty(t, recorder.Body.String())
+}
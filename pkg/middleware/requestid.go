@@ -0,0 +1,35 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/iamnotrodger/golang-projects/pkg/eventmeta"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// maxRequestIDLength bounds the request ids accepted from clients.
+const maxRequestIDLength = 128
+
+// RequestID adds the X-Request-ID of the request to its context and response.
+// Requests without one use the id of their trace, so it must run after the
+// tracing middleware, and the id is also set on the server span.
+func RequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+		span := trace.SpanFromContext(ctx)
+
+		requestID := c.GetHeader(eventmeta.HTTPHeaderRequestID)
+		if requestID == "" || len(requestID) > maxRequestIDLength {
+			if spanContext := span.SpanContext(); spanContext.HasTraceID() {
+				requestID = spanContext.TraceID().String()
+			} else {
+				requestID = eventmeta.NewRequestID()
+			}
+		}
+
+		span.SetAttributes(attribute.String("request.id", requestID))
+		c.Header(eventmeta.HTTPHeaderRequestID, requestID)
+		c.Request = c.Request.WithContext(eventmeta.WithRequestID(ctx, requestID))
+		c.Next()
+	}
+}
@@ -0,0 +1,83 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/iamnotrodger/golang-projects/pkg/eventmeta"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestRequestID(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	traceID, err := trace.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+	require.NoError(t, err)
+	spanID, err := trace.SpanIDFromHex("00f067aa0ba902b7")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name              string
+		requestID         string
+		traced            bool
+		expectedRequestID string
+	}{
+		{
+			name:              "uses the request id of the client",
+			requestID:         "request-1",
+			traced:            true,
+			expectedRequestID: "request-1",
+		},
+		{
+			name:              "uses the trace id when missing",
+			traced:            true,
+			expectedRequestID: "4bf92f3577b34da6a3ce929d0e0e4736",
+		},
+		{
+			name:              "replaces request ids that are too long",
+			requestID:         strings.Repeat("a", maxRequestIDLength+1),
+			traced:            true,
+			expectedRequestID: "4bf92f3577b34da6a3ce929d0e0e4736",
+		},
+		{
+			name: "generates one without a trace",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var handlerRequestID string
+			engine := gin.New()
+			engine.Use(func(c *gin.Context) {
+				if tt.traced {
+					ctx := trace.ContextWithSpanContext(c.Request.Context(), trace.NewSpanContext(trace.SpanContextConfig{
+						TraceID: traceID,
+						SpanID:  spanID,
+					}))
+					c.Request = c.Request.WithContext(ctx)
+				}
+			}, RequestID())
+			engine.GET("/", func(c *gin.Context) {
+				handlerRequestID = eventmeta.RequestID(c.Request.Context())
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.requestID != "" {
+				req.Header.Set("X-Request-ID", tt.requestID)
+			}
+			w := httptest.NewRecorder()
+			engine.ServeHTTP(w, req)
+
+			require.Equal(t, handlerRequestID, w.Header().Get("X-Request-ID"))
+			if tt.expectedRequestID != "" {
+				require.Equal(t, tt.expectedRequestID, handlerRequestID)
+			} else {
+				require.Len(t, handlerRequestID, 32)
+			}
+		})
+	}
+}
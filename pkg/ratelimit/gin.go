@@ -0,0 +1,57 @@
+package ratelimit
+
+import (
+	"log/slog"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/iamnotrodger/golang-projects/pkg/apierror"
+	"github.com/iamnotrodger/golang-projects/pkg/auth"
+)
+
+// Middleware limits the requests of every client to route. Clients are
+// identified by their principal, an API key or a player, and otherwise by
+// their IP, so it must run after the authentication. limit is read on every
+// request, and requests are let through when the limiter fails.
+func Middleware(limiter Limiter, route string, limit func() Limit) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		current := limit()
+		if limiter == nil || !current.Enabled() {
+			c.Next()
+			return
+		}
+
+		ctx := c.Request.Context()
+		result, err := limiter.Allow(ctx, route+":"+clientKey(c), current)
+		if err != nil {
+			slog.WarnContext(ctx, "rate limiter failed, allowing request", "route", route, "error", err.Error())
+			c.Next()
+			return
+		}
+
+		c.Header("RateLimit-Limit", strconv.Itoa(result.Limit))
+		c.Header("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+		c.Header("RateLimit-Reset", strconv.Itoa(ceilSeconds(result.Reset)))
+		c.Header("RateLimit-Policy", strconv.Itoa(current.Requests)+";w="+strconv.Itoa(ceilSeconds(current.Window)))
+
+		if !result.Allowed {
+			c.Error(apierror.TooManyRequests(result.RetryAfter))
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+func clientKey(c *gin.Context) string {
+	if principal, ok := auth.FromContext(c.Request.Context()); ok {
+		return principal.Method + ":" + principal.Subject
+	}
+	return "ip:" + c.ClientIP()
+}
+
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
@@ -0,0 +1,121 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/iamnotrodger/golang-projects/pkg/apierror"
+	"github.com/iamnotrodger/golang-projects/pkg/auth"
+	"github.com/iamnotrodger/golang-projects/pkg/middleware"
+	"github.com/stretchr/testify/require"
+)
+
+type limiterFunc func(ctx context.Context, key string, limit Limit) (Result, error)
+
+func (f limiterFunc) Allow(ctx context.Context, key string, limit Limit) (Result, error) {
+	return f(ctx, key, limit)
+}
+
+func TestMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	limit := Limit{Requests: 10, Window: time.Minute}
+
+	tests := []struct {
+		name            string
+		limit           Limit
+		principal       *auth.Principal
+		result          Result
+		err             error
+		expectedKey     string
+		expectedStatus  int
+		expectedHeaders map[string]string
+	}{
+		{
+			name:           "allows requests under the limit",
+			limit:          limit,
+			result:         Result{Allowed: true, Limit: 10, Remaining: 9, Reset: 6 * time.Second},
+			expectedKey:    "save_score:ip:192.0.2.1",
+			expectedStatus: http.StatusOK,
+			expectedHeaders: map[string]string{
+				"RateLimit-Limit":     "10",
+				"RateLimit-Remaining": "9",
+				"RateLimit-Reset":     "6",
+				"RateLimit-Policy":    "10;w=60",
+			},
+		},
+		{
+			name:           "keys clients by principal",
+			limit:          limit,
+			principal:      &auth.Principal{Subject: "player-1", Method: auth.MethodJWT},
+			result:         Result{Allowed: true, Limit: 10, Remaining: 9, Reset: 6 * time.Second},
+			expectedKey:    "save_score:jwt:player-1",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "rejects requests over the limit",
+			limit:          limit,
+			result:         Result{Allowed: false, Limit: 10, Remaining: 0, Reset: time.Minute, RetryAfter: 1500 * time.Millisecond},
+			expectedKey:    "save_score:ip:192.0.2.1",
+			expectedStatus: http.StatusTooManyRequests,
+			expectedHeaders: map[string]string{
+				"RateLimit-Remaining": "0",
+				"Retry-After":         "2",
+			},
+		},
+		{
+			name:           "allows requests when the limiter fails",
+			limit:          limit,
+			err:            errors.New("connection refused"),
+			expectedKey:    "save_score:ip:192.0.2.1",
+			expectedStatus: http.StatusOK,
+			expectedHeaders: map[string]string{
+				"RateLimit-Limit": "",
+			},
+		},
+		{
+			name:           "skips disabled limits",
+			limit:          Limit{Window: time.Minute},
+			expectedStatus: http.StatusOK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var key string
+			limiter := limiterFunc(func(_ context.Context, k string, _ Limit) (Result, error) {
+				key = k
+				return tt.result, tt.err
+			})
+
+			router := gin.New()
+			router.Use(middleware.Errors())
+			router.Use(func(c *gin.Context) {
+				if tt.principal != nil {
+					c.Request = c.Request.WithContext(auth.NewContext(c.Request.Context(), *tt.principal))
+				}
+			})
+			router.POST("/score", Middleware(limiter, "save_score", func() Limit { return tt.limit }), func(c *gin.Context) {
+				c.Status(http.StatusOK)
+			})
+
+			req := httptest.NewRequest(http.MethodPost, "/score", nil)
+			req.RemoteAddr = "192.0.2.1:1234"
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			require.Equal(t, tt.expectedStatus, w.Code)
+			require.Equal(t, tt.expectedKey, key)
+			for header, value := range tt.expectedHeaders {
+				require.Equal(t, value, w.Header().Get(header), header)
+			}
+			if tt.expectedStatus == http.StatusTooManyRequests {
+				require.Contains(t, w.Body.String(), apierror.CodeRateLimited)
+			}
+		})
+	}
+}
@@ -0,0 +1,62 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemory_Allow(t *testing.T) {
+	limit := Limit{Requests: 2, Window: time.Minute}
+	now := time.Unix(1_700_000_000, 0)
+	memory := NewMemory()
+	memory.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	result, err := memory.Allow(ctx, "player", limit)
+	require.NoError(t, err)
+	require.Equal(t, Result{Allowed: true, Limit: 2, Remaining: 1, Reset: 30 * time.Second}, result)
+
+	result, err = memory.Allow(ctx, "player", limit)
+	require.NoError(t, err)
+	require.Equal(t, Result{Allowed: true, Limit: 2, Remaining: 0, Reset: time.Minute}, result)
+
+	result, err = memory.Allow(ctx, "player", limit)
+	require.NoError(t, err)
+	require.Equal(t, Result{Allowed: false, Limit: 2, Remaining: 0, Reset: time.Minute, RetryAfter: 30 * time.Second}, result)
+
+	result, err = memory.Allow(ctx, "other", limit)
+	require.NoError(t, err)
+	require.True(t, result.Allowed, "keys have their own bucket")
+
+	now = now.Add(30 * time.Second)
+	result, err = memory.Allow(ctx, "player", limit)
+	require.NoError(t, err)
+	require.True(t, result.Allowed, "a token is refilled every half minute")
+	require.False(t, must(memory.Allow(ctx, "player", limit)).Allowed)
+}
+
+func TestMemory_Sweep(t *testing.T) {
+	limit := Limit{Requests: 1, Window: time.Second}
+	now := time.Unix(1_700_000_000, 0)
+	memory := NewMemory()
+	memory.now = func() time.Time { return now }
+
+	_, err := memory.Allow(context.Background(), "idle", limit)
+	require.NoError(t, err)
+	now = now.Add(sweepInterval)
+	_, err = memory.Allow(context.Background(), "active", limit)
+	require.NoError(t, err)
+
+	require.NotContains(t, memory.buckets, "idle")
+	require.Contains(t, memory.buckets, "active")
+}
+
+func must(result Result, err error) Result {
+	if err != nil {
+		panic(err)
+	}
+	return result
+}
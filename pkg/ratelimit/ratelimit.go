@@ -0,0 +1,38 @@
+package ratelimit
+
+import (
+	"context"
+	"time"
+)
+
+const (
+	BackendMemory = "memory"
+	BackendRedis  = "redis"
+)
+
+// Limit allows Requests per Window to every client, zero Requests disables it.
+// Limits are read on every request so they can change at runtime.
+type Limit struct {
+	Requests int           `mapstructure:"requests" validate:"min=0" reload:"true"`
+	Window   time.Duration `mapstructure:"window" validate:"min=1ms" reload:"true"`
+}
+
+func (l Limit) Enabled() bool {
+	return l.Requests > 0
+}
+
+// Result is the state of the limit of a key after a request.
+type Result struct {
+	Allowed   bool
+	Limit     int
+	Remaining int
+	// Reset is the time until the limit is fully available again.
+	Reset time.Duration
+	// RetryAfter is the time until the next request is allowed, it is zero
+	// for allowed requests.
+	RetryAfter time.Duration
+}
+
+type Limiter interface {
+	Allow(ctx context.Context, key string, limit Limit) (Result, error)
+}
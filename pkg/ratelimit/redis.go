@@ -0,0 +1,77 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const keyPrefix = "ratelimit:"
+
+// slidingWindow records the request in the sorted set of the key when fewer
+// than the limit were made within the window. It returns whether the request
+// was allowed, the requests within the window and the time of the oldest one.
+var slidingWindow = redis.NewScript(`
+local now = tonumber(ARGV[1])
+local window = tonumber(ARGV[2])
+local limit = tonumber(ARGV[3])
+
+redis.call('ZREMRANGEBYSCORE', KEYS[1], '-inf', now - window)
+local count = redis.call('ZCARD', KEYS[1])
+local allowed = 0
+if count < limit then
+	redis.call('ZADD', KEYS[1], now, ARGV[4])
+	count = count + 1
+	allowed = 1
+end
+redis.call('PEXPIRE', KEYS[1], window)
+
+local oldest = redis.call('ZRANGE', KEYS[1], 0, 0, 'WITHSCORES')
+if oldest[2] then
+	return {allowed, count, tonumber(oldest[2])}
+end
+return {allowed, count, now}
+`)
+
+// Redis is a sliding window limiter shared by the replicas of a service, the
+// requests of every key are kept in a sorted set for the duration of the window.
+type Redis struct {
+	rdb      redis.Scripter
+	now      func() time.Time
+	sequence atomic.Uint64
+}
+
+func NewRedis(rdb redis.Scripter) *Redis {
+	return &Redis{rdb: rdb, now: time.Now}
+}
+
+func (r *Redis) Allow(ctx context.Context, key string, limit Limit) (Result, error) {
+	now := r.now().UnixMilli()
+	window := limit.Window.Milliseconds()
+	// The member only has to be unique, requests made in the same millisecond are kept apart by the sequence.
+	member := fmt.Sprintf("%d-%d", now, r.sequence.Add(1))
+
+	values, err := slidingWindow.Run(ctx, r.rdb, []string{keyPrefix + key}, now, window, limit.Requests, member).Int64Slice()
+	if err != nil {
+		return Result{}, err
+	}
+	if len(values) != 3 {
+		return Result{}, fmt.Errorf("unexpected rate limit script result %v", values)
+	}
+
+	allowed, count, oldest := values[0] == 1, int(values[1]), values[2]
+	reset := time.Duration(oldest+window-now) * time.Millisecond
+	result := Result{
+		Allowed:   allowed,
+		Limit:     limit.Requests,
+		Remaining: max(limit.Requests-count, 0),
+		Reset:     reset,
+	}
+	if !allowed {
+		result.RetryAfter = reset
+	}
+	return result, nil
+}
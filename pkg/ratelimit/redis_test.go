@@ -0,0 +1,67 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-redis/redismock/v9"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRedis_Allow(t *testing.T) {
+	limit := Limit{Requests: 10, Window: time.Minute}
+	now := time.UnixMilli(1_700_000_000_000)
+
+	tests := []struct {
+		name           string
+		setupMock      func(mock redismock.ClientMock)
+		expectedResult Result
+		expectedError  bool
+	}{
+		{
+			name: "allows requests under the limit",
+			setupMock: func(mock redismock.ClientMock) {
+				mock.ExpectEvalSha(slidingWindow.Hash(), []string{"ratelimit:player"}, now.UnixMilli(), int64(60_000), 10, "1700000000000-1").
+					SetVal([]any{int64(1), int64(4), now.UnixMilli() - 20_000})
+			},
+			expectedResult: Result{Allowed: true, Limit: 10, Remaining: 6, Reset: 40 * time.Second},
+		},
+		{
+			name: "rejects requests over the limit",
+			setupMock: func(mock redismock.ClientMock) {
+				mock.ExpectEvalSha(slidingWindow.Hash(), []string{"ratelimit:player"}, now.UnixMilli(), int64(60_000), 10, "1700000000000-1").
+					SetVal([]any{int64(0), int64(10), now.UnixMilli() - 45_000})
+			},
+			expectedResult: Result{Allowed: false, Limit: 10, Remaining: 0, Reset: 15 * time.Second, RetryAfter: 15 * time.Second},
+		},
+		{
+			name: "returns redis errors",
+			setupMock: func(mock redismock.ClientMock) {
+				mock.ExpectEvalSha(slidingWindow.Hash(), []string{"ratelimit:player"}, now.UnixMilli(), int64(60_000), 10, "1700000000000-1").
+					SetErr(errors.New("connection refused"))
+			},
+			expectedError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rdb, mock := redismock.NewClientMock()
+			tt.setupMock(mock)
+			limiter := NewRedis(rdb)
+			limiter.now = func() time.Time { return now }
+
+			result, err := limiter.Allow(context.Background(), "player", limit)
+
+			if tt.expectedError {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, tt.expectedResult, result)
+			}
+			require.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
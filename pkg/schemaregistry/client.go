@@ -0,0 +1,158 @@
+package schemaregistry
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	SchemaTypeProtobuf = "PROTOBUF"
+	ContentType        = "application/vnd.schemaregistry.v1+json"
+	DefaultTimeout     = 5 * time.Second
+)
+
+const (
+	ErrorCodeSubjectNotFound = 40401
+	ErrorCodeSchemaNotFound  = 40403
+	ErrorCodeIncompatible    = 409
+	ErrorCodeInvalidSchema   = 42201
+)
+
+// Config selects the schema registry. Embedded runs an in-memory registry for
+// development, which the producer serves under /schema-registry behind basic
+// auth with Username and Password. Its ids restart with the producer.
+type Config struct {
+	URL      string        `mapstructure:"url" validate:"required_without=Embedded,omitempty,url"`
+	Username string        `mapstructure:"username" validate:"required_if=Embedded true"`
+	Password string        `mapstructure:"password" json:"-" validate:"required_if=Embedded true"`
+	Timeout  time.Duration `mapstructure:"timeout"`
+	Embedded bool          `mapstructure:"embedded"`
+}
+
+// NewRegistry returns the in-memory registry when Embedded is set and a client
+// of the registry at URL otherwise.
+func (c Config) NewRegistry() Registry {
+	if c.Embedded {
+		return NewMemory()
+	}
+	return NewClient(c)
+}
+
+type Schema struct {
+	Schema     string `json:"schema"`
+	SchemaType string `json:"schemaType,omitempty"`
+}
+
+type Registry interface {
+	// Register registers schema under subject, or returns the id it is already registered with.
+	Register(ctx context.Context, subject string, schema Schema) (int, error)
+	SchemaByID(ctx context.Context, id int) (Schema, error)
+	// CheckCompatibility checks schema against the latest version of subject.
+	CheckCompatibility(ctx context.Context, subject string, schema Schema) (bool, error)
+}
+
+// Error is returned for the error responses of the registry.
+type Error struct {
+	StatusCode int    `json:"-"`
+	Code       int    `json:"error_code"`
+	Message    string `json:"message"`
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("schema registry error %d: %s", e.Code, e.Message)
+}
+
+// Client is a client of the Confluent schema registry REST API.
+type Client struct {
+	baseURL    string
+	username   string
+	password   string
+	httpClient *http.Client
+}
+
+func NewClient(config Config) *Client {
+	timeout := config.Timeout
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+
+	return &Client{
+		baseURL:    strings.TrimSuffix(config.URL, "/"),
+		username:   config.Username,
+		password:   config.Password,
+		httpClient: &http.Client{Timeout: timeout},
+	}
+}
+
+func (c *Client) Register(ctx context.Context, subject string, schema Schema) (int, error) {
+	var response struct {
+		ID int `json:"id"`
+	}
+	err := c.do(ctx, http.MethodPost, "/subjects/"+url.PathEscape(subject)+"/versions", schema, &response)
+	return response.ID, err
+}
+
+func (c *Client) SchemaByID(ctx context.Context, id int) (Schema, error) {
+	var schema Schema
+	err := c.do(ctx, http.MethodGet, "/schemas/ids/"+strconv.Itoa(id), nil, &schema)
+	return schema, err
+}
+
+func (c *Client) CheckCompatibility(ctx context.Context, subject string, schema Schema) (bool, error) {
+	var response struct {
+		IsCompatible bool `json:"is_compatible"`
+	}
+	err := c.do(ctx, http.MethodPost, "/compatibility/subjects/"+url.PathEscape(subject)+"/versions/latest", schema, &response)
+	if registryErr, ok := err.(*Error); ok && registryErr.Code == ErrorCodeSubjectNotFound {
+		return true, nil
+	}
+	return response.IsCompatible, err
+}
+
+func (c *Client) do(ctx context.Context, method string, path string, body any, response any) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", ContentType)
+	if body != nil {
+		req.Header.Set("Content-Type", ContentType)
+	}
+	if c.username != "" {
+		req.SetBasicAuth(c.username, c.password)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		registryErr := &Error{StatusCode: resp.StatusCode}
+		if err := json.NewDecoder(resp.Body).Decode(registryErr); err != nil || registryErr.Message == "" {
+			registryErr.Code = resp.StatusCode
+			registryErr.Message = http.StatusText(resp.StatusCode)
+		}
+		return registryErr
+	}
+
+	return json.NewDecoder(resp.Body).Decode(response)
+}
@@ -0,0 +1,108 @@
+package schemaregistry
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	pkgconfig "github.com/iamnotrodger/golang-projects/pkg/config"
+	"github.com/iamnotrodger/golang-projects/pkg/proto/topics"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient(t *testing.T) {
+	memory := NewMemory()
+	var username, password string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		username, password, _ = r.BasicAuth()
+		require.Equal(t, ContentType, r.Header.Get("Accept"))
+		memory.ServeHTTP(w, r)
+	}))
+	defer server.Close()
+
+	client := NewClient(Config{URL: server.URL + "/", Username: "user", Password: "secret"})
+	ctx := context.Background()
+
+	schema, err := ProtobufSchema((&topics.Ticket{}).ProtoReflect().Descriptor())
+	require.NoError(t, err)
+
+	isCompatible, err := client.CheckCompatibility(ctx, "tickets-value", schema)
+	require.NoError(t, err)
+	require.True(t, isCompatible)
+
+	id, err := client.Register(ctx, "tickets-value", schema)
+	require.NoError(t, err)
+	require.Equal(t, 1, id)
+	require.Equal(t, "user", username)
+	require.Equal(t, "secret", password)
+
+	id, err = client.Register(ctx, "tickets-value", schema)
+	require.NoError(t, err)
+	require.Equal(t, 1, id)
+
+	fetched, err := client.SchemaByID(ctx, id)
+	require.NoError(t, err)
+	require.Equal(t, schema, fetched)
+
+	_, err = client.SchemaByID(ctx, 42)
+	require.EqualError(t, err, "schema registry error 40403: Schema 42 not found")
+	require.Equal(t, http.StatusNotFound, err.(*Error).StatusCode)
+}
+
+func TestClient_UnexpectedError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	_, err := NewClient(Config{URL: server.URL}).SchemaByID(context.Background(), 1)
+
+	require.EqualError(t, err, "schema registry error 502: Bad Gateway")
+}
+
+func TestConfig_NewRegistry(t *testing.T) {
+	require.IsType(t, &Memory{}, Config{Embedded: true}.NewRegistry())
+	require.IsType(t, &Client{}, Config{URL: "http://registry:8081"}.NewRegistry())
+}
+
+type testSpec struct {
+	SchemaRegistry Config `mapstructure:"schema_registry"`
+}
+
+func TestConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name          string
+		config        Config
+		expectedError string
+	}{
+		{name: "embedded", config: Config{Embedded: true, Username: "admin", Password: "secret"}},
+		{name: "url", config: Config{URL: "http://registry:8081"}},
+		{
+			name:          "missing url",
+			config:        Config{},
+			expectedError: "invalid configuration:\n  schema_registry.url: is required when embedded is not set",
+		},
+		{
+			name:          "embedded without credentials",
+			config:        Config{Embedded: true},
+			expectedError: "invalid configuration:\n  schema_registry.username: is required when embedded is true\n  schema_registry.password: is required when embedded is true",
+		},
+		{
+			name:          "invalid url",
+			config:        Config{URL: "registry"},
+			expectedError: "invalid configuration:\n  schema_registry.url: must be a valid URL",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := pkgconfig.Validate(&testSpec{SchemaRegistry: tt.config})
+			if tt.expectedError == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.EqualError(t, err, tt.expectedError)
+		})
+	}
+}
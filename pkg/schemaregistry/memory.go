@@ -0,0 +1,156 @@
+package schemaregistry
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"sync"
+)
+
+// Memory is an in-memory registry for development and tests. It serves the
+// subset of the REST API used by Client, so it can stand in for a registry.
+type Memory struct {
+	mu sync.RWMutex
+	// schemas holds the schema with id i+1 at index i.
+	schemas  []Schema
+	subjects map[string][]int
+	mux      *http.ServeMux
+}
+
+func NewMemory() *Memory {
+	m := &Memory{subjects: map[string][]int{}}
+
+	m.mux = http.NewServeMux()
+	m.mux.HandleFunc("POST /subjects/{subject}/versions", m.handleRegister)
+	m.mux.HandleFunc("GET /schemas/ids/{id}", m.handleSchemaByID)
+	m.mux.HandleFunc("POST /compatibility/subjects/{subject}/versions/latest", m.handleCheckCompatibility)
+	return m
+}
+
+func (m *Memory) Register(ctx context.Context, subject string, schema Schema) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	versions := m.subjects[subject]
+	for _, id := range versions {
+		if m.schemas[id-1] == schema {
+			return id, nil
+		}
+	}
+	if len(versions) > 0 {
+		if err := compatible(m.schemas[versions[len(versions)-1]-1], schema); err != nil {
+			return 0, err
+		}
+	}
+
+	id := 0
+	for i, registered := range m.schemas {
+		if registered == schema {
+			id = i + 1
+			break
+		}
+	}
+	if id == 0 {
+		m.schemas = append(m.schemas, schema)
+		id = len(m.schemas)
+	}
+
+	m.subjects[subject] = append(versions, id)
+	return id, nil
+}
+
+func (m *Memory) SchemaByID(ctx context.Context, id int) (Schema, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if id < 1 || id > len(m.schemas) {
+		return Schema{}, &Error{StatusCode: http.StatusNotFound, Code: ErrorCodeSchemaNotFound, Message: "Schema " + strconv.Itoa(id) + " not found"}
+	}
+	return m.schemas[id-1], nil
+}
+
+func (m *Memory) CheckCompatibility(ctx context.Context, subject string, schema Schema) (bool, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	versions := m.subjects[subject]
+	if len(versions) == 0 {
+		return true, nil
+	}
+
+	err := compatible(m.schemas[versions[len(versions)-1]-1], schema)
+	if registryErr, ok := err.(*Error); ok && registryErr.Code == ErrorCodeIncompatible {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+func (m *Memory) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	m.mux.ServeHTTP(w, r)
+}
+
+func (m *Memory) handleRegister(w http.ResponseWriter, r *http.Request) {
+	var schema Schema
+	if !decodeSchema(w, r, &schema) {
+		return
+	}
+
+	id, err := m.Register(r.Context(), r.PathValue("subject"), schema)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]int{"id": id})
+}
+
+func (m *Memory) handleSchemaByID(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		writeError(w, &Error{StatusCode: http.StatusNotFound, Code: ErrorCodeSchemaNotFound, Message: "Schema " + r.PathValue("id") + " not found"})
+		return
+	}
+
+	schema, err := m.SchemaByID(r.Context(), id)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, schema)
+}
+
+func (m *Memory) handleCheckCompatibility(w http.ResponseWriter, r *http.Request) {
+	var schema Schema
+	if !decodeSchema(w, r, &schema) {
+		return
+	}
+
+	isCompatible, err := m.CheckCompatibility(r.Context(), r.PathValue("subject"), schema)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]bool{"is_compatible": isCompatible})
+}
+
+func decodeSchema(w http.ResponseWriter, r *http.Request, schema *Schema) bool {
+	if err := json.NewDecoder(r.Body).Decode(schema); err != nil {
+		writeError(w, &Error{StatusCode: http.StatusUnprocessableEntity, Code: ErrorCodeInvalidSchema, Message: err.Error()})
+		return false
+	}
+	return true
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	registryErr, ok := err.(*Error)
+	if !ok {
+		registryErr = &Error{StatusCode: http.StatusInternalServerError, Code: http.StatusInternalServerError, Message: err.Error()}
+	}
+	writeJSON(w, registryErr.StatusCode, registryErr)
+}
+
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", ContentType)
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
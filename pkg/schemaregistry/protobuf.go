@@ -0,0 +1,292 @@
+package schemaregistry
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+var ErrNotRegistered = errors.New("schema is not registered")
+
+// TopicSubject returns the subject of the values of topic, following the TopicNameStrategy.
+func TopicSubject(topic string) string {
+	return topic + "-value"
+}
+
+// ProtobufSchema returns the schema of the file declaring message. The schema
+// is the base64 encoded FileDescriptorProto, which registries accept in place
+// of the .proto source. Imports must be well-known types.
+func ProtobufSchema(message protoreflect.MessageDescriptor) (Schema, error) {
+	file := protodesc.ToFileDescriptorProto(message.ParentFile())
+	data, err := proto.MarshalOptions{Deterministic: true}.Marshal(file)
+	if err != nil {
+		return Schema{}, err
+	}
+	return Schema{Schema: base64.StdEncoding.EncodeToString(data), SchemaType: SchemaTypeProtobuf}, nil
+}
+
+func parseProtobufSchema(schema Schema) (protoreflect.FileDescriptor, error) {
+	if schema.SchemaType != SchemaTypeProtobuf {
+		return nil, fmt.Errorf("unsupported schema type %q", schema.SchemaType)
+	}
+
+	data, err := base64.StdEncoding.DecodeString(schema.Schema)
+	if err != nil {
+		return nil, fmt.Errorf("decode protobuf schema: %w", err)
+	}
+
+	var file descriptorpb.FileDescriptorProto
+	if err := proto.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("decode protobuf schema: %w", err)
+	}
+	return protodesc.NewFile(&file, protoregistry.GlobalFiles)
+}
+
+// messageIndexes returns the path of message within its file.
+func messageIndexes(message protoreflect.MessageDescriptor) []int {
+	var indexes []int
+	var descriptor protoreflect.Descriptor = message
+	for {
+		if _, ok := descriptor.(protoreflect.MessageDescriptor); !ok {
+			break
+		}
+		indexes = append([]int{descriptor.Index()}, indexes...)
+		descriptor = descriptor.Parent()
+	}
+	return indexes
+}
+
+func messageAt(file protoreflect.FileDescriptor, indexes []int) (protoreflect.MessageDescriptor, error) {
+	messages := file.Messages()
+	var message protoreflect.MessageDescriptor
+	for _, index := range indexes {
+		if index >= messages.Len() {
+			return nil, fmt.Errorf("message indexes %v not found in %s", indexes, file.Path())
+		}
+		message = messages.Get(index)
+		messages = message.Messages()
+	}
+	if message == nil {
+		return nil, fmt.Errorf("message indexes %v not found in %s", indexes, file.Path())
+	}
+	return message, nil
+}
+
+// compatible checks that data written with previous can be read with next: the
+// messages of previous must still exist and keep the type and cardinality of
+// their fields. Fields may be added, removed or renamed.
+func compatible(previous Schema, next Schema) error {
+	previousFile, err := parseProtobufSchema(previous)
+	if err != nil {
+		return &Error{StatusCode: http.StatusUnprocessableEntity, Code: ErrorCodeInvalidSchema, Message: err.Error()}
+	}
+	nextFile, err := parseProtobufSchema(next)
+	if err != nil {
+		return &Error{StatusCode: http.StatusUnprocessableEntity, Code: ErrorCodeInvalidSchema, Message: err.Error()}
+	}
+
+	var problems []string
+	var walk func(previousMessages protoreflect.MessageDescriptors)
+	walk = func(previousMessages protoreflect.MessageDescriptors) {
+		for i := range previousMessages.Len() {
+			previousMessage := previousMessages.Get(i)
+			nextMessage := findMessage(nextFile, previousMessage.FullName())
+			if nextMessage == nil {
+				problems = append(problems, fmt.Sprintf("message %s was removed", previousMessage.FullName()))
+				continue
+			}
+
+			fields := previousMessage.Fields()
+			for j := range fields.Len() {
+				previousField := fields.Get(j)
+				nextField := nextMessage.Fields().ByNumber(previousField.Number())
+				if nextField == nil {
+					continue
+				}
+				if previousField.Kind() != nextField.Kind() || previousField.Cardinality() != nextField.Cardinality() {
+					problems = append(problems, fmt.Sprintf("field %d of %s changed type", previousField.Number(), previousMessage.FullName()))
+					continue
+				}
+				if previousField.Message() != nil && previousField.Message().FullName() != nextField.Message().FullName() {
+					problems = append(problems, fmt.Sprintf("field %d of %s changed type", previousField.Number(), previousMessage.FullName()))
+				}
+			}
+
+			walk(previousMessage.Messages())
+		}
+	}
+	walk(previousFile.Messages())
+
+	if len(problems) > 0 {
+		return &Error{StatusCode: http.StatusConflict, Code: ErrorCodeIncompatible, Message: fmt.Sprintf("schema is incompatible with the latest version: %v", problems)}
+	}
+	return nil
+}
+
+func findMessage(file protoreflect.FileDescriptor, name protoreflect.FullName) protoreflect.MessageDescriptor {
+	var find func(messages protoreflect.MessageDescriptors) protoreflect.MessageDescriptor
+	find = func(messages protoreflect.MessageDescriptors) protoreflect.MessageDescriptor {
+		for i := range messages.Len() {
+			message := messages.Get(i)
+			if message.FullName() == name {
+				return message
+			}
+			if nested := find(message.Messages()); nested != nil {
+				return nested
+			}
+		}
+		return nil
+	}
+	return find(file.Messages())
+}
+
+// Serializer frames messages of a single type with the id of their schema.
+type Serializer struct {
+	registry Registry
+	subject  string
+	message  protoreflect.MessageDescriptor
+
+	mu     sync.RWMutex
+	header *Header
+}
+
+func NewSerializer(registry Registry, subject string, message proto.Message) *Serializer {
+	return &Serializer{
+		registry: registry,
+		subject:  subject,
+		message:  message.ProtoReflect().Descriptor(),
+	}
+}
+
+// Register checks the schema against the latest version of the subject and
+// registers it, it must be called before Serialize.
+func (s *Serializer) Register(ctx context.Context) error {
+	schema, err := ProtobufSchema(s.message)
+	if err != nil {
+		return err
+	}
+
+	isCompatible, err := s.registry.CheckCompatibility(ctx, s.subject, schema)
+	if err != nil {
+		return fmt.Errorf("check compatibility of %s: %w", s.subject, err)
+	}
+	if !isCompatible {
+		return fmt.Errorf("schema of %s is incompatible with the latest version of %s", s.message.FullName(), s.subject)
+	}
+
+	id, err := s.registry.Register(ctx, s.subject, schema)
+	if err != nil {
+		return fmt.Errorf("register schema of %s: %w", s.subject, err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.header = &Header{SchemaID: id, Indexes: messageIndexes(s.message)}
+	return nil
+}
+
+// SchemaID returns the id the schema was registered with, or zero before Register.
+func (s *Serializer) SchemaID() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.header == nil {
+		return 0
+	}
+	return s.header.SchemaID
+}
+
+func (s *Serializer) Serialize(message proto.Message) ([]byte, error) {
+	if message.ProtoReflect().Descriptor().FullName() != s.message.FullName() {
+		return nil, fmt.Errorf("serializer of %s cannot serialize %s", s.message.FullName(), message.ProtoReflect().Descriptor().FullName())
+	}
+
+	s.mu.RLock()
+	header := s.header
+	s.mu.RUnlock()
+	if header == nil {
+		return nil, ErrNotRegistered
+	}
+
+	payload, err := proto.Marshal(message)
+	if err != nil {
+		return nil, err
+	}
+	return Encode(*header, payload), nil
+}
+
+// Deserializer decodes framed messages with the writer's schema, fetched from
+// the registry by id and cached.
+type Deserializer struct {
+	registry Registry
+
+	mu    sync.RWMutex
+	files map[int]protoreflect.FileDescriptor
+}
+
+func NewDeserializer(registry Registry) *Deserializer {
+	return &Deserializer{
+		registry: registry,
+		files:    map[int]protoreflect.FileDescriptor{},
+	}
+}
+
+// Deserialize decodes data into target. The writer's schema identifies the
+// message, its fields are matched to target by number like protobuf does so
+// renamed fields are kept and the fields unknown to target are dropped.
+// Unframed messages are decoded as plain protobuf and returned with a zero
+// header.
+func (d *Deserializer) Deserialize(ctx context.Context, data []byte, target proto.Message) (Header, error) {
+	header, payload, err := Decode(data)
+	if errors.Is(err, ErrUnframed) {
+		return Header{}, proto.Unmarshal(data, target)
+	}
+	if err != nil {
+		return Header{}, err
+	}
+
+	file, err := d.file(ctx, header.SchemaID)
+	if err != nil {
+		return header, err
+	}
+	writer, err := messageAt(file, header.Indexes)
+	if err != nil {
+		return header, err
+	}
+	if name := target.ProtoReflect().Descriptor().FullName(); writer.FullName() != name {
+		return header, fmt.Errorf("schema %d was written for %s, not %s", header.SchemaID, writer.FullName(), name)
+	}
+
+	return header, proto.UnmarshalOptions{DiscardUnknown: true}.Unmarshal(payload, target)
+}
+
+func (d *Deserializer) file(ctx context.Context, id int) (protoreflect.FileDescriptor, error) {
+	d.mu.RLock()
+	file, ok := d.files[id]
+	d.mu.RUnlock()
+	if ok {
+		return file, nil
+	}
+
+	schema, err := d.registry.SchemaByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("fetch schema %d: %w", id, err)
+	}
+	file, err = parseProtobufSchema(schema)
+	if err != nil {
+		return nil, fmt.Errorf("parse schema %d: %w", id, err)
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.files[id] = file
+	return file, nil
+}
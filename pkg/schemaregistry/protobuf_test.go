@@ -0,0 +1,250 @@
+package schemaregistry
+
+import (
+	"context"
+	"encoding/base64"
+	"testing"
+	"time"
+
+	"github.com/iamnotrodger/golang-projects/pkg/proto/topics"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// ticketSchema returns the schema of topics.Ticket after applying change to its descriptor.
+func ticketSchema(t *testing.T, change func(message *descriptorpb.DescriptorProto)) Schema {
+	file := protodesc.ToFileDescriptorProto((&topics.Ticket{}).ProtoReflect().Descriptor().ParentFile())
+	change(file.MessageType[0])
+
+	data, err := proto.Marshal(file)
+	require.NoError(t, err)
+	return Schema{Schema: base64.StdEncoding.EncodeToString(data), SchemaType: SchemaTypeProtobuf}
+}
+
+func newTicket() *topics.Ticket {
+	return &topics.Ticket{
+		Id:        "ticket-1",
+		Title:     "Concert",
+		Price:     50,
+		CreatedAt: timestamppb.New(time.Date(2025, 10, 25, 12, 0, 0, 0, time.UTC)),
+	}
+}
+
+func TestSerializer(t *testing.T) {
+	ctx := context.Background()
+	registry := NewMemory()
+	serializer := NewSerializer(registry, TopicSubject("tickets"), &topics.Ticket{})
+
+	_, err := serializer.Serialize(newTicket())
+	require.ErrorIs(t, err, ErrNotRegistered)
+
+	require.Zero(t, serializer.SchemaID())
+
+	require.NoError(t, serializer.Register(ctx))
+	require.Equal(t, 1, serializer.SchemaID())
+
+	data, err := serializer.Serialize(newTicket())
+	require.NoError(t, err)
+
+	header, payload, err := Decode(data)
+	require.NoError(t, err)
+	require.Equal(t, Header{SchemaID: 1, Indexes: []int{0}}, header)
+
+	var ticket topics.Ticket
+	require.NoError(t, proto.Unmarshal(payload, &ticket))
+	require.True(t, proto.Equal(newTicket(), &ticket))
+
+	_, err = serializer.Serialize(&timestamppb.Timestamp{})
+	require.EqualError(t, err, "serializer of topics.ticket.Ticket cannot serialize google.protobuf.Timestamp")
+}
+
+func TestSerializer_RegisterIncompatible(t *testing.T) {
+	ctx := context.Background()
+	registry := NewMemory()
+
+	incompatible := ticketSchema(t, func(message *descriptorpb.DescriptorProto) {
+		message.Field[2].Type = descriptorpb.FieldDescriptorProto_TYPE_STRING.Enum()
+	})
+	_, err := registry.Register(ctx, "tickets-value", incompatible)
+	require.NoError(t, err)
+
+	err = NewSerializer(registry, "tickets-value", &topics.Ticket{}).Register(ctx)
+
+	require.EqualError(t, err, "schema of topics.ticket.Ticket is incompatible with the latest version of tickets-value")
+}
+
+func TestMemory_Register(t *testing.T) {
+	ctx := context.Background()
+	current, err := ProtobufSchema((&topics.Ticket{}).ProtoReflect().Descriptor())
+	require.NoError(t, err)
+
+	tests := []struct {
+		name          string
+		next          Schema
+		expectedID    int
+		expectedError string
+	}{
+		{
+			name:       "same schema",
+			next:       current,
+			expectedID: 1,
+		},
+		{
+			name: "added field",
+			next: ticketSchema(t, func(message *descriptorpb.DescriptorProto) {
+				message.Field = append(message.Field, &descriptorpb.FieldDescriptorProto{
+					Name:     proto.String("venue"),
+					JsonName: proto.String("venue"),
+					Number:   proto.Int32(5),
+					Label:    descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+					Type:     descriptorpb.FieldDescriptorProto_TYPE_STRING.Enum(),
+				})
+			}),
+			expectedID: 2,
+		},
+		{
+			name: "renamed field",
+			next: ticketSchema(t, func(message *descriptorpb.DescriptorProto) {
+				message.Field[1].Name = proto.String("name")
+				message.Field[1].JsonName = proto.String("name")
+			}),
+			expectedID: 2,
+		},
+		{
+			name: "changed field type",
+			next: ticketSchema(t, func(message *descriptorpb.DescriptorProto) {
+				message.Field[2].Type = descriptorpb.FieldDescriptorProto_TYPE_DOUBLE.Enum()
+			}),
+			expectedError: "schema registry error 409: schema is incompatible with the latest version: [field 3 of topics.ticket.Ticket changed type]",
+		},
+		{
+			name: "repeated field",
+			next: ticketSchema(t, func(message *descriptorpb.DescriptorProto) {
+				message.Field[0].Label = descriptorpb.FieldDescriptorProto_LABEL_REPEATED.Enum()
+			}),
+			expectedError: "schema registry error 409: schema is incompatible with the latest version: [field 1 of topics.ticket.Ticket changed type]",
+		},
+		{
+			name:          "invalid schema",
+			next:          Schema{Schema: "syntax = \"proto3\";", SchemaType: SchemaTypeProtobuf},
+			expectedError: "schema registry error 42201: decode protobuf schema: illegal base64 data at input byte 6",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			registry := NewMemory()
+			_, err := registry.Register(ctx, "tickets-value", current)
+			require.NoError(t, err)
+
+			isCompatible, compatibilityErr := registry.CheckCompatibility(ctx, "tickets-value", tt.next)
+			id, err := registry.Register(ctx, "tickets-value", tt.next)
+			if tt.expectedError != "" {
+				require.EqualError(t, err, tt.expectedError)
+				require.False(t, isCompatible)
+				return
+			}
+			require.NoError(t, err)
+			require.NoError(t, compatibilityErr)
+			require.True(t, isCompatible)
+			require.Equal(t, tt.expectedID, id)
+		})
+	}
+}
+
+func TestDeserializer(t *testing.T) {
+	ctx := context.Background()
+	registry := NewMemory()
+
+	serializer := NewSerializer(registry, "tickets-value", &topics.Ticket{})
+	require.NoError(t, serializer.Register(ctx))
+	current, err := serializer.Serialize(newTicket())
+	require.NoError(t, err)
+
+	legacy, err := proto.Marshal(newTicket())
+	require.NoError(t, err)
+
+	// The writer's version has a new venue field.
+	writerSchema := ticketSchema(t, func(message *descriptorpb.DescriptorProto) {
+		message.Field = append(message.Field, &descriptorpb.FieldDescriptorProto{
+			Name:     proto.String("venue"),
+			JsonName: proto.String("venue"),
+			Number:   proto.Int32(5),
+			Label:    descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+			Type:     descriptorpb.FieldDescriptorProto_TYPE_STRING.Enum(),
+		})
+	})
+	writerID, err := registry.Register(ctx, "tickets-value", writerSchema)
+	require.NoError(t, err)
+	writerFile, err := parseProtobufSchema(writerSchema)
+	require.NoError(t, err)
+
+	message := dynamicpb.NewMessage(writerFile.Messages().Get(0))
+	fields := message.Descriptor().Fields()
+	message.Set(fields.ByName("id"), protoreflect.ValueOfString("ticket-1"))
+	message.Set(fields.ByName("title"), protoreflect.ValueOfString("Concert"))
+	message.Set(fields.ByName("price"), protoreflect.ValueOfFloat32(50))
+	message.Set(fields.ByName("created_at"), protoreflect.ValueOfMessage(newTicket().CreatedAt.ProtoReflect()))
+	message.Set(fields.ByName("venue"), protoreflect.ValueOfString("Arena"))
+	payload, err := proto.Marshal(message)
+	require.NoError(t, err)
+	evolved := Encode(Header{SchemaID: writerID, Indexes: []int{0}}, payload)
+
+	// The writer's version renamed the title field, it is still decoded by number.
+	renamedSchema := ticketSchema(t, func(message *descriptorpb.DescriptorProto) {
+		message.Field[1].Name = proto.String("name")
+		message.Field[1].JsonName = proto.String("name")
+	})
+	renamedID, err := registry.Register(ctx, "tickets-value", renamedSchema)
+	require.NoError(t, err)
+	renamed := Encode(Header{SchemaID: renamedID, Indexes: []int{0}}, legacy)
+
+	tests := []struct {
+		name           string
+		data           []byte
+		expectedHeader Header
+		expectedError  string
+	}{
+		{name: "current schema", data: current, expectedHeader: Header{SchemaID: 1, Indexes: []int{0}}},
+		{name: "writer schema", data: evolved, expectedHeader: Header{SchemaID: writerID, Indexes: []int{0}}},
+		{name: "renamed field", data: renamed, expectedHeader: Header{SchemaID: renamedID, Indexes: []int{0}}},
+		{name: "unframed", data: legacy},
+		{
+			name:           "unknown schema",
+			data:           Encode(Header{SchemaID: 99}, payload),
+			expectedHeader: Header{SchemaID: 99, Indexes: []int{0}},
+			expectedError:  "fetch schema 99: schema registry error 40403: Schema 99 not found",
+		},
+		{
+			name:           "unknown message",
+			data:           Encode(Header{SchemaID: 1, Indexes: []int{3}}, payload),
+			expectedHeader: Header{SchemaID: 1, Indexes: []int{3}},
+			expectedError:  "message indexes [3] not found in proto/topics/ticket.proto",
+		},
+	}
+
+	deserializer := NewDeserializer(registry)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var ticket topics.Ticket
+			header, err := deserializer.Deserialize(ctx, tt.data, &ticket)
+			require.Equal(t, tt.expectedHeader, header)
+			if tt.expectedError != "" {
+				require.EqualError(t, err, tt.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			require.True(t, proto.Equal(newTicket(), &ticket))
+		})
+	}
+	t.Run("other message", func(t *testing.T) {
+		var timestamp timestamppb.Timestamp
+		_, err := deserializer.Deserialize(ctx, current, &timestamp)
+		require.EqualError(t, err, "schema 1 was written for topics.ticket.Ticket, not google.protobuf.Timestamp")
+	})
+}
@@ -0,0 +1,77 @@
+package schemaregistry
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+// MagicByte starts every message framed with the Confluent wire format.
+const MagicByte byte = 0
+
+var ErrUnframed = errors.New("message is not framed with a schema id")
+
+// Header identifies the writer's schema of a framed message. Indexes is the
+// path of the message type in the schema, [0] being its first top-level message.
+type Header struct {
+	SchemaID int
+	Indexes  []int
+}
+
+// Encode frames payload as the magic byte, the big endian schema id and the
+// zig-zag varint encoded message indexes.
+func Encode(header Header, payload []byte) []byte {
+	data := make([]byte, 5, 5+binary.MaxVarintLen64*(len(header.Indexes)+1)+len(payload))
+	data[0] = MagicByte
+	binary.BigEndian.PutUint32(data[1:5], uint32(header.SchemaID))
+
+	if len(header.Indexes) == 0 || (len(header.Indexes) == 1 && header.Indexes[0] == 0) {
+		// The indexes of the first message are shortened to a single zero.
+		data = binary.AppendVarint(data, 0)
+	} else {
+		data = binary.AppendVarint(data, int64(len(header.Indexes)))
+		for _, index := range header.Indexes {
+			data = binary.AppendVarint(data, int64(index))
+		}
+	}
+
+	return append(data, payload...)
+}
+
+// Decode splits a framed message into its header and payload, ErrUnframed is
+// returned for messages that do not start with the magic byte.
+func Decode(data []byte) (Header, []byte, error) {
+	if len(data) == 0 || data[0] != MagicByte {
+		return Header{}, nil, ErrUnframed
+	}
+	if len(data) < 5 {
+		return Header{}, nil, fmt.Errorf("framed message too short: %d bytes", len(data))
+	}
+
+	header := Header{SchemaID: int(binary.BigEndian.Uint32(data[1:5]))}
+	data = data[5:]
+
+	// Every index takes at least a byte, larger counts are corrupt.
+	count, n := binary.Varint(data)
+	if n <= 0 || count < 0 || count > int64(len(data)-n) {
+		return Header{}, nil, errors.New("invalid message indexes")
+	}
+	data = data[n:]
+
+	if count == 0 {
+		header.Indexes = []int{0}
+		return header, data, nil
+	}
+
+	header.Indexes = make([]int, count)
+	for i := range header.Indexes {
+		index, n := binary.Varint(data)
+		if n <= 0 || index < 0 {
+			return Header{}, nil, errors.New("invalid message indexes")
+		}
+		header.Indexes[i] = int(index)
+		data = data[n:]
+	}
+
+	return header, data, nil
+}
@@ -0,0 +1,73 @@
+package schemaregistry
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncodeDecode(t *testing.T) {
+	tests := []struct {
+		name            string
+		header          Header
+		expectedFrame   []byte
+		expectedIndexes []int
+	}{
+		{
+			name:            "first message",
+			header:          Header{SchemaID: 1, Indexes: []int{0}},
+			expectedFrame:   []byte{0, 0, 0, 0, 1, 0},
+			expectedIndexes: []int{0},
+		},
+		{
+			name:            "no indexes",
+			header:          Header{SchemaID: 258},
+			expectedFrame:   []byte{0, 0, 0, 1, 2, 0},
+			expectedIndexes: []int{0},
+		},
+		{
+			name:            "nested message",
+			header:          Header{SchemaID: 7, Indexes: []int{1, 2}},
+			expectedFrame:   []byte{0, 0, 0, 0, 7, 4, 2, 4},
+			expectedIndexes: []int{1, 2},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := Encode(tt.header, []byte("payload"))
+			require.Equal(t, append(tt.expectedFrame, []byte("payload")...), data)
+
+			header, payload, err := Decode(data)
+			require.NoError(t, err)
+			require.Equal(t, Header{SchemaID: tt.header.SchemaID, Indexes: tt.expectedIndexes}, header)
+			require.Equal(t, []byte("payload"), payload)
+		})
+	}
+}
+
+func TestDecode_Invalid(t *testing.T) {
+	tests := []struct {
+		name          string
+		data          []byte
+		expectedError string
+	}{
+		{name: "empty", data: nil, expectedError: ErrUnframed.Error()},
+		{name: "unframed protobuf", data: []byte{0x0a, 0x01, 'a'}, expectedError: ErrUnframed.Error()},
+		{name: "too short", data: []byte{0, 0, 1}, expectedError: "framed message too short: 3 bytes"},
+		{name: "missing indexes", data: []byte{0, 0, 0, 0, 1}, expectedError: "invalid message indexes"},
+		{name: "truncated indexes", data: []byte{0, 0, 0, 0, 1, 4, 2}, expectedError: "invalid message indexes"},
+		{
+			name:          "huge index count",
+			data:          []byte{0, 0, 0, 0, 1, 0xfe, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0x7f},
+			expectedError: "invalid message indexes",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := Decode(tt.data)
+			require.EqualError(t, err, tt.expectedError)
+		})
+	}
+}
@@ -0,0 +1,22 @@
+package telemetry
+
+import (
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
+)
+
+// untracedPaths are polled by probes and scrapers, tracing them only adds noise.
+var untracedPaths = map[string]bool{
+	"/health":  true,
+	"/livez":   true,
+	"/readyz":  true,
+	"/metrics": true,
+}
+
+// Middleware starts a server span for every request but the probes and scrapes,
+// continuing the trace of the traceparent header.
+func Middleware(serviceName string) gin.HandlerFunc {
+	return otelgin.Middleware(serviceName, otelgin.WithGinFilter(func(c *gin.Context) bool {
+		return !untracedPaths[c.Request.URL.Path]
+	}))
+}
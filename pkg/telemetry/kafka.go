@@ -0,0 +1,93 @@
+package telemetry
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/segmentio/kafka-go"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const tracerName = "github.com/iamnotrodger/golang-projects/pkg/telemetry"
+
+// HeaderCarrier adapts the headers of a kafka message to the propagators.
+type HeaderCarrier struct {
+	Headers *[]kafka.Header
+}
+
+var _ propagation.TextMapCarrier = HeaderCarrier{}
+
+func (c HeaderCarrier) Get(key string) string {
+	for _, header := range *c.Headers {
+		if header.Key == key {
+			return string(header.Value)
+		}
+	}
+	return ""
+}
+
+// Set replaces the header key, kafka allows repeated keys but the propagators expect a single value.
+func (c HeaderCarrier) Set(key string, value string) {
+	for i, header := range *c.Headers {
+		if header.Key == key {
+			(*c.Headers)[i].Value = []byte(value)
+			return
+		}
+	}
+	*c.Headers = append(*c.Headers, kafka.Header{Key: key, Value: []byte(value)})
+}
+
+func (c HeaderCarrier) Keys() []string {
+	keys := make([]string, 0, len(*c.Headers))
+	for _, header := range *c.Headers {
+		keys = append(keys, header.Key)
+	}
+	return keys
+}
+
+// StartProducerSpan starts the span publishing msg to topic and injects its
+// trace context into the headers of msg, the consumer spans are its children.
+func StartProducerSpan(ctx context.Context, topic string, msg *kafka.Message) (context.Context, trace.Span) {
+	ctx, span := otel.Tracer(tracerName).Start(ctx, topic+" publish",
+		trace.WithSpanKind(trace.SpanKindProducer),
+		trace.WithAttributes(
+			semconv.MessagingSystemKafka,
+			semconv.MessagingOperationTypeSend,
+			semconv.MessagingDestinationName(topic),
+			semconv.MessagingKafkaMessageKey(string(msg.Key)),
+		),
+	)
+	otel.GetTextMapPropagator().Inject(ctx, HeaderCarrier{Headers: &msg.Headers})
+	return ctx, span
+}
+
+// StartConsumerSpan starts the span processing msg as a child of the trace
+// context found in its headers.
+func StartConsumerSpan(ctx context.Context, groupID string, msg *kafka.Message) (context.Context, trace.Span) {
+	ctx = otel.GetTextMapPropagator().Extract(ctx, HeaderCarrier{Headers: &msg.Headers})
+	return otel.Tracer(tracerName).Start(ctx, msg.Topic+" process",
+		trace.WithSpanKind(trace.SpanKindConsumer),
+		trace.WithAttributes(
+			semconv.MessagingSystemKafka,
+			semconv.MessagingOperationTypeProcess,
+			semconv.MessagingDestinationName(msg.Topic),
+			semconv.MessagingConsumerGroupName(groupID),
+			semconv.MessagingDestinationPartitionID(strconv.Itoa(msg.Partition)),
+			semconv.MessagingKafkaOffset(int(msg.Offset)),
+			semconv.MessagingKafkaMessageKey(string(msg.Key)),
+		),
+	)
+}
+
+// EndSpan records err on span, if any, and ends it.
+func EndSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
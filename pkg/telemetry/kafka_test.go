@@ -0,0 +1,61 @@
+package telemetry
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/segmentio/kafka-go"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+func TestHeaderCarrier(t *testing.T) {
+	headers := []kafka.Header{{Key: "request-id", Value: []byte("request-1")}}
+	carrier := HeaderCarrier{Headers: &headers}
+
+	carrier.Set("traceparent", "first")
+	carrier.Set("traceparent", "second")
+
+	require.Equal(t, "second", carrier.Get("traceparent"))
+	require.Equal(t, "", carrier.Get("tracestate"))
+	require.Equal(t, []string{"request-id", "traceparent"}, carrier.Keys())
+}
+
+func TestProducerAndConsumerSpans(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	defer otel.SetTracerProvider(noop.NewTracerProvider())
+
+	msg := kafka.Message{Key: []byte("ticket-1")}
+	_, producerSpan := StartProducerSpan(sampledContext(t), "tickets", &msg)
+	EndSpan(producerSpan, nil)
+	require.NotEmpty(t, HeaderCarrier{Headers: &msg.Headers}.Get("traceparent"))
+
+	// The consumer only sees what went through kafka.
+	received := kafka.Message{Topic: "tickets", Partition: 2, Offset: 42, Key: msg.Key, Headers: msg.Headers}
+	_, consumerSpan := StartConsumerSpan(context.Background(), "ticket-consumer-group", &received)
+	EndSpan(consumerSpan, errors.New("database unavailable"))
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 2)
+
+	producer, consumer := spans[0], spans[1]
+	require.Equal(t, "tickets publish", producer.Name())
+	require.Equal(t, trace.SpanKindProducer, producer.SpanKind())
+	require.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", producer.SpanContext().TraceID().String())
+
+	require.Equal(t, "tickets process", consumer.Name())
+	require.Equal(t, trace.SpanKindConsumer, consumer.SpanKind())
+	require.Equal(t, producer.SpanContext().TraceID(), consumer.SpanContext().TraceID())
+	require.Equal(t, producer.SpanContext().SpanID(), consumer.Parent().SpanID())
+	require.Equal(t, codes.Error, consumer.Status().Code)
+	require.Equal(t, "database unavailable", consumer.Status().Description)
+}
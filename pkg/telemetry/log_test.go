@@ -0,0 +1,34 @@
+package telemetry
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"testing"
+
+	"github.com/iamnotrodger/golang-projects/pkg/eventmeta"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLogHandler(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(NewLogHandler(slog.NewJSONHandler(&buf, nil))).With("service", "test")
+
+	ctx := eventmeta.WithRequestID(sampledContext(t), "request-1")
+	logger.InfoContext(ctx, "with context")
+	logger.Info("without context")
+
+	lines := bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n"))
+	require.Len(t, lines, 2)
+
+	var withContext, withoutContext map[string]any
+	require.NoError(t, json.Unmarshal(lines[0], &withContext))
+	require.NoError(t, json.Unmarshal(lines[1], &withoutContext))
+
+	require.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", withContext["trace_id"])
+	require.Equal(t, "00f067aa0ba902b7", withContext["span_id"])
+	require.Equal(t, "request-1", withContext["request_id"])
+	require.Equal(t, "test", withContext["service"])
+	require.NotContains(t, withoutContext, "trace_id")
+	require.NotContains(t, withoutContext, "request_id")
+}
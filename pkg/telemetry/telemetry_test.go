@@ -0,0 +1,78 @@
+package telemetry
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	pkgconfig "github.com/iamnotrodger/golang-projects/pkg/config"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestSetup_FileExporter(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "traces.jsonl")
+	config := NewConfig()
+	config.Exporter = ExporterFile
+	config.File = file
+
+	shutdown, err := Setup(context.Background(), "test-service", config)
+	require.NoError(t, err)
+
+	_, span := otel.Tracer("test").Start(context.Background(), "operation")
+	require.True(t, span.SpanContext().IsSampled())
+	span.End()
+	require.NoError(t, shutdown(context.Background()))
+
+	data, err := os.ReadFile(file)
+	require.NoError(t, err)
+	require.Contains(t, string(data), `"Name":"operation"`)
+	require.Contains(t, string(data), `"Value":"test-service"`)
+}
+
+func TestSetup_SampleRatio(t *testing.T) {
+	config := NewConfig()
+	config.SampleRatio = 0
+
+	shutdown, err := Setup(context.Background(), "test-service", config)
+	require.NoError(t, err)
+	defer shutdown(context.Background())
+
+	_, span := otel.Tracer("test").Start(context.Background(), "operation")
+	defer span.End()
+	require.True(t, span.SpanContext().IsValid())
+	require.False(t, span.SpanContext().IsSampled())
+}
+
+func TestSetup_UnsupportedExporter(t *testing.T) {
+	_, err := Setup(context.Background(), "test-service", Config{Exporter: "zipkin"})
+
+	require.EqualError(t, err, `unsupported trace exporter "zipkin"`)
+}
+
+type testSpec struct {
+	Telemetry Config `mapstructure:"telemetry"`
+}
+
+func TestConfig_Validate(t *testing.T) {
+	err := pkgconfig.Validate(&testSpec{Telemetry: Config{Exporter: ExporterOTLP, SampleRatio: 2}})
+
+	require.EqualError(t, err, "invalid configuration:"+
+		"\n  telemetry.endpoint: is required when exporter is otlp"+
+		"\n  telemetry.sample_ratio: must be at most 1, got 2")
+}
+
+func sampledContext(t *testing.T) context.Context {
+	traceID, err := trace.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+	require.NoError(t, err)
+	spanID, err := trace.SpanIDFromHex("00f067aa0ba902b7")
+	require.NoError(t, err)
+
+	return trace.ContextWithSpanContext(context.Background(), trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    traceID,
+		SpanID:     spanID,
+		TraceFlags: trace.FlagsSampled,
+	}))
+}
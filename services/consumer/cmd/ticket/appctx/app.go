@@ -3,70 +3,162 @@ package appctx
 import (
 	"context"
 	"log/slog"
+	"time"
 
+	"github.com/exaring/otelpgx"
 	"github.com/iamnotrodger/golang-projects/pkg/app"
+	"github.com/iamnotrodger/golang-projects/pkg/auth"
+	pkgconfig "github.com/iamnotrodger/golang-projects/pkg/config"
 	"github.com/iamnotrodger/golang-projects/pkg/health"
+	"github.com/iamnotrodger/golang-projects/pkg/middleware"
+	"github.com/iamnotrodger/golang-projects/pkg/schemaregistry"
+	"github.com/iamnotrodger/golang-projects/pkg/telemetry"
 	"github.com/iamnotrodger/golang-projects/services/consumer/internal/config"
 	"github.com/iamnotrodger/golang-projects/services/consumer/internal/healthcheck"
 	"github.com/iamnotrodger/golang-projects/services/consumer/internal/metrics"
 	"github.com/iamnotrodger/golang-projects/services/consumer/internal/processes"
 	"github.com/iamnotrodger/golang-projects/services/consumer/internal/ticket"
-	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/segmentio/kafka-go"
 )
 
+const (
+	consumerGroupID      = "ticket-consumer-group"
+	healthCheckInterval  = 10 * time.Second
+	healthCheckMaxAge    = 30 * time.Second
+	postgresCheckTimeout = time.Second
+)
+
+type Options struct {
+	Config   *pkgconfig.Loader[*config.Spec]
+	LogLevel *slog.LevelVar
+}
+
 type AppContext struct {
+	options           Options
+	shutdownTelemetry func(context.Context) error
 	kafkaReaderConfig *kafka.ReaderConfig
-	dbClient          *pgx.Conn
+	dbClient          *pgxpool.Pool
 	healthService     *health.Service
+	healthMonitor     *health.Monitor
 	ticketService     *ticket.Service
+	authenticator     auth.Authenticator
 }
 
-func BuildAppProcesses(appCtx *AppContext) map[string]app.Runnable {
+func (appCtx *AppContext) Processes() map[string]app.Runnable {
 	return map[string]app.Runnable{
-		"consumer": processes.NewConsumer(appCtx.kafkaReaderConfig, appCtx.ticketService.HandleMessage),
+		"consumer": app.Supervise(
+			processes.NewConsumer(appCtx.kafkaReaderConfig, config.Global.Consumer, appCtx.ticketService.HandleMessage),
+			app.ProcessOptions{
+				Restart:     app.RestartOnFailure,
+				MaxRestarts: 5,
+				Window:      time.Minute,
+				Critical:    true,
+			},
+		),
+		"http": app.Supervise(
+			processes.NewHttpServer(processes.HttpServerServices{
+				Config:        appCtx.options.Config,
+				LogLevel:      appCtx.options.LogLevel,
+				HealthMonitor: appCtx.healthMonitor,
+				Authenticator: appCtx.authenticator,
+			}),
+			app.ProcessOptions{
+				Critical:  true,
+				DependsOn: []string{"consumer"},
+			},
+		),
+		"health-monitor": appCtx.healthMonitor,
+		"config-watcher": app.Supervise(
+			appCtx.options.Config,
+			app.ProcessOptions{
+				Restart:     app.RestartOnFailure,
+				MaxRestarts: 5,
+				Window:      time.Minute,
+			},
+		),
 	}
 }
 
-func NewAppContext(ctx context.Context) *AppContext {
-	appCtx := AppContext{}
+func NewAppContext(ctx context.Context, options Options) (*AppContext, error) {
+	appCtx := AppContext{options: options}
+
+	var err error
+	appCtx.shutdownTelemetry, err = telemetry.Setup(ctx, config.ServiceName, config.Global.Telemetry)
+	if err != nil {
+		return nil, err
+	}
+
+	dialer, err := config.Global.Kafka.Dialer()
+	if err != nil {
+		return nil, err
+	}
+	kafkaClient, err := config.Global.Kafka.Client()
+	if err != nil {
+		return nil, err
+	}
 
 	appCtx.kafkaReaderConfig = &kafka.ReaderConfig{
-		Brokers: []string{config.Global.KafkaBroker},
+		Brokers: config.Global.Kafka.Brokers,
 		Topic:   config.Global.KafkaTicketTopic,
-		GroupID: "ticket-consumer-group",
+		GroupID: consumerGroupID,
+		Dialer:  dialer,
+	}
+
+	appCtx.authenticator, err = config.Global.Auth.NewAuthenticator()
+	if err != nil {
+		return nil, err
 	}
 
 	metrics.MustRegister()
-	appCtx.initDBClient(ctx)
+	health.MustRegister()
+	middleware.MustRegister()
+	if err := appCtx.initDBClient(ctx); err != nil {
+		return nil, err
+	}
 
 	ticketStore := ticket.NewStore(appCtx.dbClient)
 
 	appCtx.healthService = health.NewService(map[string]health.HealthCheck{
-		"kafka": healthcheck.NewKafkaCheck(),
+		"kafka":    healthcheck.NewKafkaCheck(kafkaClient, config.Global.KafkaTicketTopic, consumerGroupID),
+		"postgres": healthcheck.NewPostgresCheck(appCtx.dbClient, postgresCheckTimeout),
 	})
-	appCtx.ticketService = ticket.NewService(ticketStore)
+	appCtx.healthMonitor = health.NewMonitor(appCtx.healthService, healthCheckInterval, healthCheckMaxAge)
+	deserializer := schemaregistry.NewDeserializer(config.Global.SchemaRegistry.NewRegistry())
+	appCtx.ticketService = ticket.NewService(ticketStore, deserializer)
 
-	return &appCtx
+	return &appCtx, nil
 }
 
 func (a *AppContext) Shutdown(ctx context.Context) error {
 	slog.Info("shutting down application context")
 
-	err := a.dbClient.Close(ctx)
-	if err != nil {
-		slog.Error("failed to close database connection", "error", err.Error())
+	a.dbClient.Close()
+	if err := a.shutdownTelemetry(ctx); err != nil {
+		slog.Error("error flushing traces", "error", err.Error())
+		return err
 	}
-
-	return err
+	return nil
 }
 
-func (a *AppContext) initDBClient(ctx context.Context) {
-	var err error
-	a.dbClient, err = pgx.Connect(ctx, config.Global.Secret.DatabaseURL)
+func (a *AppContext) initDBClient(ctx context.Context) error {
+	poolConfig, err := pgxpool.ParseConfig(config.Global.Secret.DatabaseURL)
+	if err != nil {
+		slog.Error("failed to parse database url", "error", err.Error())
+		return err
+	}
+	poolConfig.ConnConfig.Tracer = otelpgx.NewTracer()
 
+	a.dbClient, err = pgxpool.NewWithConfig(ctx, poolConfig)
 	if err != nil {
+		slog.Error("failed to create database pool", "error", err.Error())
+		return err
+	}
+
+	if err = a.dbClient.Ping(ctx); err != nil {
+		a.dbClient.Close()
 		slog.Error("failed to connect to database", "error", err.Error())
-		panic(err)
+		return err
 	}
+	return nil
 }
@@ -2,12 +2,15 @@ package api
 
 import (
 	"context"
+	"net/http"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
+	"github.com/iamnotrodger/golang-projects/pkg/health"
 )
 
 type healthService interface {
-	Ping(context.Context) error
+	Check(ctx context.Context) health.Report
 }
 
 type HealthAPI struct {
@@ -20,10 +23,19 @@ func NewHealthAPI(service healthService) *HealthAPI {
 	}
 }
 
+func (h *HealthAPI) Live(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, health.Report{Status: health.StatusHealthy})
+}
+
+func (h *HealthAPI) Ready(ctx *gin.Context) {
+	report := h.service.Check(ctx)
+	ctx.JSON(report.HTTPStatus(), health.Report{Status: report.Status})
+}
+
 func (h *HealthAPI) Health(ctx *gin.Context) {
-	if err := h.service.Ping(ctx); err != nil {
-		ctx.JSON(500, gin.H{"status": "unhealthy"})
-		return
+	report := h.service.Check(ctx)
+	if verbose, _ := strconv.ParseBool(ctx.Query("verbose")); !verbose {
+		report.Checks = nil
 	}
-	ctx.JSON(200, gin.H{"status": "healthy"})
+	ctx.JSON(report.HTTPStatus(), report)
 }
@@ -3,12 +3,12 @@ package api
 import (
 	"context"
 	"encoding/json"
-	"errors"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 
 	"github.com/gin-gonic/gin"
+	"github.com/iamnotrodger/golang-projects/pkg/health"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 )
@@ -18,46 +18,82 @@ type MockHealthService struct {
 	mock.Mock
 }
 
-func (m *MockHealthService) Ping(ctx context.Context) error {
+func (m *MockHealthService) Check(ctx context.Context) health.Report {
 	args := m.Called(ctx)
-	return args.Error(0)
+	return args.Get(0).(health.Report)
 }
 
-func TestHealthAPI_Health(t *testing.T) {
+func TestHealthAPI(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
+	checks := []health.CheckResult{
+		{Name: "kafka", Status: health.StatusHealthy, Critical: true},
+		{Name: "database", Status: health.StatusUnhealthy, Critical: false, Error: "database connection failed"},
+	}
+
 	tests := []struct {
 		name           string
-		dbPingError    error
+		path           string
+		report         health.Report
 		expectedStatus int
 		expectedBody   map[string]any
 	}{
 		{
-			name:           "healthy database returns 200",
-			dbPingError:    nil,
+			name:           "healthy dependencies return 200",
+			path:           "/health",
+			report:         health.Report{Status: health.StatusHealthy},
 			expectedStatus: http.StatusOK,
 			expectedBody:   map[string]any{"status": "healthy"},
 		},
 		{
-			name:           "unhealthy database returns 500",
-			dbPingError:    errors.New("database connection failed"),
-			expectedStatus: http.StatusInternalServerError,
+			name:           "unhealthy dependencies return 503",
+			path:           "/health",
+			report:         health.Report{Status: health.StatusUnhealthy, Checks: checks},
+			expectedStatus: http.StatusServiceUnavailable,
+			expectedBody:   map[string]any{"status": "unhealthy"},
+		},
+		{
+			name:           "verbose health returns every check",
+			path:           "/health?verbose=1",
+			report:         health.Report{Status: health.StatusDegraded, Checks: checks},
+			expectedStatus: http.StatusOK,
+			expectedBody: map[string]any{
+				"status": "degraded",
+				"checks": []any{
+					map[string]any{"name": "kafka", "status": "healthy", "critical": true, "latency_ms": 0.0},
+					map[string]any{"name": "database", "status": "unhealthy", "critical": false, "latency_ms": 0.0, "error": "database connection failed"},
+				},
+			},
+		},
+		{
+			name:           "readiness omits checks",
+			path:           "/readyz?verbose=1",
+			report:         health.Report{Status: health.StatusUnhealthy, Checks: checks},
+			expectedStatus: http.StatusServiceUnavailable,
 			expectedBody:   map[string]any{"status": "unhealthy"},
 		},
+		{
+			name:           "liveness does not run checks",
+			path:           "/livez",
+			expectedStatus: http.StatusOK,
+			expectedBody:   map[string]any{"status": "healthy"},
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			mockService := &MockHealthService{}
-			mockService.On("Ping", tt.dbPingError).Return(tt.dbPingError)
+			mockService.On("Check", mock.Anything).Return(tt.report)
 
 			healthAPI := NewHealthAPI(mockService)
 
-			w := httptest.NewRecorder()
-			ctx, _ := gin.CreateTestContext(w)
-			ctx.Request = httptest.NewRequest(http.MethodGet, "/health", nil)
+			engine := gin.New()
+			engine.GET("/health", healthAPI.Health)
+			engine.GET("/readyz", healthAPI.Ready)
+			engine.GET("/livez", healthAPI.Live)
 
-			healthAPI.Health(ctx)
+			w := httptest.NewRecorder()
+			engine.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.path, nil))
 
 			require.Equal(t, tt.expectedStatus, w.Code)
 
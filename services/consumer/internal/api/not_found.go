@@ -1,17 +1,12 @@
 package api
 
 import (
-	"net/http"
-
 	"github.com/gin-gonic/gin"
+	"github.com/iamnotrodger/golang-projects/pkg/apierror"
 )
 
 func NotFound() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		c.JSON(http.StatusNotFound, gin.H{
-			"error":       http.StatusText(http.StatusNotFound),
-			"code":        http.StatusNotFound,
-			"description": "route not found",
-		})
+		c.Error(apierror.NotFound("route not found"))
 	}
 }
@@ -5,17 +5,20 @@ import (
 	"testing"
 
 	"github.com/gin-gonic/gin"
+	"github.com/iamnotrodger/golang-projects/pkg/middleware"
 	"github.com/stretchr/testify/assert"
 )
 
 func TestNotFound(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
-	w := httptest.NewRecorder()
-	ctx, _ := gin.CreateTestContext(w)
+	engine := gin.New()
+	engine.Use(middleware.Errors())
+	engine.NoRoute(NotFound())
 
-	NotFound()(ctx)
+	w := httptest.NewRecorder()
+	engine.ServeHTTP(w, httptest.NewRequest("GET", "/unknown", nil))
 
 	assert.Equal(t, 404, w.Code)
-	assert.JSONEq(t, `{"code":404, "description":"route not found", "error":"Not Found"}`, w.Body.String())
+	assert.JSONEq(t, `{"code":404, "description":"route not found", "error":"not_found"}`, w.Body.String())
 }
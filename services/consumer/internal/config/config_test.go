@@ -2,8 +2,10 @@ package config
 
 import (
 	"os"
+	"path/filepath"
 	"testing"
 
+	pkgconfig "github.com/iamnotrodger/golang-projects/pkg/config"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -11,27 +13,20 @@ func TestGlobal(t *testing.T) {
 	assert.Equal(t, Global.Env, defaultEnv)
 	assert.Equal(t, Global.Port, defaultPort)
 	assert.Equal(t, Global.LogLevel, defaultLogLevel)
-	assert.Equal(t, Global.KafkaBroker, defaultKafkaBroker)
+	assert.Equal(t, Global.Kafka.Brokers, []string{defaultKafkaBroker})
 	assert.Equal(t, Global.KafkaTicketTopic, defaultKafkaTicketTopic)
 	assert.Equal(t, Global.Secret.DatabaseURL, defaultDatabaseURL)
+	assert.Equal(t, Global.SchemaRegistry.URL, defaultSchemaRegistryURL)
+	assert.Equal(t, Global.Consumer.MaxAttempts, defaultMaxAttempts)
+	assert.Equal(t, Global.Consumer.RetryBackoff, defaultRetryBackoff)
 }
 
-func TestLoadConfig(t *testing.T) {
-	testConfig := New()
-
-	originalPort := os.Getenv("PORT")
-	originalLogLevel := os.Getenv("LOG_LEVEL")
-
-	defer func() {
-		os.Setenv("PORT", originalPort)
-		os.Setenv("LOG_LEVEL", originalLogLevel)
-	}()
-
+func TestLoad(t *testing.T) {
 	envLogLevel := "debug"
-	os.Setenv("LOG_LEVEL", envLogLevel)
-	os.Unsetenv("PORT")
+	t.Setenv("LOG_LEVEL", envLogLevel)
 
-	LoadConfig(testConfig)
+	testConfig, err := Load([]string{"--env", "test"}, nil)
+	assert.NoError(t, err)
 
 	cases := []struct {
 		name        string
@@ -48,6 +43,11 @@ func TestLoadConfig(t *testing.T) {
 			configValue: testConfig.Port,
 			expected:    defaultPort,
 		},
+		{
+			name:        "uses flag value when defined",
+			configValue: testConfig.Env,
+			expected:    "test",
+		},
 	}
 
 	for _, tc := range cases {
@@ -56,3 +56,48 @@ func TestLoadConfig(t *testing.T) {
 		})
 	}
 }
+
+func TestLoad_SecretFile(t *testing.T) {
+	secretFile := filepath.Join(t.TempDir(), "database_url")
+	databaseURL := "postgres://consumer:secret@db:5432/tickets"
+	assert.NoError(t, os.WriteFile(secretFile, []byte(databaseURL+"\n"), 0o600))
+	t.Setenv("DATABASE_URL_FILE", secretFile)
+
+	testConfig, err := Load(nil, nil)
+
+	assert.NoError(t, err)
+	assert.Equal(t, databaseURL, testConfig.DatabaseURL)
+}
+
+func TestLoad_Invalid(t *testing.T) {
+	t.Setenv("LOG_LEVEL", "verbose")
+	t.Setenv("KAFKA_BROKERS", "localhost:9092,localhost")
+
+	_, err := Load(nil, nil)
+
+	assert.EqualError(t, err, "invalid configuration:\n  log_level: must be one of [debug info warn error], got \"verbose\"\n  kafka.brokers[1]: must be a host:port address, got \"localhost\"")
+}
+
+func TestLoad_EmbeddedSchemaRegistry(t *testing.T) {
+	t.Setenv("SCHEMA_REGISTRY_EMBEDDED", "true")
+	t.Setenv("SCHEMA_REGISTRY_USERNAME", "admin")
+	t.Setenv("SCHEMA_REGISTRY_PASSWORD", "secret")
+
+	_, err := Load(nil, nil)
+
+	assert.EqualError(t, err, "invalid configuration:\n  schema_registry.embedded: is not supported by the consumer, set schema_registry.url")
+}
+
+func TestLoader_Snapshot(t *testing.T) {
+	t.Setenv("PORT", "9090")
+
+	loader := NewLoader(nil, nil)
+	_, err := loader.Load()
+	assert.NoError(t, err)
+
+	snapshot, err := loader.Snapshot()
+
+	assert.NoError(t, err)
+	assert.Equal(t, pkgconfig.Value{Value: 9090, Source: pkgconfig.SourceEnv}, snapshot["port"])
+	assert.Equal(t, pkgconfig.Value{Value: pkgconfig.Redacted, Source: pkgconfig.SourceDefault}, snapshot["database_url"])
+}
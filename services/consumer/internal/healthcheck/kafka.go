@@ -2,22 +2,71 @@ package healthcheck
 
 import (
 	"context"
+	"fmt"
+	"strconv"
 
-	"github.com/iamnotrodger/golang-projects/services/consumer/internal/config"
+	"github.com/iamnotrodger/golang-projects/pkg/kafkaclient"
 	"github.com/segmentio/kafka-go"
 )
 
-type KafkaCheck struct{}
+type KafkaCheck struct {
+	client  *kafka.Client
+	topic   string
+	groupID string
+}
 
-func NewKafkaCheck() *KafkaCheck {
-	return &KafkaCheck{}
+func NewKafkaCheck(client *kafka.Client, topic string, groupID string) *KafkaCheck {
+	return &KafkaCheck{
+		client:  client,
+		topic:   topic,
+		groupID: groupID,
+	}
 }
 
 func (k *KafkaCheck) Ping(ctx context.Context) error {
-	conn, err := kafka.Dial("tcp", config.Global.KafkaBroker)
+	_, err := k.Inspect(ctx)
+	return err
+}
+
+// Inspect verifies the topic and reports its partition count along with the state of the consumer group.
+func (k *KafkaCheck) Inspect(ctx context.Context) (map[string]string, error) {
+	metadata, err := k.client.Metadata(ctx, &kafka.MetadataRequest{Topics: []string{k.topic}})
+	if err != nil {
+		return nil, err
+	}
+
+	partitions, err := kafkaclient.ValidateTopic(k.topic, metadata)
+	if err != nil {
+		return nil, err
+	}
+
+	groups, err := k.client.DescribeGroups(ctx, &kafka.DescribeGroupsRequest{GroupIDs: []string{k.groupID}})
 	if err != nil {
-		return err
+		return nil, err
 	}
-	defer conn.Close()
-	return nil
+
+	state, members, err := groupState(k.groupID, groups)
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]string{
+		"partitions":    strconv.Itoa(partitions),
+		"group_state":   state,
+		"group_members": strconv.Itoa(members),
+	}, nil
+}
+
+func groupState(groupID string, response *kafka.DescribeGroupsResponse) (string, int, error) {
+	for _, group := range response.Groups {
+		if group.GroupID != groupID {
+			continue
+		}
+		if group.Error != nil {
+			return "", 0, fmt.Errorf("consumer group %s: %w", groupID, group.Error)
+		}
+		return group.GroupState, len(group.Members), nil
+	}
+
+	return "", 0, fmt.Errorf("consumer group %s not found", groupID)
 }
@@ -0,0 +1,55 @@
+package healthcheck
+
+import (
+	"testing"
+
+	"github.com/segmentio/kafka-go"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGroupState(t *testing.T) {
+	tests := []struct {
+		name            string
+		response        *kafka.DescribeGroupsResponse
+		expectedState   string
+		expectedMembers int
+		expectedError   string
+	}{
+		{
+			name: "stable group",
+			response: &kafka.DescribeGroupsResponse{Groups: []kafka.DescribeGroupsResponseGroup{{
+				GroupID:    "ticket-consumer-group",
+				GroupState: "Stable",
+				Members:    []kafka.DescribeGroupsResponseMember{{MemberID: "m1"}},
+			}}},
+			expectedState:   "Stable",
+			expectedMembers: 1,
+		},
+		{
+			name: "group error",
+			response: &kafka.DescribeGroupsResponse{Groups: []kafka.DescribeGroupsResponseGroup{{
+				GroupID: "ticket-consumer-group",
+				Error:   kafka.GroupCoordinatorNotAvailable,
+			}}},
+			expectedError: "consumer group ticket-consumer-group: " + kafka.GroupCoordinatorNotAvailable.Error(),
+		},
+		{
+			name:          "group missing from response",
+			response:      &kafka.DescribeGroupsResponse{},
+			expectedError: "consumer group ticket-consumer-group not found",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			state, members, err := groupState("ticket-consumer-group", tt.response)
+			if tt.expectedError != "" {
+				require.EqualError(t, err, tt.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.expectedState, state)
+			require.Equal(t, tt.expectedMembers, members)
+		})
+	}
+}
@@ -0,0 +1,44 @@
+package healthcheck
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+const DefaultPostgresTimeout = time.Second
+
+type querier interface {
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+type PostgresCheck struct {
+	db      querier
+	timeout time.Duration
+}
+
+func NewPostgresCheck(db querier, timeout time.Duration) *PostgresCheck {
+	if timeout <= 0 {
+		timeout = DefaultPostgresTimeout
+	}
+	return &PostgresCheck{
+		db:      db,
+		timeout: timeout,
+	}
+}
+
+func (p *PostgresCheck) Ping(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, p.timeout)
+	defer cancel()
+
+	var result int
+	if err := p.db.QueryRow(ctx, "SELECT 1").Scan(&result); err != nil {
+		return err
+	}
+	if result != 1 {
+		return fmt.Errorf("unexpected result from SELECT 1: %d", result)
+	}
+	return nil
+}
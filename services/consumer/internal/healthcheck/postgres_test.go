@@ -0,0 +1,72 @@
+package healthcheck
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeRow struct {
+	value int
+	err   error
+}
+
+func (r fakeRow) Scan(dest ...any) error {
+	if r.err != nil {
+		return r.err
+	}
+	*dest[0].(*int) = r.value
+	return nil
+}
+
+type fakeQuerier struct {
+	row      fakeRow
+	deadline time.Time
+}
+
+func (q *fakeQuerier) QueryRow(ctx context.Context, sql string, args ...any) pgx.Row {
+	q.deadline, _ = ctx.Deadline()
+	return q.row
+}
+
+func TestPostgresCheck_Ping(t *testing.T) {
+	tests := []struct {
+		name          string
+		row           fakeRow
+		expectedError string
+	}{
+		{
+			name: "healthy",
+			row:  fakeRow{value: 1},
+		},
+		{
+			name:          "query failure",
+			row:           fakeRow{err: errors.New("connection refused")},
+			expectedError: "connection refused",
+		},
+		{
+			name:          "unexpected result",
+			row:           fakeRow{value: 2},
+			expectedError: "unexpected result from SELECT 1: 2",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := &fakeQuerier{row: tt.row}
+			check := NewPostgresCheck(db, 500*time.Millisecond)
+
+			err := check.Ping(context.Background())
+			if tt.expectedError == "" {
+				require.NoError(t, err)
+			} else {
+				require.EqualError(t, err, tt.expectedError)
+			}
+			require.WithinDuration(t, time.Now().Add(500*time.Millisecond), db.deadline, 100*time.Millisecond)
+		})
+	}
+}
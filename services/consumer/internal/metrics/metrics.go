@@ -2,35 +2,64 @@ package metrics
 
 import "github.com/prometheus/client_golang/prometheus"
 
+const (
+	MessageProcessed = "processed"
+	MessageFailed    = "failed"
+	MessageRetried   = "retried"
+)
+
 type metrics struct {
-	TicketsCreatedCounter *prometheus.CounterVec
-	ErrorCounter          *prometheus.CounterVec
+	MessagesCounter       *prometheus.CounterVec
+	CommitFailuresCounter *prometheus.CounterVec
+	HandlerDuration       *prometheus.HistogramVec
+	EndToEndLatency       *prometheus.HistogramVec
+	Lag                   *prometheus.GaugeVec
 }
 
 var metric = metrics{
-	TicketsCreatedCounter: prometheus.NewCounterVec(
+	MessagesCounter: prometheus.NewCounterVec(
 		prometheus.CounterOpts{
-			Name: "producer_tickets_created_total",
-			Help: "total number of created tickets",
+			Name: "consumer_messages_total",
+			Help: "total number of kafka messages by handling result",
 		},
-		[]string{"type"},
+		[]string{"topic", "partition", "result"},
 	),
-	ErrorCounter: prometheus.NewCounterVec(
+	CommitFailuresCounter: prometheus.NewCounterVec(
 		prometheus.CounterOpts{
-			Name: "producer_error_total",
-			Help: "total number of errors",
+			Name: "consumer_commit_failures_total",
+			Help: "total number of failed offset commits",
+		},
+		[]string{"topic", "partition"},
+	),
+	HandlerDuration: prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "consumer_handler_duration_seconds",
+			Help:    "duration of each attempt at handling a kafka message",
+			Buckets: prometheus.DefBuckets,
+		},
+		[]string{"topic", "partition"},
+	),
+	EndToEndLatency: prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "consumer_end_to_end_latency_seconds",
+			Help:    "time from the creation of a ticket to its storage",
+			Buckets: []float64{.01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10, 30, 60, 300},
 		},
-		[]string{"type"},
+		[]string{"topic"},
+	),
+	Lag: prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "consumer_lag",
+			Help: "number of messages behind the end of the partition",
+		},
+		[]string{"topic", "partition"},
 	),
 }
 
 func MustRegister() {
-	prometheus.MustRegister(metric.TicketsCreatedCounter)
-	prometheus.MustRegister(metric.ErrorCounter)
-	initCounters()
-}
-
-func initCounters() {
-	metric.TicketsCreatedCounter.WithLabelValues("REPLACE_ME").Add(0)
-	metric.ErrorCounter.WithLabelValues("REPLACE_ME").Add(0)
+	prometheus.MustRegister(metric.MessagesCounter)
+	prometheus.MustRegister(metric.CommitFailuresCounter)
+	prometheus.MustRegister(metric.HandlerDuration)
+	prometheus.MustRegister(metric.EndToEndLatency)
+	prometheus.MustRegister(metric.Lag)
 }
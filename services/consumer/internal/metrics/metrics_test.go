@@ -10,6 +10,9 @@ import (
 func TestMustRegister(t *testing.T) {
 	MustRegister()
 
-	assert.True(t, prometheus.Unregister(metric.TicketsCreatedCounter))
-	assert.True(t, prometheus.Unregister(metric.ErrorCounter))
+	assert.True(t, prometheus.Unregister(metric.MessagesCounter))
+	assert.True(t, prometheus.Unregister(metric.CommitFailuresCounter))
+	assert.True(t, prometheus.Unregister(metric.HandlerDuration))
+	assert.True(t, prometheus.Unregister(metric.EndToEndLatency))
+	assert.True(t, prometheus.Unregister(metric.Lag))
 }
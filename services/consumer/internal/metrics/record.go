@@ -1,9 +1,26 @@
 package metrics
 
-func RecordTicketCreated(ticketType string) {
-	metric.TicketsCreatedCounter.WithLabelValues(ticketType).Inc()
+import (
+	"strconv"
+	"time"
+)
+
+func RecordMessage(topic string, partition int, result string) {
+	metric.MessagesCounter.WithLabelValues(topic, strconv.Itoa(partition), result).Inc()
+}
+
+func RecordCommitFailure(topic string, partition int) {
+	metric.CommitFailuresCounter.WithLabelValues(topic, strconv.Itoa(partition)).Inc()
+}
+
+func RecordHandlerDuration(topic string, partition int, duration time.Duration) {
+	metric.HandlerDuration.WithLabelValues(topic, strconv.Itoa(partition)).Observe(duration.Seconds())
+}
+
+func RecordEndToEndLatency(topic string, latency time.Duration) {
+	metric.EndToEndLatency.WithLabelValues(topic).Observe(latency.Seconds())
 }
 
-func RecordError(errorType string) {
-	metric.ErrorCounter.WithLabelValues(errorType).Inc()
+func RecordLag(topic string, partition int, lag int64) {
+	metric.Lag.WithLabelValues(topic, strconv.Itoa(partition)).Set(float64(lag))
 }
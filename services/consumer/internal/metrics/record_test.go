@@ -2,8 +2,10 @@ package metrics
 
 import (
 	"testing"
+	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	dto "github.com/prometheus/client_model/go"
 	"github.com/stretchr/testify/require"
 )
@@ -46,14 +48,35 @@ func assertCounterResults(t *testing.T, collector prometheus.Collector, name str
 	require.Equal(t, value, counterMetric.GetCounter().GetValue(), "metric value should match")
 }
 
-func TestRecordTicketCreated(t *testing.T) {
-	metric.TicketsCreatedCounter.Reset()
-	RecordTicketCreated("ticketType")
-	assertCounterResults(t, metric.TicketsCreatedCounter, "producer_tickets_created_total", 1, prometheus.Labels{"type": "ticketType"})
+func TestRecordMessage(t *testing.T) {
+	metric.MessagesCounter.Reset()
+	RecordMessage("tickets", 2, MessageRetried)
+	RecordMessage("tickets", 2, MessageRetried)
+	assertCounterResults(t, metric.MessagesCounter, "consumer_messages_total", 2, prometheus.Labels{"topic": "tickets", "partition": "2", "result": MessageRetried})
 }
 
-func TestRecordError(t *testing.T) {
-	metric.ErrorCounter.Reset()
-	RecordError("errorType")
-	assertCounterResults(t, metric.ErrorCounter, "producer_error_total", 1, prometheus.Labels{"type": "errorType"})
+func TestRecordCommitFailure(t *testing.T) {
+	metric.CommitFailuresCounter.Reset()
+	RecordCommitFailure("tickets", 0)
+	assertCounterResults(t, metric.CommitFailuresCounter, "consumer_commit_failures_total", 1, prometheus.Labels{"topic": "tickets", "partition": "0"})
+}
+
+func TestRecordHandlerDuration(t *testing.T) {
+	metric.HandlerDuration.Reset()
+	RecordHandlerDuration("tickets", 0, 20*time.Millisecond)
+	RecordHandlerDuration("tickets", 1, 20*time.Millisecond)
+	require.Equal(t, 2, testutil.CollectAndCount(metric.HandlerDuration))
+}
+
+func TestRecordEndToEndLatency(t *testing.T) {
+	metric.EndToEndLatency.Reset()
+	RecordEndToEndLatency("tickets", time.Second)
+	require.Equal(t, 1, testutil.CollectAndCount(metric.EndToEndLatency))
+}
+
+func TestRecordLag(t *testing.T) {
+	metric.Lag.Reset()
+	RecordLag("tickets", 1, 10)
+	RecordLag("tickets", 1, 4)
+	require.Equal(t, 4.0, testutil.ToFloat64(metric.Lag.WithLabelValues("tickets", "1")))
 }
@@ -3,37 +3,69 @@ package processes
 import (
 	"context"
 	"log/slog"
+	"sync"
+	"time"
 
+	"github.com/iamnotrodger/golang-projects/pkg/telemetry"
+	"github.com/iamnotrodger/golang-projects/services/consumer/internal/config"
+	"github.com/iamnotrodger/golang-projects/services/consumer/internal/metrics"
 	"github.com/segmentio/kafka-go"
 )
 
 type KafkaMessageHandler func(context.Context, kafka.Message) error
 
 type Consumer struct {
-	reader  *kafka.Reader
-	handler KafkaMessageHandler
+	config    *kafka.ReaderConfig
+	retry     config.Consumer
+	handler   KafkaMessageHandler
+	ready     chan struct{}
+	readyOnce sync.Once
 }
 
-func NewConsumer(config *kafka.ReaderConfig, handler KafkaMessageHandler) *Consumer {
+func NewConsumer(readerConfig *kafka.ReaderConfig, retry config.Consumer, handler KafkaMessageHandler) *Consumer {
 	return &Consumer{
-		reader:  kafka.NewReader(*config),
+		config:  readerConfig,
+		retry:   retry,
 		handler: handler,
+		ready:   make(chan struct{}),
 	}
 }
 
+// Run creates a new reader on every call, a closed reader cannot be reused when the consumer is restarted.
 func (c *Consumer) Run(ctx context.Context, errChan chan error) {
-	go c.start(ctx, errChan)
-	c.stop(ctx)
+	reader := kafka.NewReader(*c.config)
+	go c.start(ctx, reader, errChan)
+	c.stop(ctx, reader)
 }
 
-func (c *Consumer) start(ctx context.Context, errChan chan error) {
+func (c *Consumer) Ready() <-chan struct{} {
+	return c.ready
+}
+
+func (c *Consumer) start(ctx context.Context, reader *kafka.Reader, errChan chan error) {
+	if err := c.connect(ctx); err != nil {
+		if ctx.Err() == nil {
+			errChan <- err
+		}
+		return
+	}
+	c.readyOnce.Do(func() {
+		close(c.ready)
+	})
+
 	for {
-		message, err := c.reader.FetchMessage(ctx)
+		message, err := reader.FetchMessage(ctx)
 		if err != nil {
-			errChan <- err
+			if ctx.Err() == nil {
+				errChan <- err
+			}
 			return
 		}
 
+		// The high water mark is read along with the message, kafka-go's reader
+		// stats only keep the lag of the last partition fetched by the group.
+		metrics.RecordLag(message.Topic, message.Partition, message.HighWaterMark-message.Offset-1)
+
 		slog.Info(
 			"received message",
 			"topic", message.Topic,
@@ -43,21 +75,79 @@ func (c *Consumer) start(ctx context.Context, errChan chan error) {
 			"value_length", len(message.Value),
 		)
 
-		if err := c.handler(ctx, message); err != nil {
-			slog.Error("failed to handle kafka message", "error", err.Error(), "topic", message.Topic)
+		if err := c.handle(ctx, message); err != nil {
+			metrics.RecordMessage(message.Topic, message.Partition, metrics.MessageFailed)
 			continue
 		}
+		metrics.RecordMessage(message.Topic, message.Partition, metrics.MessageProcessed)
 
-		if err := c.reader.CommitMessages(ctx, message); err != nil {
+		if err := reader.CommitMessages(ctx, message); err != nil {
+			metrics.RecordCommitFailure(message.Topic, message.Partition)
 			slog.Error("failed to commit kafka message", "error", err.Error(), "topic", message.Topic)
 		}
 	}
 }
 
-func (c *Consumer) stop(ctx context.Context) {
+// handle runs the handler until it succeeds or its attempts are exhausted,
+// each attempt is traced and timed separately.
+func (c *Consumer) handle(ctx context.Context, message kafka.Message) error {
+	backoff := c.retry.RetryBackoff
+	for attempt := 1; ; attempt++ {
+		handlerCtx, span := telemetry.StartConsumerSpan(ctx, c.config.GroupID, &message)
+		start := time.Now()
+		err := c.handler(handlerCtx, message)
+		metrics.RecordHandlerDuration(message.Topic, message.Partition, time.Since(start))
+		telemetry.EndSpan(span, err)
+		if err == nil {
+			return nil
+		}
+
+		if attempt >= c.retry.MaxAttempts || ctx.Err() != nil {
+			slog.ErrorContext(handlerCtx, "failed to handle kafka message", "error", err.Error(), "topic", message.Topic, "attempts", attempt)
+			return err
+		}
+
+		slog.WarnContext(handlerCtx, "retrying kafka message", "error", err.Error(), "topic", message.Topic, "attempt", attempt, "backoff", backoff)
+		metrics.RecordMessage(message.Topic, message.Partition, metrics.MessageRetried)
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+// connect checks that a broker is reachable and the topic exists before the consumer reports ready.
+func (c *Consumer) connect(ctx context.Context) error {
+	dialer := c.config.Dialer
+	if dialer == nil {
+		dialer = kafka.DefaultDialer
+	}
+
+	var err error
+	for _, broker := range c.config.Brokers {
+		var conn *kafka.Conn
+		conn, err = dialer.DialContext(ctx, "tcp", broker)
+		if err != nil {
+			continue
+		}
+
+		_, err = conn.ReadPartitions(c.config.Topic)
+		conn.Close()
+		if err == nil {
+			return nil
+		}
+	}
+
+	return err
+}
+
+func (c *Consumer) stop(ctx context.Context, reader *kafka.Reader) {
 	<-ctx.Done()
 
-	if err := c.reader.Close(); err != nil {
+	if err := reader.Close(); err != nil {
 		slog.Error("consumer shutdown failed", "error", err)
 	} else {
 		slog.Info("consumer shutdown complete")
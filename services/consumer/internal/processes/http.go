@@ -4,35 +4,69 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"net"
 	"net/http"
+	"sync"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/iamnotrodger/golang-projects/pkg/app"
+	"github.com/iamnotrodger/golang-projects/pkg/auth"
+	pkgconfig "github.com/iamnotrodger/golang-projects/pkg/config"
 	"github.com/iamnotrodger/golang-projects/pkg/health"
+	"github.com/iamnotrodger/golang-projects/pkg/middleware"
+	"github.com/iamnotrodger/golang-projects/pkg/telemetry"
 	"github.com/iamnotrodger/golang-projects/services/consumer/internal/api"
 	"github.com/iamnotrodger/golang-projects/services/consumer/internal/config"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 )
 
 type HttpServer struct {
-	server *http.Server
+	server    *http.Server
+	ready     chan struct{}
+	readyOnce sync.Once
 }
 
 type HttpServerServices struct {
-	HealthService *health.Service
+	Config        *pkgconfig.Loader[*config.Spec]
+	LogLevel      *slog.LevelVar
+	HealthMonitor *health.Monitor
+	// Authenticator authenticates the admin routes, they are not registered
+	// when it is nil.
+	Authenticator auth.Authenticator
 }
 
 func NewHttpServer(services HttpServerServices) *HttpServer {
 	engine := gin.New()
+	middleware.TrustProxies(engine, config.Global.HTTP.TrustedProxies)
 
-	// g.Use(gin.LoggerWithFormatter(logFormatter), gin.Recovery(), gerror.Handler(), location.Default())
+	engine.Use(
+		telemetry.Middleware(config.ServiceName),
+		middleware.RequestID(),
+		middleware.Metrics(),
+		middleware.Logger(config.Global.HTTP.AccessLog),
+		middleware.Errors(),
+		middleware.Recovery(),
+		middleware.CORS(config.Global.HTTP.CORS),
+	)
 	engine.NoRoute(api.NotFound())
 
-	healthHandler := api.NewHealthAPI(services.HealthService)
+	healthHandler := api.NewHealthAPI(services.HealthMonitor)
 
 	engine.Match([]string{"GET", "HEAD"}, "/health", healthHandler.Health)
+	engine.Match([]string{"GET", "HEAD"}, "/livez", healthHandler.Live)
+	engine.Match([]string{"GET", "HEAD"}, "/readyz", healthHandler.Ready)
 	engine.GET("/metrics", gin.WrapH(promhttp.Handler()))
 
+	// The configuration and log level are never served open.
+	if services.Authenticator != nil {
+		serviceAdmin := engine.Group("", auth.Require(services.Authenticator, auth.ScopeServiceAdmin))
+		serviceAdmin.GET("/debug/config", gin.WrapH(pkgconfig.Handler(services.Config.Snapshot)))
+		serviceAdmin.Match([]string{"GET", "PUT"}, "/admin/log-level", gin.WrapH(app.LogLevelHandler(services.LogLevel, app.ConfigLogLevel(services.Config.Set, "log_level"))))
+	} else {
+		slog.Warn("config and log level routes are disabled because authentication is disabled")
+	}
+
 	server := &http.Server{
 		Addr:         fmt.Sprintf("0.0.0.0:%v", config.Global.Port),
 		WriteTimeout: 15 * time.Second,
@@ -40,16 +74,32 @@ func NewHttpServer(services HttpServerServices) *HttpServer {
 		Handler:      engine,
 	}
 
-	return &HttpServer{server}
+	return &HttpServer{
+		server: server,
+		ready:  make(chan struct{}),
+	}
 }
 
 func (h *HttpServer) Run(ctx context.Context, errChan chan error) {
-	go h.start(errChan)
+	listener, err := net.Listen("tcp", h.server.Addr)
+	if err != nil {
+		errChan <- err
+		return
+	}
+	h.readyOnce.Do(func() {
+		close(h.ready)
+	})
+
+	go h.start(listener, errChan)
 	h.stop(ctx)
 }
 
-func (h *HttpServer) start(errChan chan error) {
-	errChan <- h.server.ListenAndServe()
+func (h *HttpServer) Ready() <-chan struct{} {
+	return h.ready
+}
+
+func (h *HttpServer) start(listener net.Listener, errChan chan error) {
+	errChan <- h.server.Serve(listener)
 }
 
 func (h *HttpServer) stop(ctx context.Context) {
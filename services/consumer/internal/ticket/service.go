@@ -3,8 +3,12 @@ package ticket
 import (
 	"context"
 	"log/slog"
+	"time"
 
+	"github.com/iamnotrodger/golang-projects/pkg/eventmeta"
 	"github.com/iamnotrodger/golang-projects/pkg/proto/topics"
+	"github.com/iamnotrodger/golang-projects/pkg/schemaregistry"
+	"github.com/iamnotrodger/golang-projects/services/consumer/internal/metrics"
 	"github.com/segmentio/kafka-go"
 	"google.golang.org/protobuf/proto"
 )
@@ -13,34 +17,59 @@ type ticketStore interface {
 	CreateTicket(ctx context.Context, ticket *topics.Ticket) error
 }
 
+type ticketDeserializer interface {
+	Deserialize(ctx context.Context, data []byte, target proto.Message) (schemaregistry.Header, error)
+}
+
 type Service struct {
-	store ticketStore
+	store        ticketStore
+	deserializer ticketDeserializer
 }
 
-func NewService(store ticketStore) *Service {
+func NewService(store ticketStore, deserializer ticketDeserializer) *Service {
 	return &Service{
-		store: store,
+		store:        store,
+		deserializer: deserializer,
 	}
 }
 
+// HandleMessage stores the ticket of msg, the metadata of its headers is added
+// to ctx and to the logs so the ticket can be traced back to its request.
 func (t *Service) HandleMessage(ctx context.Context, msg kafka.Message) error {
+	metadata := eventmeta.FromHeaders(msg.Headers)
+	ctx = eventmeta.NewContext(ctx, metadata)
+	logger := slog.With(metadata.LogAttrs()...)
+
 	var ticket topics.Ticket
 
-	if err := proto.Unmarshal(msg.Value, &ticket); err != nil {
-		slog.Error(
-			"failed to unmarshal ticket from protobuf",
+	header, err := t.deserializer.Deserialize(ctx, msg.Value, &ticket)
+	if err != nil {
+		logger.ErrorContext(
+			ctx,
+			"failed to deserialize ticket",
 			"error", err.Error(),
+			"schema_id", header.SchemaID,
 			"raw_value", string(msg.Value),
 		)
 		return err
 	}
 
-	slog.Info("creating ticket", "id", ticket.Id)
+	logger.InfoContext(ctx, "creating ticket", "id", ticket.Id, "schema_id", header.SchemaID)
 
 	if err := t.store.CreateTicket(ctx, &ticket); err != nil {
-		slog.Error("failed to create ticket", "error", err.Error())
+		logger.ErrorContext(ctx, "failed to create ticket", "error", err.Error())
 		return err
 	}
 
+	metrics.RecordEndToEndLatency(msg.Topic, time.Since(createdAt(&ticket, msg)))
 	return nil
 }
+
+// createdAt returns the creation time of the ticket, falling back to the time
+// the message was appended to the topic.
+func createdAt(ticket *topics.Ticket, msg kafka.Message) time.Time {
+	if ticket.GetCreatedAt().IsValid() {
+		return ticket.CreatedAt.AsTime()
+	}
+	return msg.Time
+}
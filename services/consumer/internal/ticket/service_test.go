@@ -6,7 +6,9 @@ import (
 	"testing"
 	"time"
 
+	"github.com/iamnotrodger/golang-projects/pkg/eventmeta"
 	"github.com/iamnotrodger/golang-projects/pkg/proto/topics"
+	"github.com/iamnotrodger/golang-projects/pkg/schemaregistry"
 	"github.com/segmentio/kafka-go"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
@@ -39,6 +41,11 @@ func TestHandleMessage(t *testing.T) {
 	}
 	validTicketBytes, _ := proto.Marshal(validTicket)
 
+	registry := schemaregistry.NewMemory()
+	serializer := schemaregistry.NewSerializer(registry, "tickets-value", &topics.Ticket{})
+	require.NoError(t, serializer.Register(context.Background()))
+	framedTicketBytes, _ := serializer.Serialize(validTicket)
+
 	tests := []testCase{
 		{
 			name: "successfully handles valid message",
@@ -55,6 +62,53 @@ func TestHandleMessage(t *testing.T) {
 			}(),
 			expectedError: nil,
 		},
+		{
+			name: "decodes message framed with the writer's schema",
+			message: kafka.Message{
+				Key:   []byte("ticket-123"),
+				Value: framedTicketBytes,
+			},
+			store: func() *MockTicketStore {
+				m := &MockTicketStore{}
+				m.On("CreateTicket", mock.Anything, mock.MatchedBy(func(t *topics.Ticket) bool {
+					return t.Id == "ticket-123" && t.Price == 99.99
+				})).Return(nil)
+				return m
+			}(),
+			expectedError: nil,
+		},
+		{
+			name: "adds the header metadata to the context",
+			message: kafka.Message{
+				Key:   []byte("ticket-123"),
+				Value: framedTicketBytes,
+				Headers: eventmeta.Metadata{
+					RequestID:       "request-1",
+					Traceparent:     "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+					ProducerService: "producer",
+				}.Headers(),
+			},
+			store: func() *MockTicketStore {
+				m := &MockTicketStore{}
+				m.On("CreateTicket", mock.MatchedBy(func(ctx context.Context) bool {
+					metadata, ok := eventmeta.FromContext(ctx)
+					return ok && metadata.ProducerService == "producer" &&
+						eventmeta.RequestID(ctx) == "request-1" &&
+						metadata.Traceparent == "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+				}), mock.Anything).Return(nil)
+				return m
+			}(),
+			expectedError: nil,
+		},
+		{
+			name: "returns error on unknown schema",
+			message: kafka.Message{
+				Key:   []byte("ticket-123"),
+				Value: schemaregistry.Encode(schemaregistry.Header{SchemaID: 2}, validTicketBytes),
+			},
+			store:         &MockTicketStore{},
+			expectedError: errors.New("fetch schema 2"),
+		},
 		{
 			name: "returns error on invalid protobuf",
 			message: kafka.Message{
@@ -95,7 +149,7 @@ func TestHandleMessage(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 
-			service := NewService(tt.store)
+			service := NewService(tt.store, schemaregistry.NewDeserializer(registry))
 			ctx := context.Background()
 
 			err := service.HandleMessage(ctx, tt.message)
@@ -111,3 +165,31 @@ func TestHandleMessage(t *testing.T) {
 		})
 	}
 }
+
+func TestCreatedAt(t *testing.T) {
+	ticketTime := time.Date(2025, 10, 27, 12, 0, 0, 0, time.UTC)
+	messageTime := time.Date(2025, 10, 27, 12, 0, 1, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		ticket   *topics.Ticket
+		expected time.Time
+	}{
+		{
+			name:     "uses the creation time of the ticket",
+			ticket:   &topics.Ticket{CreatedAt: timestamppb.New(ticketTime)},
+			expected: ticketTime,
+		},
+		{
+			name:     "falls back to the message time",
+			ticket:   &topics.Ticket{},
+			expected: messageTime,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.expected, createdAt(tt.ticket, kafka.Message{Time: messageTime}))
+		})
+	}
+}
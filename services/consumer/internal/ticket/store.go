@@ -4,14 +4,14 @@ import (
 	"context"
 
 	"github.com/iamnotrodger/golang-projects/pkg/proto/topics"
-	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
 )
 
 type Store struct {
-	dbClient *pgx.Conn
+	dbClient *pgxpool.Pool
 }
 
-func NewStore(dbClient *pgx.Conn) *Store {
+func NewStore(dbClient *pgxpool.Pool) *Store {
 	return &Store{
 		dbClient: dbClient,
 	}
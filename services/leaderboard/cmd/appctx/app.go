@@ -2,62 +2,199 @@ package appctx
 
 import (
 	"context"
+	"errors"
 	"log/slog"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/iamnotrodger/golang-projects/pkg/app"
+	"github.com/iamnotrodger/golang-projects/pkg/auth"
+	pkgconfig "github.com/iamnotrodger/golang-projects/pkg/config"
 	"github.com/iamnotrodger/golang-projects/pkg/health"
+	"github.com/iamnotrodger/golang-projects/pkg/middleware"
+	"github.com/iamnotrodger/golang-projects/pkg/ratelimit"
+	"github.com/iamnotrodger/golang-projects/pkg/telemetry"
+	"github.com/iamnotrodger/golang-projects/services/leaderboard/internal/admin"
 	"github.com/iamnotrodger/golang-projects/services/leaderboard/internal/config"
+	"github.com/iamnotrodger/golang-projects/services/leaderboard/internal/friends"
 	"github.com/iamnotrodger/golang-projects/services/leaderboard/internal/healthcheck"
+	"github.com/iamnotrodger/golang-projects/services/leaderboard/internal/history"
+	"github.com/iamnotrodger/golang-projects/services/leaderboard/internal/integrity"
 	"github.com/iamnotrodger/golang-projects/services/leaderboard/internal/leaderboard"
 	"github.com/iamnotrodger/golang-projects/services/leaderboard/internal/processes"
 	"github.com/iamnotrodger/golang-projects/services/leaderboard/internal/score"
+	"github.com/iamnotrodger/golang-projects/services/leaderboard/internal/team"
+	"github.com/redis/go-redis/extra/redisotel/v9"
 	"github.com/redis/go-redis/v9"
 )
 
+const (
+	healthCheckInterval = 10 * time.Second
+	healthCheckMaxAge   = 30 * time.Second
+)
+
+type Options struct {
+	Config   *pkgconfig.Loader[*config.Spec]
+	LogLevel *slog.LevelVar
+}
+
 type AppContext struct {
-	engine        *gin.Engine
-	rdb           *redis.Client
-	hub           *leaderboard.Hub
-	scoreService  *score.Service
-	healthService *health.Service
+	options           Options
+	shutdownTelemetry func(context.Context) error
+	engine            *gin.Engine
+	rdb               *redis.Client
+	hub               *leaderboard.Hub
+	teamHub           *leaderboard.Hub
+	scoreService      *score.Service
+	adminService      *admin.Service
+	historyService    *history.Service
+	teamService       *team.Service
+	friendsService    *friends.Service
+	scoreGuard        *integrity.Guard
+	reviewStore       *integrity.Store
+	authenticator     auth.Authenticator
+	rateLimiter       ratelimit.Limiter
+	healthService     *health.Service
+	healthMonitor     *health.Monitor
 }
 
-func BuildAppProcesses(appCtx *AppContext) map[string]app.Runnable {
+func (appCtx *AppContext) Processes() map[string]app.Runnable {
 	httpServices := processes.HttpServerServices{
-		ScoreService:  appCtx.scoreService,
-		HealthService: appCtx.healthService,
+		ScoreService:   appCtx.scoreService,
+		AdminService:   appCtx.adminService,
+		HistoryService: appCtx.historyService,
+		TeamService:    appCtx.teamService,
+		TeamHub:        appCtx.teamHub,
+		FriendsService: appCtx.friendsService,
+		ScoreGuard:     appCtx.scoreGuard,
+		ReviewStore:    appCtx.reviewStore,
+		Config:         appCtx.options.Config,
+		LogLevel:       appCtx.options.LogLevel,
+		HealthMonitor:  appCtx.healthMonitor,
+		Authenticator:  appCtx.authenticator,
+		RateLimiter:    appCtx.rateLimiter,
+	}
+
+	runnables := map[string]app.Runnable{
+		"http": app.Supervise(
+			processes.NewHttpServer(appCtx.engine, appCtx.hub, httpServices),
+			app.ProcessOptions{
+				Critical:  true,
+				DependsOn: []string{"leaderboard-subscriber", "team-subscriber"},
+			},
+		),
+		"leaderboard-subscriber": app.Supervise(
+			processes.NewLeaderSubscriber(appCtx.rdb, score.TopScoresChannel, appCtx.hub.Broadcast),
+			app.ProcessOptions{
+				Restart:     app.RestartOnFailure,
+				MaxRestarts: 5,
+				Window:      time.Minute,
+				Critical:    true,
+			},
+		),
+		"team-subscriber": app.Supervise(
+			processes.NewLeaderSubscriber(appCtx.rdb, team.TopScoresChannel, appCtx.teamHub.Broadcast),
+			app.ProcessOptions{
+				Restart:     app.RestartOnFailure,
+				MaxRestarts: 5,
+				Window:      time.Minute,
+				Critical:    true,
+			},
+		),
+		"health-monitor": appCtx.healthMonitor,
+		"config-watcher": app.Supervise(
+			appCtx.options.Config,
+			app.ProcessOptions{
+				Restart:     app.RestartOnFailure,
+				MaxRestarts: 5,
+				Window:      time.Minute,
+			},
+		),
 	}
 
-	return map[string]app.Runnable{
-		"http":                   processes.NewHttpServer(appCtx.engine, appCtx.hub, httpServices),
-		"leaderboard-subscriber": processes.NewLeaderSubscriber(appCtx.rdb, appCtx.hub.Broadcast),
+	if teams := config.Global.Teams; teams.Mode == team.ModeRebuild {
+		runnables["team-rebuilder"] = processes.NewTeamRebuilder(appCtx.teamService, teams.RebuildInterval)
 	}
+	return runnables
 }
 
-func NewAppContext(ctx context.Context) *AppContext {
-	appCtx := AppContext{}
+func NewAppContext(ctx context.Context, options Options) (*AppContext, error) {
+	appCtx := AppContext{options: options}
+
+	health.MustRegister()
+	middleware.MustRegister()
+	integrity.MustRegister()
+
+	var err error
+	appCtx.shutdownTelemetry, err = telemetry.Setup(ctx, config.ServiceName, config.Global.Telemetry)
+	if err != nil {
+		return nil, err
+	}
 
 	appCtx.engine = gin.New()
+	middleware.TrustProxies(appCtx.engine, config.Global.HTTP.TrustedProxies)
 	appCtx.hub = leaderboard.NewHub()
+	appCtx.teamHub = leaderboard.NewHub()
 	appCtx.rdb = redis.NewClient(&redis.Options{
 		Addr:     config.Global.RedisAddr,
 		Password: config.Global.RedisPassword,
 		DB:       config.Global.RedisDb,
 	})
+	if err := redisotel.InstrumentTracing(appCtx.rdb); err != nil {
+		return nil, err
+	}
+
+	appCtx.authenticator, err = config.Global.Auth.NewAuthenticator()
+	if err != nil {
+		return nil, err
+	}
+
+	if config.Global.RateLimit.Backend == ratelimit.BackendRedis {
+		appCtx.rateLimiter = ratelimit.NewRedis(appCtx.rdb)
+	} else {
+		appCtx.rateLimiter = ratelimit.NewMemory()
+	}
 
 	appCtx.scoreService = score.NewService(appCtx.rdb)
+	appCtx.adminService = admin.NewService(appCtx.rdb)
+	appCtx.historyService = history.NewService(appCtx.rdb, func() history.Config {
+		return options.Config.Current().History
+	})
+	appCtx.friendsService = friends.NewService(appCtx.rdb, func() friends.Config {
+		return options.Config.Current().Friends
+	})
+	appCtx.teamService = team.NewService(appCtx.rdb, func() team.Config {
+		return options.Config.Current().Teams
+	}, func() int {
+		return options.Config.Current().TopK
+	})
+	if config.Global.Integrity.RequireSignature && config.Global.ScoreSigningSecret == "" {
+		return nil, errors.New("score signatures are required but SCORE_SIGNING_SECRET is not set")
+	}
+	integrityConfig := func() integrity.Config {
+		return options.Config.Current().Integrity
+	}
+	appCtx.reviewStore = integrity.NewStore(appCtx.rdb, score.Board, func() int64 {
+		return integrityConfig().ReviewLength
+	})
+	appCtx.scoreGuard = integrity.NewGuard(appCtx.reviewStore, score.Board, config.Global.ScoreSigningSecret, integrityConfig)
 	appCtx.healthService = health.NewService(map[string]health.HealthCheck{
 		"redis": healthcheck.NewRedisCheck(appCtx.rdb),
 	})
+	appCtx.healthMonitor = health.NewMonitor(appCtx.healthService, healthCheckInterval, healthCheckMaxAge)
 
-	return &appCtx
+	return &appCtx, nil
 }
 
 func (a *AppContext) Shutdown(ctx context.Context) error {
+	var errs []error
 	if err := a.rdb.Close(); err != nil {
 		slog.Error("error closing redis client", "error", err.Error())
-		return err
+		errs = append(errs, err)
+	}
+	if err := a.shutdownTelemetry(ctx); err != nil {
+		slog.Error("error flushing traces", "error", err.Error())
+		errs = append(errs, err)
 	}
-	return nil
+	return errors.Join(errs...)
 }
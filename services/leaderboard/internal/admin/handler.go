@@ -0,0 +1,211 @@
+package admin
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/iamnotrodger/golang-projects/pkg/apierror"
+	"github.com/iamnotrodger/golang-projects/pkg/auth"
+	"github.com/iamnotrodger/golang-projects/services/leaderboard/internal/model"
+)
+
+// ScopeLeaderboardAdmin allows correcting the leaderboard and banning players.
+const ScopeLeaderboardAdmin = "leaderboard:admin"
+
+type adminService interface {
+	DeletePlayer(ctx context.Context, playerID, reason, actor string) error
+	AdjustScore(ctx context.Context, playerID string, value int, reason, actor string) error
+	Reset(ctx context.Context, reason, actor string) error
+	Archive(ctx context.Context, reason, actor string) (string, error)
+	Ban(ctx context.Context, playerID, reason, actor string) error
+	Unban(ctx context.Context, playerID, reason, actor string) error
+	Bans(ctx context.Context) ([]Ban, error)
+}
+
+type scoreService interface {
+	GetTopK(ctx context.Context, k int) ([]model.Score, error)
+	PublishTopScores(ctx context.Context, topScores []model.Score) error
+}
+
+// teamService recomputes the team scores changed by the actions.
+type teamService interface {
+	Refresh(ctx context.Context, playerID string) error
+	Rebuild(ctx context.Context) error
+}
+
+type reasonRequest struct {
+	Reason string `json:"reason"`
+}
+
+type adjustRequest struct {
+	Value  *int   `json:"value" binding:"required"`
+	Reason string `json:"reason" binding:"required"`
+}
+
+type Handler struct {
+	service adminService
+	scores  scoreService
+	teams   teamService
+	topK    func() int
+}
+
+// NewHandler creates the admin handler, the top scores are republished
+// through scores and the team scores recomputed through teams after every
+// action.
+func NewHandler(service adminService, scores scoreService, teams teamService, topK func() int) *Handler {
+	return &Handler{service: service, scores: scores, teams: teams, topK: topK}
+}
+
+func (h *Handler) RegisterRoutes(r gin.IRouter) {
+	admin := r.Group("/admin/leaderboard")
+	{
+		admin.DELETE("/players/:id", h.deletePlayer)
+		admin.PUT("/players/:id/score", h.adjustScore)
+		admin.POST("/reset", h.reset)
+		admin.POST("/archive", h.archive)
+		admin.GET("/bans", h.listBans)
+		admin.PUT("/bans/:id", h.ban)
+		admin.DELETE("/bans/:id", h.unban)
+	}
+}
+
+func (h *Handler) deletePlayer(c *gin.Context) {
+	request, ok := bindReason(c)
+	if !ok {
+		return
+	}
+	if err := h.service.DeletePlayer(c.Request.Context(), c.Param("id"), request.Reason, actor(c)); err != nil {
+		c.Error(err)
+		return
+	}
+	h.republish(c.Request.Context())
+	h.refreshTeam(c.Request.Context(), c.Param("id"))
+	c.Status(http.StatusNoContent)
+}
+
+func (h *Handler) adjustScore(c *gin.Context) {
+	var request adjustRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.Error(apierror.Validation("a value and a reason are required", err))
+		return
+	}
+	if err := h.service.AdjustScore(c.Request.Context(), c.Param("id"), *request.Value, request.Reason, actor(c)); err != nil {
+		c.Error(err)
+		return
+	}
+	h.republish(c.Request.Context())
+	h.refreshTeam(c.Request.Context(), c.Param("id"))
+	c.Status(http.StatusNoContent)
+}
+
+func (h *Handler) reset(c *gin.Context) {
+	request, ok := bindReason(c)
+	if !ok {
+		return
+	}
+	if err := h.service.Reset(c.Request.Context(), request.Reason, actor(c)); err != nil {
+		c.Error(err)
+		return
+	}
+	h.republish(c.Request.Context())
+	h.rebuildTeams(c.Request.Context())
+	c.Status(http.StatusNoContent)
+}
+
+func (h *Handler) archive(c *gin.Context) {
+	request, ok := bindReason(c)
+	if !ok {
+		return
+	}
+	archive, err := h.service.Archive(c.Request.Context(), request.Reason, actor(c))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	h.republish(c.Request.Context())
+	h.rebuildTeams(c.Request.Context())
+	c.JSON(http.StatusOK, gin.H{"archive": archive})
+}
+
+func (h *Handler) listBans(c *gin.Context) {
+	bans, err := h.service.Bans(c.Request.Context())
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"bans": bans})
+}
+
+func (h *Handler) ban(c *gin.Context) {
+	request, ok := bindReason(c)
+	if !ok {
+		return
+	}
+	if err := h.service.Ban(c.Request.Context(), c.Param("id"), request.Reason, actor(c)); err != nil {
+		c.Error(err)
+		return
+	}
+	h.republish(c.Request.Context())
+	c.Status(http.StatusNoContent)
+}
+
+func (h *Handler) unban(c *gin.Context) {
+	request, ok := bindReason(c)
+	if !ok {
+		return
+	}
+	if err := h.service.Unban(c.Request.Context(), c.Param("id"), request.Reason, actor(c)); err != nil {
+		c.Error(err)
+		return
+	}
+	h.republish(c.Request.Context())
+	c.Status(http.StatusNoContent)
+}
+
+// republish sends the corrected top scores to the connected clients. The
+// action succeeded already, a failure is only logged.
+func (h *Handler) republish(ctx context.Context) {
+	topScores, err := h.scores.GetTopK(ctx, h.topK())
+	if err != nil {
+		slog.ErrorContext(ctx, "admin error getting top scores", "error", err.Error())
+		return
+	}
+	if err := h.scores.PublishTopScores(ctx, topScores); err != nil {
+		slog.ErrorContext(ctx, "admin error publishing top scores", "error", err.Error())
+	}
+}
+
+// refreshTeam recomputes the team of the corrected player, the action
+// succeeded already so a failure is only logged.
+func (h *Handler) refreshTeam(ctx context.Context, playerID string) {
+	if err := h.teams.Refresh(ctx, playerID); err != nil {
+		slog.ErrorContext(ctx, "admin error recomputing team score", "player_id", playerID, "error", err.Error())
+	}
+}
+
+// rebuildTeams recomputes every team once the whole leaderboard changed.
+func (h *Handler) rebuildTeams(ctx context.Context) {
+	if err := h.teams.Rebuild(ctx); err != nil {
+		slog.ErrorContext(ctx, "admin error rebuilding team scores", "error", err.Error())
+	}
+}
+
+// bindReason reads the optional audit reason of an action.
+func bindReason(c *gin.Context) (reasonRequest, bool) {
+	var request reasonRequest
+	if c.Request.ContentLength == 0 {
+		return request, true
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.Error(apierror.Validation("invalid request", err))
+		return request, false
+	}
+	return request, true
+}
+
+func actor(c *gin.Context) string {
+	principal, _ := auth.FromContext(c.Request.Context())
+	return principal.Subject
+}
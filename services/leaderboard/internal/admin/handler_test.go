@@ -0,0 +1,215 @@
+package admin
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/iamnotrodger/golang-projects/pkg/apierror"
+	"github.com/iamnotrodger/golang-projects/pkg/auth"
+	"github.com/iamnotrodger/golang-projects/pkg/middleware"
+	"github.com/iamnotrodger/golang-projects/services/leaderboard/internal/model"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockAdminService struct {
+	mock.Mock
+}
+
+func (m *MockAdminService) DeletePlayer(ctx context.Context, player, reason, actor string) error {
+	return m.Called(ctx, player, reason, actor).Error(0)
+}
+
+func (m *MockAdminService) AdjustScore(ctx context.Context, player string, value int, reason, actor string) error {
+	return m.Called(ctx, player, value, reason, actor).Error(0)
+}
+
+func (m *MockAdminService) Reset(ctx context.Context, reason, actor string) error {
+	return m.Called(ctx, reason, actor).Error(0)
+}
+
+func (m *MockAdminService) Archive(ctx context.Context, reason, actor string) (string, error) {
+	args := m.Called(ctx, reason, actor)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockAdminService) Ban(ctx context.Context, playerID, reason, actor string) error {
+	return m.Called(ctx, playerID, reason, actor).Error(0)
+}
+
+func (m *MockAdminService) Unban(ctx context.Context, playerID, reason, actor string) error {
+	return m.Called(ctx, playerID, reason, actor).Error(0)
+}
+
+func (m *MockAdminService) Bans(ctx context.Context) ([]Ban, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]Ban), args.Error(1)
+}
+
+type MockScoreService struct {
+	mock.Mock
+}
+
+func (m *MockScoreService) GetTopK(ctx context.Context, k int) ([]model.Score, error) {
+	args := m.Called(ctx, k)
+	return args.Get(0).([]model.Score), args.Error(1)
+}
+
+func (m *MockScoreService) PublishTopScores(ctx context.Context, topScores []model.Score) error {
+	return m.Called(ctx, topScores).Error(0)
+}
+
+type MockTeamService struct {
+	mock.Mock
+}
+
+func (m *MockTeamService) Refresh(ctx context.Context, playerID string) error {
+	return m.Called(ctx, playerID).Error(0)
+}
+
+func (m *MockTeamService) Rebuild(ctx context.Context) error {
+	return m.Called(ctx).Error(0)
+}
+
+func testTopK() int {
+	return 10
+}
+
+func TestHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	topScores := []model.Score{{Name: "Bob", Value: 90}}
+
+	tests := []struct {
+		name               string
+		method             string
+		path               string
+		body               string
+		setupMock          func(service *MockAdminService)
+		setupTeams         func(teams *MockTeamService)
+		expectedStatusCode int
+		expectedRepublish  bool
+	}{
+		{
+			name:   "deletes players",
+			method: http.MethodDelete,
+			path:   "/admin/leaderboard/players/player-1",
+			body:   `{"reason":"cheating"}`,
+			setupMock: func(service *MockAdminService) {
+				service.On("DeletePlayer", mock.Anything, "player-1", "cheating", "admin").Return(nil)
+			},
+			setupTeams: func(teams *MockTeamService) {
+				teams.On("Refresh", mock.Anything, "player-1").Return(nil)
+			},
+			expectedStatusCode: http.StatusNoContent,
+			expectedRepublish:  true,
+		},
+		{
+			name:   "adjusts scores",
+			method: http.MethodPut,
+			path:   "/admin/leaderboard/players/player-1/score",
+			body:   `{"value":0,"reason":"cheating"}`,
+			setupMock: func(service *MockAdminService) {
+				service.On("AdjustScore", mock.Anything, "player-1", 0, "cheating", "admin").Return(nil)
+			},
+			setupTeams: func(teams *MockTeamService) {
+				teams.On("Refresh", mock.Anything, "player-1").Return(errors.New("redis error"))
+			},
+			expectedStatusCode: http.StatusNoContent,
+			expectedRepublish:  true,
+		},
+		{
+			name:               "requires a reason to adjust scores",
+			method:             http.MethodPut,
+			path:               "/admin/leaderboard/players/player-1/score",
+			body:               `{"value":100}`,
+			setupMock:          func(service *MockAdminService) {},
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:   "resets the board without a reason",
+			method: http.MethodPost,
+			path:   "/admin/leaderboard/reset",
+			setupMock: func(service *MockAdminService) {
+				service.On("Reset", mock.Anything, "", "admin").Return(nil)
+			},
+			setupTeams: func(teams *MockTeamService) {
+				teams.On("Rebuild", mock.Anything).Return(nil)
+			},
+			expectedStatusCode: http.StatusNoContent,
+			expectedRepublish:  true,
+		},
+		{
+			name:   "archives the board",
+			method: http.MethodPost,
+			path:   "/admin/leaderboard/archive",
+			body:   `{"reason":"season 1"}`,
+			setupMock: func(service *MockAdminService) {
+				service.On("Archive", mock.Anything, "season 1", "admin").Return("leaderboard:archive:1700000000", nil)
+			},
+			setupTeams: func(teams *MockTeamService) {
+				teams.On("Rebuild", mock.Anything).Return(nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedRepublish:  true,
+		},
+		{
+			name:   "bans players",
+			method: http.MethodPut,
+			path:   "/admin/leaderboard/bans/player-1",
+			body:   `{"reason":"cheating"}`,
+			setupMock: func(service *MockAdminService) {
+				service.On("Ban", mock.Anything, "player-1", "cheating", "admin").Return(nil)
+			},
+			expectedStatusCode: http.StatusNoContent,
+			expectedRepublish:  true,
+		},
+		{
+			name:   "does not republish failed actions",
+			method: http.MethodDelete,
+			path:   "/admin/leaderboard/bans/player-1",
+			setupMock: func(service *MockAdminService) {
+				service.On("Unban", mock.Anything, "player-1", "", "admin").Return(apierror.NotFound("player is not banned"))
+			},
+			expectedStatusCode: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := new(MockAdminService)
+			tt.setupMock(service)
+			scores := new(MockScoreService)
+			scores.On("GetTopK", mock.Anything, 10).Return(topScores, nil).Maybe()
+			scores.On("PublishTopScores", mock.Anything, topScores).Return(nil).Maybe()
+			teams := new(MockTeamService)
+			if tt.setupTeams != nil {
+				tt.setupTeams(teams)
+			}
+
+			router := gin.New()
+			router.Use(middleware.Errors(), func(c *gin.Context) {
+				c.Request = c.Request.WithContext(auth.NewContext(c.Request.Context(), auth.Principal{Subject: "admin"}))
+			})
+			NewHandler(service, scores, teams, testTopK).RegisterRoutes(router)
+
+			req := httptest.NewRequest(tt.method, tt.path, bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			require.Equal(t, tt.expectedStatusCode, w.Code)
+			service.AssertExpectations(t)
+			teams.AssertExpectations(t)
+			if tt.expectedRepublish {
+				scores.AssertCalled(t, "PublishTopScores", mock.Anything, topScores)
+			} else {
+				scores.AssertNotCalled(t, "PublishTopScores", mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
@@ -0,0 +1,181 @@
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"strconv"
+	"time"
+
+	"github.com/iamnotrodger/golang-projects/pkg/apierror"
+	"github.com/iamnotrodger/golang-projects/services/leaderboard/internal/redisutil"
+	"github.com/iamnotrodger/golang-projects/services/leaderboard/internal/score"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	auditStream = score.Board + ":audit"
+	// auditLength is the approximate number of actions kept in the audit stream.
+	auditLength   = 10000
+	archivePrefix = score.Board + ":archive:"
+)
+
+// Actions of the audit stream.
+const (
+	ActionDeletePlayer = "delete_player"
+	ActionAdjustScore  = "adjust_score"
+	ActionReset        = "reset"
+	ActionArchive      = "archive"
+	ActionBan          = "ban"
+	ActionUnban        = "unban"
+)
+
+// action is an audited change of the leaderboard, target is the player or the
+// archive it applies to.
+type action struct {
+	action   string
+	target   string
+	previous *int
+	value    *int
+	reason   string
+	actor    string
+}
+
+type Ban struct {
+	PlayerID string    `json:"player_id"`
+	Reason   string    `json:"reason"`
+	BannedBy string    `json:"banned_by,omitempty"`
+	BannedAt time.Time `json:"banned_at"`
+}
+
+type Service struct {
+	rdb *redis.Client
+	now func() time.Time
+}
+
+func NewService(rdb *redis.Client) *Service {
+	return &Service{rdb: rdb, now: time.Now}
+}
+
+// DeletePlayer removes the entry of the player from the leaderboard.
+func (s *Service) DeletePlayer(ctx context.Context, playerID, reason, actor string) error {
+	removed, err := s.rdb.ZRem(ctx, score.Board, playerID).Result()
+	if err != nil {
+		return redisutil.Error(err)
+	}
+	if removed == 0 {
+		return apierror.NotFound("player not found")
+	}
+	s.audit(ctx, action{action: ActionDeletePlayer, target: playerID, reason: reason, actor: actor})
+	return nil
+}
+
+// AdjustScore sets the score of a player already on the leaderboard.
+func (s *Service) AdjustScore(ctx context.Context, playerID string, value int, reason, actor string) error {
+	previous, err := s.rdb.ZScore(ctx, score.Board, playerID).Result()
+	if errors.Is(err, redis.Nil) {
+		return apierror.NotFound("player not found")
+	}
+	if err != nil {
+		return redisutil.Error(err)
+	}
+
+	if err := s.rdb.ZAddXX(ctx, score.Board, redis.Z{Score: float64(value), Member: playerID}).Err(); err != nil {
+		return redisutil.Error(err)
+	}
+	previousValue := int(previous)
+	s.audit(ctx, action{action: ActionAdjustScore, target: playerID, previous: &previousValue, value: &value, reason: reason, actor: actor})
+	return nil
+}
+
+// Reset removes every entry of the leaderboard.
+func (s *Service) Reset(ctx context.Context, reason, actor string) error {
+	if err := s.rdb.Del(ctx, score.Board).Err(); err != nil {
+		return redisutil.Error(err)
+	}
+	s.audit(ctx, action{action: ActionReset, reason: reason, actor: actor})
+	return nil
+}
+
+// Archive moves the entries of the leaderboard to a new archive key, leaving
+// the leaderboard empty, and returns the key.
+func (s *Service) Archive(ctx context.Context, reason, actor string) (string, error) {
+	archive := archivePrefix + strconv.FormatInt(s.now().Unix(), 10)
+	pipe := s.rdb.TxPipeline()
+	exists := pipe.Exists(ctx, score.Board)
+	moved := pipe.RenameNX(ctx, score.Board, archive)
+	_, err := pipe.Exec(ctx)
+	switch {
+	case exists.Err() == nil && exists.Val() == 0:
+		return "", apierror.NotFound("leaderboard is empty")
+	case err != nil:
+		return "", redisutil.Error(err)
+	case !moved.Val():
+		return "", apierror.Conflict("leaderboard was already archived at "+archive, nil)
+	}
+	s.audit(ctx, action{action: ActionArchive, target: archive, reason: reason, actor: actor})
+	return archive, nil
+}
+
+// Ban rejects the future scores of playerID.
+func (s *Service) Ban(ctx context.Context, playerID, reason, actor string) error {
+	data, err := json.Marshal(Ban{PlayerID: playerID, Reason: reason, BannedBy: actor, BannedAt: s.now().UTC()})
+	if err != nil {
+		return err
+	}
+	if err := s.rdb.HSet(ctx, score.Bans, playerID, data).Err(); err != nil {
+		return redisutil.Error(err)
+	}
+	s.audit(ctx, action{action: ActionBan, target: playerID, reason: reason, actor: actor})
+	return nil
+}
+
+func (s *Service) Unban(ctx context.Context, playerID, reason, actor string) error {
+	removed, err := s.rdb.HDel(ctx, score.Bans, playerID).Result()
+	if err != nil {
+		return redisutil.Error(err)
+	}
+	if removed == 0 {
+		return apierror.NotFound("player is not banned")
+	}
+	s.audit(ctx, action{action: ActionUnban, target: playerID, reason: reason, actor: actor})
+	return nil
+}
+
+func (s *Service) Bans(ctx context.Context) ([]Ban, error) {
+	values, err := s.rdb.HVals(ctx, score.Bans).Result()
+	if err != nil {
+		return nil, redisutil.Error(err)
+	}
+
+	bans := make([]Ban, len(values))
+	for i, value := range values {
+		if err := json.Unmarshal([]byte(value), &bans[i]); err != nil {
+			return nil, err
+		}
+	}
+	return bans, nil
+}
+
+// audit records the action in the audit stream. The action was applied
+// already, a failure is logged rather than reported to the admin.
+func (s *Service) audit(ctx context.Context, a action) {
+	values := []any{"action", a.action, "target", a.target, "reason", a.reason, "actor", a.actor, "at", s.now().UnixMilli()}
+	if a.previous != nil {
+		values = append(values, "previous", *a.previous)
+	}
+	if a.value != nil {
+		values = append(values, "value", *a.value)
+	}
+
+	err := s.rdb.XAdd(ctx, &redis.XAddArgs{
+		Stream: auditStream,
+		MaxLen: auditLength,
+		Approx: true,
+		Values: values,
+	}).Err()
+	if err != nil {
+		slog.ErrorContext(ctx, "error auditing leaderboard action", "action", a.action, "target", a.target, "error", err.Error())
+	}
+}
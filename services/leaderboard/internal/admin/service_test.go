@@ -0,0 +1,177 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-redis/redismock/v9"
+	"github.com/iamnotrodger/golang-projects/pkg/apierror"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/require"
+)
+
+var testNow = time.UnixMilli(1_700_000_000_000)
+
+func newTestService(rdb *redis.Client) *Service {
+	service := NewService(rdb)
+	service.now = func() time.Time { return testNow }
+	return service
+}
+
+func expectAudit(mock redismock.ClientMock, action, target, reason string, values ...any) {
+	values = append([]any{"action", action, "target", target, "reason", reason, "actor", "admin", "at", testNow.UnixMilli()}, values...)
+	mock.ExpectXAdd(&redis.XAddArgs{
+		Stream: "leaderboard:audit",
+		MaxLen: auditLength,
+		Approx: true,
+		Values: values,
+	}).SetVal("1700000000000-0")
+}
+
+func TestService_DeletePlayer(t *testing.T) {
+	tests := []struct {
+		name          string
+		setupMock     func(mock redismock.ClientMock)
+		expectedError error
+	}{
+		{
+			name: "removes the player and audits it",
+			setupMock: func(mock redismock.ClientMock) {
+				mock.ExpectZRem("leaderboard", "player-1").SetVal(1)
+				expectAudit(mock, ActionDeletePlayer, "player-1", "cheating")
+			},
+		},
+		{
+			name: "reports unknown players",
+			setupMock: func(mock redismock.ClientMock) {
+				mock.ExpectZRem("leaderboard", "player-1").SetVal(0)
+			},
+			expectedError: apierror.NotFound("player not found"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rdb, mock := redismock.NewClientMock()
+			tt.setupMock(mock)
+
+			err := newTestService(rdb).DeletePlayer(context.Background(), "player-1", "cheating", "admin")
+
+			require.Equal(t, tt.expectedError, err)
+			require.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestService_AdjustScore(t *testing.T) {
+	tests := []struct {
+		name          string
+		setupMock     func(mock redismock.ClientMock)
+		expectedError error
+	}{
+		{
+			name: "sets the score and audits the previous one",
+			setupMock: func(mock redismock.ClientMock) {
+				mock.ExpectZScore("leaderboard", "player-1").SetVal(9000)
+				mock.ExpectZAddXX("leaderboard", redis.Z{Score: 900, Member: "player-1"}).SetVal(0)
+				expectAudit(mock, ActionAdjustScore, "player-1", "typo", "previous", 9000, "value", 900)
+			},
+		},
+		{
+			name: "reports unknown players",
+			setupMock: func(mock redismock.ClientMock) {
+				mock.ExpectZScore("leaderboard", "player-1").RedisNil()
+			},
+			expectedError: apierror.NotFound("player not found"),
+		},
+		{
+			name: "reports unavailable redis",
+			setupMock: func(mock redismock.ClientMock) {
+				mock.ExpectZScore("leaderboard", "player-1").SetErr(redis.ErrPoolTimeout)
+			},
+			expectedError: apierror.Unavailable("redis", redis.ErrPoolTimeout),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rdb, mock := redismock.NewClientMock()
+			tt.setupMock(mock)
+
+			err := newTestService(rdb).AdjustScore(context.Background(), "player-1", 900, "typo", "admin")
+
+			require.Equal(t, tt.expectedError, err)
+			require.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestService_Archive(t *testing.T) {
+	tests := []struct {
+		name            string
+		setupMock       func(mock redismock.ClientMock)
+		expectedArchive string
+		expectedError   error
+	}{
+		{
+			name: "moves the leaderboard to an archive",
+			setupMock: func(mock redismock.ClientMock) {
+				mock.ExpectTxPipeline()
+				mock.ExpectExists("leaderboard").SetVal(1)
+				mock.ExpectRenameNX("leaderboard", "leaderboard:archive:1700000000").SetVal(true)
+				mock.ExpectTxPipelineExec()
+				expectAudit(mock, ActionArchive, "leaderboard:archive:1700000000", "")
+			},
+			expectedArchive: "leaderboard:archive:1700000000",
+		},
+		{
+			name: "reports empty leaderboards",
+			setupMock: func(mock redismock.ClientMock) {
+				mock.ExpectTxPipeline()
+				mock.ExpectExists("leaderboard").SetVal(0)
+				mock.ExpectRenameNX("leaderboard", "leaderboard:archive:1700000000").SetErr(errors.New("ERR no such key"))
+			},
+			expectedError: apierror.NotFound("leaderboard is empty"),
+		},
+		{
+			name: "reports archives taken in the same second",
+			setupMock: func(mock redismock.ClientMock) {
+				mock.ExpectTxPipeline()
+				mock.ExpectExists("leaderboard").SetVal(1)
+				mock.ExpectRenameNX("leaderboard", "leaderboard:archive:1700000000").SetVal(false)
+				mock.ExpectTxPipelineExec()
+			},
+			expectedError: apierror.Conflict("leaderboard was already archived at leaderboard:archive:1700000000", nil),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rdb, mock := redismock.NewClientMock()
+			tt.setupMock(mock)
+
+			archive, err := newTestService(rdb).Archive(context.Background(), "", "admin")
+
+			require.Equal(t, tt.expectedError, err)
+			require.Equal(t, tt.expectedArchive, archive)
+			require.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestService_Bans(t *testing.T) {
+	rdb, mock := redismock.NewClientMock()
+	mock.ExpectHSet("leaderboard:bans", "player-1", []byte(`{"player_id":"player-1","reason":"cheating","banned_by":"admin","banned_at":"2023-11-14T22:13:20Z"}`)).SetVal(1)
+	expectAudit(mock, ActionBan, "player-1", "cheating")
+	mock.ExpectHVals("leaderboard:bans").SetVal([]string{`{"player_id":"player-1","reason":"cheating","banned_by":"admin","banned_at":"2023-11-14T22:13:20Z"}`})
+	service := newTestService(rdb)
+
+	require.NoError(t, service.Ban(context.Background(), "player-1", "cheating", "admin"))
+	bans, err := service.Bans(context.Background())
+
+	require.NoError(t, err)
+	require.Equal(t, []Ban{{PlayerID: "player-1", Reason: "cheating", BannedBy: "admin", BannedAt: testNow.UTC()}}, bans)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
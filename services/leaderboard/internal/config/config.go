@@ -1,12 +1,23 @@
 package config
 
 import (
-	"fmt"
+	"time"
 
-	"github.com/go-viper/mapstructure/v2"
-	"github.com/spf13/viper"
+	"github.com/iamnotrodger/golang-projects/pkg/auth"
+	pkgconfig "github.com/iamnotrodger/golang-projects/pkg/config"
+	"github.com/iamnotrodger/golang-projects/pkg/middleware"
+	"github.com/iamnotrodger/golang-projects/pkg/ratelimit"
+	"github.com/iamnotrodger/golang-projects/pkg/telemetry"
+	"github.com/iamnotrodger/golang-projects/services/leaderboard/internal/friends"
+	"github.com/iamnotrodger/golang-projects/services/leaderboard/internal/history"
+	"github.com/iamnotrodger/golang-projects/services/leaderboard/internal/integrity"
+	"github.com/iamnotrodger/golang-projects/services/leaderboard/internal/team"
+	"github.com/spf13/pflag"
 )
 
+// ServiceName identifies the leaderboard in traces.
+const ServiceName = "leaderboard"
+
 const (
 	defaultEnv           = "development"
 	defaultPort          = 8080
@@ -14,25 +25,49 @@ const (
 	defaultRedisAddr     = "localhost:6379"
 	defaultRedisDb       = 0
 	defaultRedisPassword = ""
+	defaultTopK          = 10
 )
 
+var defaultSaveScoreLimit = ratelimit.Limit{Requests: 60, Window: time.Minute}
+
 type Secret struct {
 	RedisPassword string `mapstructure:"redis_password"`
+	// ScoreSigningSecret is shared with the game servers signing the scores.
+	ScoreSigningSecret string `mapstructure:"score_signing_secret"`
+}
+
+// RateLimit limits the writes of every client. The memory backend limits every
+// replica on its own while the redis backend shares the limits between them.
+type RateLimit struct {
+	Backend   string          `mapstructure:"backend" validate:"oneof=memory redis"`
+	SaveScore ratelimit.Limit `mapstructure:"save_score"`
 }
 
 type Spec struct {
 	*Secret   `json:"-"`
-	Env       string `mapstructure:"env"`
-	Port      int    `mapstructure:"port"`
-	LogLevel  string `mapstructure:"log_level"`
-	RedisAddr string `mapstructure:"redis_addr"`
-	RedisDb   int    `mapstructure:"redis_db"`
+	Env       string            `mapstructure:"env" validate:"required"`
+	Port      int               `mapstructure:"port" validate:"min=1,max=65535"`
+	LogLevel  string            `mapstructure:"log_level" validate:"oneof=debug info warn error" reload:"true"`
+	RedisAddr string            `mapstructure:"redis_addr" validate:"required,hostname_port"`
+	RedisDb   int               `mapstructure:"redis_db" validate:"min=0,max=15"`
+	TopK      int               `mapstructure:"top_k" validate:"min=1,max=100" reload:"true"`
+	Telemetry telemetry.Config  `mapstructure:"telemetry"`
+	HTTP      middleware.Config `mapstructure:"http"`
+	Auth      auth.Config       `mapstructure:"auth"`
+	RateLimit RateLimit         `mapstructure:"rate_limit"`
+	Integrity integrity.Config  `mapstructure:"integrity"`
+	History   history.Config    `mapstructure:"history"`
+	Teams     team.Config       `mapstructure:"teams"`
+	Friends   friends.Config    `mapstructure:"friends"`
 }
 
 func New() *Spec {
 	secret := &Secret{
 		RedisPassword: defaultRedisPassword,
 	}
+	http := middleware.NewConfig()
+	// The leaderboard stream is public, it may be embedded by any page.
+	http.CORS.AllowedOrigins = []string{"*"}
 	return &Spec{
 		Secret:    secret,
 		Env:       defaultEnv,
@@ -40,34 +75,33 @@ func New() *Spec {
 		LogLevel:  defaultLogLevel,
 		RedisAddr: defaultRedisAddr,
 		RedisDb:   defaultRedisDb,
+		TopK:      defaultTopK,
+		Telemetry: telemetry.NewConfig(),
+		HTTP:      http,
+		Auth:      auth.NewConfig(),
+		RateLimit: RateLimit{
+			Backend:   ratelimit.BackendMemory,
+			SaveScore: defaultSaveScoreLimit,
+		},
+		Integrity: integrity.NewConfig(),
+		History:   history.NewConfig(),
+		Teams:     team.NewConfig(),
+		Friends:   friends.NewConfig(),
 	}
 }
 
+// Global is the configuration loaded at startup, it is not updated on reload.
+// The settings tagged with reload:"true" are read from the loader.
 var Global = New()
 
-func LoadConfig(spec *Spec) {
-	v := viper.New()
-	v.SetConfigFile(".env")
-	v.ReadInConfig()
-	v.AutomaticEnv()
-
-	setDefaults(v, spec)
-
-	if err := v.Unmarshal(spec); err != nil {
-		panic(fmt.Errorf("fatal error unmarshalling config %s", err))
-	}
-}
-
-func setDefaults(v *viper.Viper, i any) {
-	values := map[string]any{}
-	if err := mapstructure.Decode(i, &values); err != nil {
-		panic(err)
-	}
-	for key, defaultValue := range values {
-		v.SetDefault(key, defaultValue)
-	}
+// NewLoader creates the loader of the configuration from its defaults, the YAML
+// file, .env, the environment and args. flags are parsed along with the
+// configuration flags. Settings tagged with reload:"true" can change at runtime.
+func NewLoader(args []string, flags *pflag.FlagSet) *pkgconfig.Loader[*Spec] {
+	return pkgconfig.NewLoader(New, pkgconfig.Options{Args: args, FlagSet: flags})
 }
 
-func init() {
-	LoadConfig(Global)
+// Load reads and validates the configuration.
+func Load(args []string, flags *pflag.FlagSet) (*Spec, error) {
+	return NewLoader(args, flags).Load()
 }
@@ -2,8 +2,13 @@ package config
 
 import (
 	"os"
+	"path/filepath"
 	"testing"
 
+	"github.com/iamnotrodger/golang-projects/services/leaderboard/internal/friends"
+	"github.com/iamnotrodger/golang-projects/services/leaderboard/internal/history"
+	"github.com/iamnotrodger/golang-projects/services/leaderboard/internal/integrity"
+	"github.com/iamnotrodger/golang-projects/services/leaderboard/internal/team"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -14,24 +19,22 @@ func TestGlobal(t *testing.T) {
 	assert.Equal(t, Global.RedisAddr, defaultRedisAddr)
 	assert.Equal(t, Global.RedisDb, defaultRedisDb)
 	assert.Equal(t, Global.RedisPassword, defaultRedisPassword)
+	assert.Equal(t, Global.TopK, defaultTopK)
+	assert.Equal(t, Global.HTTP.CORS.AllowedOrigins, []string{"*"})
+	assert.Equal(t, Global.RateLimit.Backend, "memory")
+	assert.Equal(t, Global.RateLimit.SaveScore, defaultSaveScoreLimit)
+	assert.Equal(t, Global.Integrity, integrity.NewConfig())
+	assert.Equal(t, Global.History, history.NewConfig())
+	assert.Equal(t, Global.Teams, team.NewConfig())
+	assert.Equal(t, Global.Friends, friends.NewConfig())
 }
 
-func TestLoadConfig(t *testing.T) {
-	testConfig := New()
-
-	originalPort := os.Getenv("PORT")
-	originalLogLevel := os.Getenv("LOG_LEVEL")
-
-	defer func() {
-		os.Setenv("PORT", originalPort)
-		os.Setenv("LOG_LEVEL", originalLogLevel)
-	}()
-
+func TestLoad(t *testing.T) {
 	envLogLevel := "debug"
-	os.Setenv("LOG_LEVEL", envLogLevel)
-	os.Unsetenv("PORT")
+	t.Setenv("LOG_LEVEL", envLogLevel)
 
-	LoadConfig(testConfig)
+	testConfig, err := Load([]string{"--env", "test"}, nil)
+	assert.NoError(t, err)
 
 	cases := []struct {
 		name        string
@@ -48,6 +51,11 @@ func TestLoadConfig(t *testing.T) {
 			configValue: testConfig.Port,
 			expected:    defaultPort,
 		},
+		{
+			name:        "uses flag value when defined",
+			configValue: testConfig.Env,
+			expected:    "test",
+		},
 	}
 
 	for _, tc := range cases {
@@ -56,3 +64,22 @@ func TestLoadConfig(t *testing.T) {
 		})
 	}
 }
+
+func TestLoad_SecretFile(t *testing.T) {
+	secretFile := filepath.Join(t.TempDir(), "redis_password")
+	assert.NoError(t, os.WriteFile(secretFile, []byte("secret\n"), 0o600))
+	t.Setenv("REDIS_PASSWORD_FILE", secretFile)
+
+	testConfig, err := Load(nil, nil)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "secret", testConfig.RedisPassword)
+}
+
+func TestLoad_Invalid(t *testing.T) {
+	t.Setenv("REDIS_DB", "16")
+
+	_, err := Load(nil, nil)
+
+	assert.EqualError(t, err, "invalid configuration:\n  redis_db: must be at most 15, got 16")
+}
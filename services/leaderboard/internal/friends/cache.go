@@ -0,0 +1,54 @@
+package friends
+
+import (
+	"sync"
+	"time"
+)
+
+// maxCacheEntries bounds the cache, expired entries are dropped when it is reached.
+const maxCacheEntries = 10000
+
+type cacheEntry struct {
+	entries []Entry
+	expires time.Time
+}
+
+// cache keeps the ranked subsets for a short time, the SSE streams of the
+// same subset share them.
+type cache struct {
+	now     func() time.Time
+	mu      sync.Mutex
+	entries map[string]cacheEntry
+}
+
+func newCache() *cache {
+	return &cache{now: time.Now, entries: map[string]cacheEntry{}}
+}
+
+func (c *cache) get(key string) ([]Entry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[key]
+	if !ok || !c.now().Before(entry.expires) {
+		return nil, false
+	}
+	return entry.entries, true
+}
+
+func (c *cache) set(key string, entries []Entry, ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+	if len(c.entries) >= maxCacheEntries {
+		for key, entry := range c.entries {
+			if !now.Before(entry.expires) {
+				delete(c.entries, key)
+			}
+		}
+	}
+	if len(c.entries) < maxCacheEntries {
+		c.entries[key] = cacheEntry{entries: entries, expires: now.Add(ttl)}
+	}
+}
@@ -0,0 +1,17 @@
+package friends
+
+import "time"
+
+type Config struct {
+	// CacheTTL is how long a ranked subset is reused, zero disables the cache.
+	CacheTTL time.Duration `mapstructure:"cache_ttl" validate:"min=0" reload:"true"`
+	// MaxPlayers bounds the players compared at once, friend lists included.
+	MaxPlayers int `mapstructure:"max_players" validate:"min=1,max=1000" reload:"true"`
+}
+
+func NewConfig() Config {
+	return Config{
+		CacheTTL:   2 * time.Second,
+		MaxPlayers: 100,
+	}
+}
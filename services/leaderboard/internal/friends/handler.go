@@ -0,0 +1,216 @@
+package friends
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/iamnotrodger/golang-projects/pkg/apierror"
+	"github.com/iamnotrodger/golang-projects/pkg/auth"
+	"github.com/iamnotrodger/golang-projects/services/leaderboard/internal/model"
+)
+
+const (
+	// ScopeFriendsWrite allows players to store their own friend list.
+	ScopeFriendsWrite = "friends:write"
+	// ScopeFriendsWriteAny allows storing the friend list of any player.
+	ScopeFriendsWriteAny = "friends:write:any"
+)
+
+const keepaliveInterval = 30 * time.Second
+
+type friendsService interface {
+	Leaderboard(ctx context.Context, ids []string) ([]Entry, error)
+	FriendsLeaderboard(ctx context.Context, id string) ([]Entry, error)
+	Friends(ctx context.Context, id string) ([]string, error)
+	SetFriends(ctx context.Context, id string, friends []string) error
+}
+
+// scoresHub tells the streams that the leaderboard changed.
+type scoresHub interface {
+	RegisterClient(id string) chan []model.Score
+	UnregisterClient(id string)
+}
+
+type friendsRequest struct {
+	Friends []string `json:"friends"`
+}
+
+type Handler struct {
+	service friendsService
+	hub     scoresHub
+}
+
+func NewHandler(service friendsService, hub scoresHub) *Handler {
+	return &Handler{service: service, hub: hub}
+}
+
+// RegisterRoutes registers the routes ranking the subsets given by their IDs,
+// or the players with their stored friends.
+func (h *Handler) RegisterRoutes(r gin.IRouter) {
+	r.GET("/leaderboard/friends", h.getLeaderboard)
+	r.GET("/leaderboard/friends/stream", h.streamLeaderboard)
+	r.GET("/leaderboard/players/:id/friends", h.getFriends)
+	r.GET("/leaderboard/players/:id/friends/leaderboard", h.getFriendsLeaderboard)
+	r.GET("/leaderboard/players/:id/friends/stream", h.streamFriendsLeaderboard)
+}
+
+// RegisterWriteRoutes registers the routes storing the friend lists.
+func (h *Handler) RegisterWriteRoutes(r gin.IRouter) {
+	r.PUT("/leaderboard/players/:id/friends", h.setFriends)
+}
+
+func (h *Handler) getLeaderboard(c *gin.Context) {
+	ids, ok := queryIDs(c)
+	if !ok {
+		return
+	}
+	h.respond(c, func(ctx context.Context) ([]Entry, error) {
+		return h.service.Leaderboard(ctx, ids)
+	})
+}
+
+func (h *Handler) getFriendsLeaderboard(c *gin.Context) {
+	id := c.Param("id")
+	h.respond(c, func(ctx context.Context) ([]Entry, error) {
+		return h.service.FriendsLeaderboard(ctx, id)
+	})
+}
+
+func (h *Handler) streamLeaderboard(c *gin.Context) {
+	ids, ok := queryIDs(c)
+	if !ok {
+		return
+	}
+	h.stream(c, func(ctx context.Context) ([]Entry, error) {
+		return h.service.Leaderboard(ctx, ids)
+	})
+}
+
+func (h *Handler) streamFriendsLeaderboard(c *gin.Context) {
+	id := c.Param("id")
+	h.stream(c, func(ctx context.Context) ([]Entry, error) {
+		return h.service.FriendsLeaderboard(ctx, id)
+	})
+}
+
+func (h *Handler) getFriends(c *gin.Context) {
+	friends, err := h.service.Friends(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, friendsRequest{Friends: friends})
+}
+
+func (h *Handler) setFriends(c *gin.Context) {
+	id := c.Param("id")
+	if principal, ok := auth.FromContext(c.Request.Context()); ok && principal.Subject != id && !principal.HasScope(ScopeFriendsWriteAny) {
+		c.Error(apierror.Forbidden("players may only store their own friends", nil))
+		return
+	}
+
+	var request friendsRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.Error(apierror.Validation("invalid friend list", err))
+		return
+	}
+	if err := h.service.SetFriends(c.Request.Context(), id, request.Friends); err != nil {
+		c.Error(err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func (h *Handler) respond(c *gin.Context, leaderboard func(ctx context.Context) ([]Entry, error)) {
+	entries, err := leaderboard(c.Request.Context())
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"players": entries})
+}
+
+// stream sends the ranked subset whenever it changes. Every update of the
+// leaderboard is a signal to rank the subset again, the cache keeps the
+// streams of the same subset from ranking it each.
+func (h *Handler) stream(c *gin.Context, leaderboard func(ctx context.Context) ([]Entry, error)) {
+	ctx := c.Request.Context()
+	entries, err := leaderboard(ctx)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.Writer.Header().Set("Content-Type", "text/event-stream")
+	c.Writer.Header().Set("Cache-Control", "no-cache")
+	c.Writer.Header().Set("Connection", "keep-alive")
+
+	id := "friends:" + c.Request.RemoteAddr
+	updates := h.hub.RegisterClient(id)
+	defer h.hub.UnregisterClient(id)
+
+	writeEntries(c.Writer, entries)
+
+	ticker := time.NewTicker(keepaliveInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			writeSSE(c.Writer, "keepalive:\n\n")
+		case _, ok := <-updates:
+			if !ok {
+				return
+			}
+			latest, err := leaderboard(ctx)
+			if err != nil {
+				slog.ErrorContext(ctx, "error ranking friends", "error", err.Error())
+				continue
+			}
+			if !slices.Equal(latest, entries) {
+				entries = latest
+				writeEntries(c.Writer, entries)
+			}
+		}
+	}
+}
+
+// queryIDs reads the comma separated player IDs of the ids query.
+func queryIDs(c *gin.Context) ([]string, bool) {
+	var ids []string
+	for _, id := range strings.Split(c.Query("ids"), ",") {
+		if id = strings.TrimSpace(id); id != "" {
+			ids = append(ids, id)
+		}
+	}
+	if len(ids) == 0 {
+		c.Error(apierror.Validation("ids must list player IDs", nil))
+		return nil, false
+	}
+	return ids, true
+}
+
+func writeEntries(w http.ResponseWriter, entries []Entry) {
+	data, err := json.Marshal(entries)
+	if err != nil {
+		slog.Error("error marshaling friends", "error", err)
+		return
+	}
+	writeSSE(w, fmt.Sprintf("data: %s\n\n", data))
+}
+
+func writeSSE(w http.ResponseWriter, message string) {
+	fmt.Fprint(w, message)
+	if flusher, ok := w.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
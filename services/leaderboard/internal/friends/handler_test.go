@@ -0,0 +1,175 @@
+package friends
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/iamnotrodger/golang-projects/pkg/auth"
+	"github.com/iamnotrodger/golang-projects/pkg/middleware"
+	"github.com/iamnotrodger/golang-projects/services/leaderboard/internal/leaderboard"
+	"github.com/iamnotrodger/golang-projects/services/leaderboard/internal/model"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockFriendsService struct {
+	mock.Mock
+}
+
+func (m *MockFriendsService) Leaderboard(ctx context.Context, ids []string) ([]Entry, error) {
+	args := m.Called(ctx, ids)
+	entries, _ := args.Get(0).([]Entry)
+	return entries, args.Error(1)
+}
+
+func (m *MockFriendsService) FriendsLeaderboard(ctx context.Context, id string) ([]Entry, error) {
+	args := m.Called(ctx, id)
+	entries, _ := args.Get(0).([]Entry)
+	return entries, args.Error(1)
+}
+
+func (m *MockFriendsService) Friends(ctx context.Context, id string) ([]string, error) {
+	args := m.Called(ctx, id)
+	friends, _ := args.Get(0).([]string)
+	return friends, args.Error(1)
+}
+
+func (m *MockFriendsService) SetFriends(ctx context.Context, id string, friends []string) error {
+	return m.Called(ctx, id, friends).Error(0)
+}
+
+func setupTestRouter(service friendsService, hub scoresHub, principal *auth.Principal) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(middleware.Errors(), func(c *gin.Context) {
+		if principal != nil {
+			c.Request = c.Request.WithContext(auth.NewContext(c.Request.Context(), *principal))
+		}
+	})
+	handler := NewHandler(service, hub)
+	handler.RegisterRoutes(router)
+	handler.RegisterWriteRoutes(router)
+	return router
+}
+
+func TestHandler(t *testing.T) {
+	entries := []Entry{{ID: "bob", Name: "Bob", Value: 300, Rank: 1, GlobalRank: 7}}
+
+	tests := []struct {
+		name               string
+		method             string
+		path               string
+		body               string
+		principal          *auth.Principal
+		setupMock          func(service *MockFriendsService)
+		expectedStatusCode int
+		expectedBody       string
+	}{
+		{
+			name:   "ranks the listed players",
+			method: http.MethodGet,
+			path:   "/leaderboard/friends?ids=bob,%20alice,",
+			setupMock: func(service *MockFriendsService) {
+				service.On("Leaderboard", mock.Anything, []string{"bob", "alice"}).Return(entries, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedBody:       `{"players":[{"id":"bob","name":"Bob","value":300,"rank":1,"global_rank":7}]}`,
+		},
+		{
+			name:               "requires player IDs",
+			method:             http.MethodGet,
+			path:               "/leaderboard/friends?ids=,",
+			setupMock:          func(service *MockFriendsService) {},
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:   "ranks the stored friends",
+			method: http.MethodGet,
+			path:   "/leaderboard/players/alice/friends/leaderboard",
+			setupMock: func(service *MockFriendsService) {
+				service.On("FriendsLeaderboard", mock.Anything, "alice").Return(entries, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name:      "stores the friends of the player",
+			method:    http.MethodPut,
+			path:      "/leaderboard/players/alice/friends",
+			body:      `{"friends":["bob"]}`,
+			principal: &auth.Principal{Subject: "alice"},
+			setupMock: func(service *MockFriendsService) {
+				service.On("SetFriends", mock.Anything, "alice", []string{"bob"}).Return(nil)
+			},
+			expectedStatusCode: http.StatusNoContent,
+		},
+		{
+			name:               "players cannot store the friends of others",
+			method:             http.MethodPut,
+			path:               "/leaderboard/players/alice/friends",
+			body:               `{"friends":["bob"]}`,
+			principal:          &auth.Principal{Subject: "bob"},
+			setupMock:          func(service *MockFriendsService) {},
+			expectedStatusCode: http.StatusForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := new(MockFriendsService)
+			tt.setupMock(service)
+			router := setupTestRouter(service, leaderboard.NewHub(), tt.principal)
+
+			req := httptest.NewRequest(tt.method, tt.path, bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			require.Equal(t, tt.expectedStatusCode, w.Code)
+			if tt.expectedBody != "" {
+				require.JSONEq(t, tt.expectedBody, w.Body.String())
+			}
+			service.AssertExpectations(t)
+		})
+	}
+}
+
+func TestHandler_Stream(t *testing.T) {
+	first := []Entry{{ID: "bob", Name: "Bob", Value: 300, Rank: 1, GlobalRank: 7}}
+	second := []Entry{{ID: "alice", Name: "Alice", Value: 500, Rank: 1, GlobalRank: 3}, {ID: "bob", Name: "Bob", Value: 300, Rank: 2, GlobalRank: 8}}
+	service := new(MockFriendsService)
+	service.On("Leaderboard", mock.Anything, []string{"alice", "bob"}).Return(first, nil).Twice()
+	service.On("Leaderboard", mock.Anything, []string{"alice", "bob"}).Return(second, nil)
+	hub := leaderboard.NewHub()
+	server := httptest.NewServer(setupTestRouter(service, hub, nil))
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"/leaderboard/friends/stream?ids=alice,bob", nil)
+	require.NoError(t, err)
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	events := bufio.NewScanner(resp.Body)
+
+	next := func() string {
+		for events.Scan() {
+			if line, ok := strings.CutPrefix(events.Text(), "data: "); ok {
+				return line
+			}
+		}
+		return ""
+	}
+
+	require.JSONEq(t, `[{"id":"bob","name":"Bob","value":300,"rank":1,"global_rank":7}]`, next())
+	// The first update leaves the subset unchanged, only the second one is sent.
+	hub.Broadcast([]model.Score{})
+	hub.Broadcast([]model.Score{})
+	require.JSONEq(t, `[{"id":"alice","name":"Alice","value":500,"rank":1,"global_rank":3},{"id":"bob","name":"Bob","value":300,"rank":2,"global_rank":8}]`, next())
+}
@@ -0,0 +1,145 @@
+package friends
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/iamnotrodger/golang-projects/pkg/apierror"
+	"github.com/iamnotrodger/golang-projects/services/leaderboard/internal/redisutil"
+	"github.com/iamnotrodger/golang-projects/services/leaderboard/internal/score"
+	"github.com/redis/go-redis/v9"
+)
+
+// listPrefix starts the keys of the stored friend lists.
+const listPrefix = score.Board + ":friends:"
+
+// Entry is a player ranked among a subset of players, Rank is their position
+// in the subset and GlobalRank on the whole leaderboard, both starting at 1.
+type Entry struct {
+	ID         string `json:"id"`
+	Name       string `json:"name"`
+	Value      int    `json:"value"`
+	Rank       int    `json:"rank"`
+	GlobalRank int64  `json:"global_rank"`
+}
+
+type Service struct {
+	rdb    *redis.Client
+	cache  *cache
+	config func() Config
+}
+
+// NewService creates the friends service, config is read on every request.
+func NewService(rdb *redis.Client, config func() Config) *Service {
+	return &Service{rdb: rdb, cache: newCache(), config: config}
+}
+
+// Leaderboard ranks the players among themselves, players without a score
+// are left out.
+func (s *Service) Leaderboard(ctx context.Context, ids []string) ([]Entry, error) {
+	config := s.config()
+	ids = slices.Compact(slices.Sorted(slices.Values(ids)))
+	if len(ids) > config.MaxPlayers {
+		return nil, apierror.Validation("at most "+strconv.Itoa(config.MaxPlayers)+" players can be compared", nil)
+	}
+
+	key := strings.Join(ids, ",")
+	if entries, ok := s.cache.get(key); ok {
+		return entries, nil
+	}
+
+	entries, err := s.rank(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	if config.CacheTTL > 0 {
+		s.cache.set(key, entries, config.CacheTTL)
+	}
+	return entries, nil
+}
+
+// FriendsLeaderboard ranks the player among their stored friends.
+func (s *Service) FriendsLeaderboard(ctx context.Context, id string) ([]Entry, error) {
+	friends, err := s.Friends(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return s.Leaderboard(ctx, append(friends, id))
+}
+
+func (s *Service) Friends(ctx context.Context, id string) ([]string, error) {
+	friends, err := s.rdb.SMembers(ctx, listPrefix+id).Result()
+	if err != nil {
+		return nil, redisutil.Error(err)
+	}
+	slices.Sort(friends)
+	return friends, nil
+}
+
+// SetFriends replaces the friend list of the player.
+func (s *Service) SetFriends(ctx context.Context, id string, friends []string) error {
+	if limit := s.config().MaxPlayers - 1; len(friends) > limit {
+		return apierror.Validation("at most "+strconv.Itoa(limit)+" friends can be stored", nil)
+	}
+
+	_, err := s.rdb.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Del(ctx, listPrefix+id)
+		if len(friends) > 0 {
+			pipe.SAdd(ctx, listPrefix+id, toAny(friends)...)
+		}
+		return nil
+	})
+	return redisutil.Error(err)
+}
+
+// rank reads the scores of the players with a single ZMSCORE, their global
+// ranks and their names in the same round trip.
+func (s *Service) rank(ctx context.Context, ids []string) ([]Entry, error) {
+	if len(ids) == 0 {
+		return []Entry{}, nil
+	}
+
+	pipe := s.rdb.Pipeline()
+	names := pipe.HMGet(ctx, score.Names, ids...)
+	values := pipe.ZMScore(ctx, score.Board, ids...)
+	ranks := make([]*redis.IntCmd, len(ids))
+	for i, id := range ids {
+		ranks[i] = pipe.ZRevRank(ctx, score.Board, id)
+	}
+	if _, err := pipe.Exec(ctx); err != nil && !errors.Is(err, redis.Nil) {
+		return nil, redisutil.Error(err)
+	}
+
+	ranked := make([]Entry, 0, len(ids))
+	for i, id := range ids {
+		rank, err := ranks[i].Result()
+		if errors.Is(err, redis.Nil) {
+			continue
+		}
+		name, _ := names.Val()[i].(string)
+		if name == "" {
+			name = id
+		}
+		ranked = append(ranked, Entry{ID: id, Name: name, Value: int(values.Val()[i]), GlobalRank: rank + 1})
+	}
+
+	slices.SortFunc(ranked, func(a, b Entry) int {
+		return cmp.Compare(a.GlobalRank, b.GlobalRank)
+	})
+	for i := range ranked {
+		ranked[i].Rank = i + 1
+	}
+	return ranked, nil
+}
+
+func toAny(values []string) []any {
+	result := make([]any, len(values))
+	for i, value := range values {
+		result[i] = value
+	}
+	return result
+}
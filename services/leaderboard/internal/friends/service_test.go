@@ -0,0 +1,75 @@
+package friends
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-redis/redismock/v9"
+	"github.com/iamnotrodger/golang-projects/pkg/apierror"
+	"github.com/stretchr/testify/require"
+)
+
+func testConfig() Config {
+	return Config{CacheTTL: time.Minute, MaxPlayers: 3}
+}
+
+func TestService_Leaderboard(t *testing.T) {
+	rdb, mock := redismock.NewClientMock()
+	mock.ExpectHMGet("leaderboard:names", "alice", "bob", "carol").SetVal([]any{"Alice", "Bob", "Carol"})
+	mock.ExpectZMScore("leaderboard", "alice", "bob", "carol").SetVal([]float64{100, 300, 0})
+	mock.ExpectZRevRank("leaderboard", "alice").SetVal(41)
+	mock.ExpectZRevRank("leaderboard", "bob").SetVal(6)
+	mock.ExpectZRevRank("leaderboard", "carol").RedisNil()
+	service := NewService(rdb, testConfig)
+
+	entries, err := service.Leaderboard(context.Background(), []string{"carol", "bob", "alice", "bob"})
+
+	require.NoError(t, err)
+	expected := []Entry{
+		{ID: "bob", Name: "Bob", Value: 300, Rank: 1, GlobalRank: 7},
+		{ID: "alice", Name: "Alice", Value: 100, Rank: 2, GlobalRank: 42},
+	}
+	require.Equal(t, expected, entries)
+
+	cached, err := service.Leaderboard(context.Background(), []string{"alice", "bob", "carol"})
+	require.NoError(t, err)
+	require.Equal(t, expected, cached, "the same subset is served from the cache")
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestService_Leaderboard_TooManyPlayers(t *testing.T) {
+	rdb, _ := redismock.NewClientMock()
+
+	_, err := NewService(rdb, testConfig).Leaderboard(context.Background(), []string{"a", "b", "c", "d"})
+
+	require.Equal(t, apierror.CodeValidation, apierror.From(err).Code)
+}
+
+func TestService_FriendsLeaderboard(t *testing.T) {
+	rdb, mock := redismock.NewClientMock()
+	mock.ExpectSMembers("leaderboard:friends:alice").SetVal([]string{"bob"})
+	mock.ExpectHMGet("leaderboard:names", "alice", "bob").SetVal([]any{"Alice", nil})
+	mock.ExpectZMScore("leaderboard", "alice", "bob").SetVal([]float64{100, 0})
+	mock.ExpectZRevRank("leaderboard", "alice").SetVal(0)
+	mock.ExpectZRevRank("leaderboard", "bob").RedisNil()
+
+	entries, err := NewService(rdb, testConfig).FriendsLeaderboard(context.Background(), "alice")
+
+	require.NoError(t, err)
+	require.Equal(t, []Entry{{ID: "alice", Name: "Alice", Value: 100, Rank: 1, GlobalRank: 1}}, entries)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestService_SetFriends(t *testing.T) {
+	rdb, mock := redismock.NewClientMock()
+	mock.ExpectTxPipeline()
+	mock.ExpectDel("leaderboard:friends:alice").SetVal(1)
+	mock.ExpectSAdd("leaderboard:friends:alice", "bob", "carol").SetVal(2)
+	mock.ExpectTxPipelineExec()
+	service := NewService(rdb, testConfig)
+
+	require.NoError(t, service.SetFriends(context.Background(), "alice", []string{"bob", "carol"}))
+	require.Error(t, service.SetFriends(context.Background(), "alice", []string{"bob", "carol", "dave"}))
+	require.NoError(t, mock.ExpectationsWereMet())
+}
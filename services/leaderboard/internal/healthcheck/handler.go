@@ -2,12 +2,15 @@ package healthcheck
 
 import (
 	"context"
+	"net/http"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
+	"github.com/iamnotrodger/golang-projects/pkg/health"
 )
 
 type Service interface {
-	Ping(ctx context.Context) error
+	Check(ctx context.Context) health.Report
 }
 
 type Handler struct {
@@ -21,13 +24,24 @@ func NewHandler(service Service) *Handler {
 }
 
 func (h *Handler) RegisterRoutes(r *gin.Engine) {
-	r.Match([]string{"GET", "HEAD"}, "/health", h.Ping)
+	r.Match([]string{"GET", "HEAD"}, "/health", h.Health)
+	r.Match([]string{"GET", "HEAD"}, "/livez", h.Live)
+	r.Match([]string{"GET", "HEAD"}, "/readyz", h.Ready)
 }
 
-func (h *Handler) Ping(ctx *gin.Context) {
-	if err := h.service.Ping(ctx); err != nil {
-		ctx.JSON(500, gin.H{"status": "unhealthy"})
-		return
+func (h *Handler) Live(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, health.Report{Status: health.StatusHealthy})
+}
+
+func (h *Handler) Ready(ctx *gin.Context) {
+	report := h.service.Check(ctx)
+	ctx.JSON(report.HTTPStatus(), health.Report{Status: report.Status})
+}
+
+func (h *Handler) Health(ctx *gin.Context) {
+	report := h.service.Check(ctx)
+	if verbose, _ := strconv.ParseBool(ctx.Query("verbose")); !verbose {
+		report.Checks = nil
 	}
-	ctx.JSON(200, gin.H{"status": "healthy"})
+	ctx.JSON(report.HTTPStatus(), report)
 }
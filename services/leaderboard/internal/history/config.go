@@ -0,0 +1,18 @@
+package history
+
+import "time"
+
+// Config is the retention of the history of every player.
+type Config struct {
+	MaxEntries int64 `mapstructure:"max_entries" validate:"min=1" reload:"true"`
+	// MaxAge drops the history of the players who did not submit a score for
+	// that long, zero keeps it.
+	MaxAge time.Duration `mapstructure:"max_age" validate:"min=0" reload:"true"`
+}
+
+func NewConfig() Config {
+	return Config{
+		MaxEntries: 100,
+		MaxAge:     30 * 24 * time.Hour,
+	}
+}
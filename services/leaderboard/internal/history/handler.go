@@ -0,0 +1,80 @@
+package history
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type historyService interface {
+	History(ctx context.Context, playerID string) ([]Entry, error)
+	Rank(ctx context.Context, playerID string) (int64, error)
+}
+
+type historyResponse struct {
+	PlayerID string  `json:"player_id"`
+	Entries  []Entry `json:"entries"`
+	Stats
+}
+
+type rankResponse struct {
+	PlayerID string `json:"player_id"`
+	Name     string `json:"name"`
+	// Rank is the current rank, zero when the player was removed from the leaderboard.
+	Rank int64 `json:"rank"`
+	Stats
+}
+
+type Handler struct {
+	service historyService
+}
+
+func NewHandler(service historyService) *Handler {
+	return &Handler{service: service}
+}
+
+func (h *Handler) RegisterRoutes(r gin.IRouter) {
+	players := r.Group("/leaderboard/players/:id")
+	{
+		players.GET("/history", h.getHistory)
+		players.GET("/rank", h.getRank)
+	}
+}
+
+func (h *Handler) getHistory(c *gin.Context) {
+	playerID := c.Param("id")
+	entries, err := h.service.History(c.Request.Context(), playerID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, historyResponse{
+		PlayerID: playerID,
+		Entries:  entries,
+		Stats:    NewStats(entries),
+	})
+}
+
+func (h *Handler) getRank(c *gin.Context) {
+	playerID := c.Param("id")
+	entries, err := h.service.History(c.Request.Context(), playerID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	rank, err := h.service.Rank(c.Request.Context(), playerID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, rankResponse{
+		PlayerID: playerID,
+		Name:     entries[len(entries)-1].Name,
+		Rank:     rank,
+		Stats:    NewStats(entries),
+	})
+}
@@ -0,0 +1,101 @@
+package history
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/iamnotrodger/golang-projects/pkg/apierror"
+	"github.com/iamnotrodger/golang-projects/pkg/middleware"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockHistoryService struct {
+	mock.Mock
+}
+
+func (m *MockHistoryService) History(ctx context.Context, playerID string) ([]Entry, error) {
+	args := m.Called(ctx, playerID)
+	entries, _ := args.Get(0).([]Entry)
+	return entries, args.Error(1)
+}
+
+func (m *MockHistoryService) Rank(ctx context.Context, playerID string) (int64, error) {
+	args := m.Called(ctx, playerID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func TestHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	entries := []Entry{
+		{Name: "Alice", Value: 100, Rank: 3},
+		{Name: "Alice", Value: 300, Rank: 2},
+		{Name: "Alicia", Value: 200, Rank: 1},
+	}
+
+	tests := []struct {
+		name               string
+		path               string
+		setupMock          func(service *MockHistoryService)
+		expectedStatusCode int
+		expectedBody       map[string]any
+	}{
+		{
+			name: "returns the history and stats",
+			path: "/leaderboard/players/player-1/history",
+			setupMock: func(service *MockHistoryService) {
+				service.On("History", mock.Anything, "player-1").Return(entries, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedBody: map[string]any{
+				"player_id": "player-1", "count": 3.0, "best": 300.0, "average": 200.0, "best_rank": 1.0, "rank_at_best": 2.0,
+			},
+		},
+		{
+			name: "returns the current rank and the best ones",
+			path: "/leaderboard/players/player-1/rank",
+			setupMock: func(service *MockHistoryService) {
+				service.On("History", mock.Anything, "player-1").Return(entries, nil)
+				service.On("Rank", mock.Anything, "player-1").Return(int64(4), nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedBody: map[string]any{
+				"player_id": "player-1", "name": "Alicia", "rank": 4.0, "count": 3.0, "best": 300.0, "average": 200.0, "best_rank": 1.0, "rank_at_best": 2.0,
+			},
+		},
+		{
+			name: "reports unknown players",
+			path: "/leaderboard/players/player-2/rank",
+			setupMock: func(service *MockHistoryService) {
+				service.On("History", mock.Anything, "player-2").Return(nil, apierror.NotFound("player not found"))
+			},
+			expectedStatusCode: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := new(MockHistoryService)
+			tt.setupMock(service)
+			router := gin.New()
+			router.Use(middleware.Errors())
+			NewHandler(service).RegisterRoutes(router)
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+			require.Equal(t, tt.expectedStatusCode, w.Code)
+			if tt.expectedBody != nil {
+				body := map[string]any{}
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+				delete(body, "entries")
+				require.Equal(t, tt.expectedBody, body)
+			}
+			service.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,143 @@
+package history
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/iamnotrodger/golang-projects/pkg/apierror"
+	"github.com/iamnotrodger/golang-projects/services/leaderboard/internal/model"
+	"github.com/iamnotrodger/golang-projects/services/leaderboard/internal/redisutil"
+	"github.com/iamnotrodger/golang-projects/services/leaderboard/internal/score"
+	"github.com/redis/go-redis/v9"
+)
+
+const keyPrefix = score.Board + ":history:"
+
+// Entry is an accepted submission with the rank of the player right after it,
+// ranks start at 1.
+type Entry struct {
+	Name  string    `json:"name"`
+	Value int       `json:"value"`
+	Rank  int64     `json:"rank"`
+	At    time.Time `json:"at"`
+}
+
+// Stats summarize the retained history of a player.
+type Stats struct {
+	Count   int     `json:"count"`
+	Best    int     `json:"best"`
+	Average float64 `json:"average"`
+	// BestRank is the best rank achieved and RankAtBest the rank right after
+	// the best score was submitted.
+	BestRank   int64 `json:"best_rank"`
+	RankAtBest int64 `json:"rank_at_best"`
+}
+
+type Service struct {
+	rdb    *redis.Client
+	config func() Config
+}
+
+// NewService creates the history service, config is read on every submission.
+func NewService(rdb *redis.Client, config func() Config) *Service {
+	return &Service{rdb: rdb, config: config}
+}
+
+// Record appends the accepted score to the capped history of its player along
+// with the rank it reached.
+func (s *Service) Record(ctx context.Context, score model.Score) error {
+	rank, err := s.Rank(ctx, score.ID)
+	if err != nil {
+		return err
+	}
+
+	config := s.config()
+	key := keyPrefix + score.ID
+	_, err = s.rdb.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.XAdd(ctx, &redis.XAddArgs{
+			Stream: key,
+			MaxLen: config.MaxEntries,
+			Approx: true,
+			Values: []any{"name", score.Name, "value", score.Value, "rank", rank},
+		})
+		if config.MaxAge > 0 {
+			pipe.PExpire(ctx, key, config.MaxAge)
+		}
+		return nil
+	})
+	return redisutil.Error(err)
+}
+
+// History returns the retained history of a player, oldest first.
+func (s *Service) History(ctx context.Context, playerID string) ([]Entry, error) {
+	messages, err := s.rdb.XRange(ctx, keyPrefix+playerID, "-", "+").Result()
+	if err != nil {
+		return nil, redisutil.Error(err)
+	}
+	if len(messages) == 0 {
+		return nil, apierror.NotFound("player not found")
+	}
+
+	entries := make([]Entry, len(messages))
+	for i, message := range messages {
+		entries[i] = entry(message)
+	}
+	return entries, nil
+}
+
+// Rank returns the current rank of the player, zero when they are not on the
+// leaderboard.
+func (s *Service) Rank(ctx context.Context, playerID string) (int64, error) {
+	rank, err := s.rdb.ZRevRank(ctx, score.Board, playerID).Result()
+	if errors.Is(err, redis.Nil) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, redisutil.Error(err)
+	}
+	return rank + 1, nil
+}
+
+// NewStats summarizes entries, the earliest best score wins ties.
+func NewStats(entries []Entry) Stats {
+	stats := Stats{Count: len(entries)}
+	if len(entries) == 0 {
+		return stats
+	}
+
+	total := 0
+	for i, entry := range entries {
+		total += entry.Value
+		if i == 0 || entry.Value > stats.Best {
+			stats.Best = entry.Value
+			stats.RankAtBest = entry.Rank
+		}
+		if entry.Rank > 0 && (stats.BestRank == 0 || entry.Rank < stats.BestRank) {
+			stats.BestRank = entry.Rank
+		}
+	}
+	stats.Average = float64(total) / float64(len(entries))
+	return stats
+}
+
+func entry(message redis.XMessage) Entry {
+	value, _ := strconv.Atoi(redisutil.Field(message, "value"))
+	rank, _ := strconv.ParseInt(redisutil.Field(message, "rank"), 10, 64)
+
+	return Entry{
+		Name:  redisutil.Field(message, "name"),
+		Value: value,
+		Rank:  rank,
+		At:    streamTime(message.ID),
+	}
+}
+
+// streamTime returns the time of a stream entry from the milliseconds of its ID.
+func streamTime(id string) time.Time {
+	millis, _, _ := strings.Cut(id, "-")
+	ms, _ := strconv.ParseInt(millis, 10, 64)
+	return time.UnixMilli(ms).UTC()
+}
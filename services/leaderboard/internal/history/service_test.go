@@ -0,0 +1,116 @@
+package history
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-redis/redismock/v9"
+	"github.com/iamnotrodger/golang-projects/pkg/apierror"
+	"github.com/iamnotrodger/golang-projects/services/leaderboard/internal/model"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/require"
+)
+
+func testConfig() Config {
+	return Config{MaxEntries: 50, MaxAge: time.Hour}
+}
+
+func TestService_Record(t *testing.T) {
+	rdb, mock := redismock.NewClientMock()
+	mock.ExpectZRevRank("leaderboard", "player-1").SetVal(2)
+	mock.ExpectTxPipeline()
+	mock.ExpectXAdd(&redis.XAddArgs{
+		Stream: "leaderboard:history:player-1",
+		MaxLen: 50,
+		Approx: true,
+		Values: []any{"name", "Alice", "value", 100, "rank", int64(3)},
+	}).SetVal("1700000000000-0")
+	mock.ExpectPExpire("leaderboard:history:player-1", time.Hour).SetVal(true)
+	mock.ExpectTxPipelineExec()
+
+	err := NewService(rdb, testConfig).Record(context.Background(), model.Score{ID: "player-1", Name: "Alice", Value: 100})
+
+	require.NoError(t, err)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestService_History(t *testing.T) {
+	tests := []struct {
+		name            string
+		setupMock       func(mock redismock.ClientMock)
+		expectedEntries []Entry
+		expectedError   error
+	}{
+		{
+			name: "returns the entries oldest first",
+			setupMock: func(mock redismock.ClientMock) {
+				mock.ExpectXRange("leaderboard:history:player-1", "-", "+").SetVal([]redis.XMessage{
+					{ID: "1700000000000-0", Values: map[string]any{"name": "Alice", "value": "100", "rank": "3"}},
+					{ID: "1700000060000-0", Values: map[string]any{"name": "Alice", "value": "250", "rank": "1"}},
+				})
+			},
+			expectedEntries: []Entry{
+				{Name: "Alice", Value: 100, Rank: 3, At: time.UnixMilli(1_700_000_000_000).UTC()},
+				{Name: "Alice", Value: 250, Rank: 1, At: time.UnixMilli(1_700_000_060_000).UTC()},
+			},
+		},
+		{
+			name: "reports players without history",
+			setupMock: func(mock redismock.ClientMock) {
+				mock.ExpectXRange("leaderboard:history:player-1", "-", "+").SetVal([]redis.XMessage{})
+			},
+			expectedError: apierror.NotFound("player not found"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rdb, mock := redismock.NewClientMock()
+			tt.setupMock(mock)
+
+			entries, err := NewService(rdb, testConfig).History(context.Background(), "player-1")
+
+			require.Equal(t, tt.expectedError, err)
+			require.Equal(t, tt.expectedEntries, entries)
+			require.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestNewStats(t *testing.T) {
+	tests := []struct {
+		name     string
+		entries  []Entry
+		expected Stats
+	}{
+		{
+			name:     "empty history",
+			expected: Stats{},
+		},
+		{
+			name: "best rank and rank at best score differ",
+			entries: []Entry{
+				{Value: 100, Rank: 5},
+				{Value: 300, Rank: 2},
+				{Value: 200, Rank: 1},
+				{Value: 300, Rank: 4},
+			},
+			expected: Stats{Count: 4, Best: 300, Average: 225, BestRank: 1, RankAtBest: 2},
+		},
+		{
+			name: "ignores ranks of players removed from the leaderboard",
+			entries: []Entry{
+				{Value: 100, Rank: 0},
+				{Value: 50, Rank: 7},
+			},
+			expected: Stats{Count: 2, Best: 100, Average: 75, BestRank: 7, RankAtBest: 0},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.expected, NewStats(tt.entries))
+		})
+	}
+}
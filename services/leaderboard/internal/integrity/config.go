@@ -0,0 +1,33 @@
+package integrity
+
+import "time"
+
+type Config struct {
+	// RequireSignature rejects unsigned submissions, signed ones are verified either way.
+	RequireSignature bool `mapstructure:"require_signature" reload:"true"`
+	// MaxClockSkew is how far the timestamp of a signed submission may be from now.
+	MaxClockSkew time.Duration `mapstructure:"max_clock_skew" validate:"min=1s" reload:"true"`
+	// ReviewLength is the approximate number of rejections kept for review.
+	ReviewLength int64 `mapstructure:"review_length" validate:"min=1"`
+	Rules        Rules `mapstructure:"rules"`
+}
+
+// Rules are the plausibility rules of a board, zero disables a rule.
+type Rules struct {
+	MaxValue int `mapstructure:"max_value" validate:"min=0" reload:"true"`
+	// MaxIncrease is how much the score of a player may grow per
+	// IncreaseInterval since their previous submission.
+	MaxIncrease      int           `mapstructure:"max_increase" validate:"min=0" reload:"true"`
+	IncreaseInterval time.Duration `mapstructure:"increase_interval" validate:"min=0" reload:"true"`
+	MinInterval      time.Duration `mapstructure:"min_interval" validate:"min=0" reload:"true"`
+}
+
+func NewConfig() Config {
+	return Config{
+		MaxClockSkew: 5 * time.Minute,
+		ReviewLength: 10000,
+		Rules: Rules{
+			IncreaseInterval: time.Minute,
+		},
+	}
+}
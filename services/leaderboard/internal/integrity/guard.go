@@ -0,0 +1,181 @@
+package integrity
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/iamnotrodger/golang-projects/pkg/apierror"
+	"github.com/iamnotrodger/golang-projects/pkg/auth"
+	"github.com/iamnotrodger/golang-projects/services/leaderboard/internal/model"
+)
+
+// Game servers sign submissions with the HMAC-SHA256 of Payload, hex encoded.
+const (
+	HeaderTimestamp = "X-Score-Timestamp"
+	HeaderNonce     = "X-Score-Nonce"
+	HeaderSignature = "X-Score-Signature"
+)
+
+// Reasons of the rejections.
+const (
+	ReasonSignature   = "invalid_signature"
+	ReasonReplay      = "replayed_nonce"
+	ReasonMaxValue    = "max_value"
+	ReasonMaxIncrease = "max_increase"
+	ReasonMinInterval = "min_interval"
+)
+
+type store interface {
+	ClaimNonce(ctx context.Context, nonce string, ttl time.Duration) (bool, error)
+	ReleaseNonce(ctx context.Context, nonce string) error
+	LastSubmission(ctx context.Context, player string) (Submission, bool, error)
+	SaveSubmission(ctx context.Context, player string, submission Submission) error
+	Reject(ctx context.Context, rejection Rejection) error
+}
+
+// Guard verifies the submissions to a board and records the rejected ones
+// for review.
+type Guard struct {
+	store  store
+	board  string
+	secret []byte
+	config func() Config
+	now    func() time.Time
+}
+
+// NewGuard creates the guard of board, signatures cannot be verified when
+// secret is empty. config is read on every submission.
+func NewGuard(store store, board, secret string, config func() Config) *Guard {
+	return &Guard{store: store, board: board, secret: []byte(secret), config: config, now: time.Now}
+}
+
+// Payload is the signed message: the timestamp in unix seconds, the nonce and
+// the request body separated by new lines.
+func Payload(timestamp, nonce string, body []byte) []byte {
+	return fmt.Appendf(nil, "%s\n%s\n%s", timestamp, nonce, body)
+}
+
+func Sign(secret string, timestamp, nonce string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(Payload(timestamp, nonce, body))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify checks the signature of body and claims its nonce, Release gives it
+// back when the submission could not be saved. Unsigned submissions are let
+// through unless signatures are required.
+func (g *Guard) Verify(ctx context.Context, header http.Header, body []byte, score model.Score) error {
+	config := g.config()
+	signature := header.Get(HeaderSignature)
+	if signature == "" && !config.RequireSignature {
+		return nil
+	}
+
+	timestamp, nonce := header.Get(HeaderTimestamp), header.Get(HeaderNonce)
+	if len(g.secret) == 0 || signature == "" || nonce == "" {
+		return g.reject(ctx, score, ReasonSignature, "missing score signature")
+	}
+
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return g.reject(ctx, score, ReasonSignature, "invalid score timestamp")
+	}
+	if skew := g.now().Sub(time.Unix(seconds, 0)).Abs(); skew > config.MaxClockSkew {
+		return g.reject(ctx, score, ReasonSignature, "score timestamp is too far from now")
+	}
+
+	expected := Sign(string(g.secret), timestamp, nonce, body)
+	if !hmac.Equal([]byte(signature), []byte(expected)) {
+		return g.reject(ctx, score, ReasonSignature, "invalid score signature")
+	}
+
+	// Older timestamps are rejected, nonces only have to be remembered while theirs are accepted.
+	claimed, err := g.store.ClaimNonce(ctx, nonce, 2*config.MaxClockSkew)
+	if err != nil {
+		return err
+	}
+	if !claimed {
+		return g.reject(ctx, score, ReasonReplay, "score nonce was already used")
+	}
+	return nil
+}
+
+// Check applies the plausibility rules to score against the previous
+// accepted submission of the player.
+func (g *Guard) Check(ctx context.Context, score model.Score) error {
+	rules := g.config().Rules
+	if rules.MaxValue > 0 && score.Value > rules.MaxValue {
+		return g.reject(ctx, score, ReasonMaxValue, fmt.Sprintf("score is above the maximum of %d", rules.MaxValue))
+	}
+	if rules.MaxIncrease == 0 && rules.MinInterval == 0 {
+		return nil
+	}
+
+	previous, ok, err := g.store.LastSubmission(ctx, score.ID)
+	if err != nil || !ok {
+		return err
+	}
+
+	elapsed := g.now().Sub(previous.At)
+	if rules.MinInterval > 0 && elapsed < rules.MinInterval {
+		return g.reject(ctx, score, ReasonMinInterval, fmt.Sprintf("scores may be submitted every %s", rules.MinInterval))
+	}
+	if rules.MaxIncrease > 0 && rules.IncreaseInterval > 0 {
+		allowed := float64(rules.MaxIncrease) * elapsed.Seconds() / rules.IncreaseInterval.Seconds()
+		if float64(score.Value-previous.Value) > allowed {
+			return g.reject(ctx, score, ReasonMaxIncrease, fmt.Sprintf("score may increase by %d every %s", rules.MaxIncrease, rules.IncreaseInterval))
+		}
+	}
+	return nil
+}
+
+// Accept records score as the latest accepted submission of the player.
+func (g *Guard) Accept(ctx context.Context, score model.Score) error {
+	return g.store.SaveSubmission(ctx, score.ID, Submission{Value: score.Value, At: g.now()})
+}
+
+// Release gives back the nonce claimed by Verify for a signed submission that
+// could not be saved, so that it can be retried.
+func (g *Guard) Release(ctx context.Context, header http.Header) error {
+	nonce := header.Get(HeaderNonce)
+	if header.Get(HeaderSignature) == "" || nonce == "" {
+		return nil
+	}
+	return g.store.ReleaseNonce(ctx, nonce)
+}
+
+func (g *Guard) reject(ctx context.Context, score model.Score, reason, detail string) error {
+	metric.RejectionCounter.WithLabelValues(g.board, reason).Inc()
+
+	rejection := Rejection{
+		Board:      g.board,
+		PlayerID:   score.ID,
+		Name:       score.Name,
+		Value:      score.Value,
+		Reason:     reason,
+		Detail:     detail,
+		RejectedAt: g.now(),
+	}
+	if principal, ok := auth.FromContext(ctx); ok {
+		rejection.Subject = principal.Subject
+	}
+	if err := g.store.Reject(ctx, rejection); err != nil {
+		slog.ErrorContext(ctx, "error recording rejected score", "reason", reason, "error", err.Error())
+	}
+
+	switch reason {
+	case ReasonSignature:
+		return apierror.Unauthorized(detail, nil)
+	case ReasonReplay:
+		return apierror.Conflict(detail, nil)
+	default:
+		return apierror.Validation(detail, nil)
+	}
+}
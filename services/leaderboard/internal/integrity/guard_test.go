@@ -0,0 +1,221 @@
+package integrity
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/iamnotrodger/golang-projects/pkg/apierror"
+	"github.com/iamnotrodger/golang-projects/services/leaderboard/internal/model"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+const testSecret = "secret"
+
+type MockStore struct {
+	mock.Mock
+}
+
+func (m *MockStore) ClaimNonce(ctx context.Context, nonce string, ttl time.Duration) (bool, error) {
+	args := m.Called(ctx, nonce, ttl)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockStore) ReleaseNonce(ctx context.Context, nonce string) error {
+	return m.Called(ctx, nonce).Error(0)
+}
+
+func (m *MockStore) LastSubmission(ctx context.Context, player string) (Submission, bool, error) {
+	args := m.Called(ctx, player)
+	return args.Get(0).(Submission), args.Bool(1), args.Error(2)
+}
+
+func (m *MockStore) SaveSubmission(ctx context.Context, player string, submission Submission) error {
+	args := m.Called(ctx, player, submission)
+	return args.Error(0)
+}
+
+func (m *MockStore) Reject(ctx context.Context, rejection Rejection) error {
+	args := m.Called(ctx, rejection)
+	return args.Error(0)
+}
+
+func newTestGuard(store *MockStore, now time.Time, config Config) *Guard {
+	guard := NewGuard(store, "leaderboard", testSecret, func() Config { return config })
+	guard.now = func() time.Time { return now }
+	return guard
+}
+
+func signedHeader(timestamp time.Time, nonce string, body []byte) http.Header {
+	seconds := strconv.FormatInt(timestamp.Unix(), 10)
+	header := http.Header{}
+	header.Set(HeaderTimestamp, seconds)
+	header.Set(HeaderNonce, nonce)
+	header.Set(HeaderSignature, Sign(testSecret, seconds, nonce, body))
+	return header
+}
+
+func TestGuard_Verify(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	body := []byte(`{"id":"player-1","name":"Alice","value":100}`)
+	score := model.Score{ID: "player-1", Name: "Alice", Value: 100}
+	config := NewConfig()
+	config.RequireSignature = true
+
+	tests := []struct {
+		name           string
+		header         http.Header
+		config         Config
+		claimed        bool
+		expectedReason string
+		expectedCode   string
+	}{
+		{
+			name:    "accepts signed submissions",
+			header:  signedHeader(now, "nonce-1", body),
+			config:  config,
+			claimed: true,
+		},
+		{
+			name:   "accepts unsigned submissions when signatures are optional",
+			header: http.Header{},
+			config: NewConfig(),
+		},
+		{
+			name:           "rejects unsigned submissions when signatures are required",
+			header:         http.Header{},
+			config:         config,
+			expectedReason: ReasonSignature,
+			expectedCode:   apierror.CodeUnauthorized,
+		},
+		{
+			name:           "rejects tampered submissions",
+			header:         signedHeader(now, "nonce-1", []byte(`{"id":"player-1","name":"Alice","value":9000}`)),
+			config:         config,
+			expectedReason: ReasonSignature,
+			expectedCode:   apierror.CodeUnauthorized,
+		},
+		{
+			name:           "rejects stale submissions",
+			header:         signedHeader(now.Add(-time.Hour), "nonce-1", body),
+			config:         config,
+			expectedReason: ReasonSignature,
+			expectedCode:   apierror.CodeUnauthorized,
+		},
+		{
+			name:           "rejects replayed nonces",
+			header:         signedHeader(now, "nonce-1", body),
+			config:         config,
+			claimed:        false,
+			expectedReason: ReasonReplay,
+			expectedCode:   apierror.CodeConflict,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := new(MockStore)
+			store.On("ClaimNonce", mock.Anything, "nonce-1", 10*time.Minute).Return(tt.claimed, nil).Maybe()
+			if tt.expectedReason != "" {
+				store.On("Reject", mock.Anything, mock.MatchedBy(func(rejection Rejection) bool {
+					return rejection.Reason == tt.expectedReason && rejection.PlayerID == "player-1" && rejection.Value == 100
+				})).Return(nil).Once()
+			}
+
+			err := newTestGuard(store, now, tt.config).Verify(context.Background(), tt.header, body, score)
+
+			if tt.expectedCode == "" {
+				require.NoError(t, err)
+			} else {
+				require.Equal(t, tt.expectedCode, apierror.From(err).Code)
+			}
+			store.AssertExpectations(t)
+		})
+	}
+}
+
+func TestGuard_Check(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	config := NewConfig()
+	config.Rules = Rules{
+		MaxValue:         10_000,
+		MaxIncrease:      1_000,
+		IncreaseInterval: time.Minute,
+		MinInterval:      10 * time.Second,
+	}
+
+	tests := []struct {
+		name           string
+		value          int
+		previous       *Submission
+		expectedReason string
+	}{
+		{
+			name:  "accepts the first submission",
+			value: 5_000,
+		},
+		{
+			name:     "accepts plausible increases",
+			value:    1_500,
+			previous: &Submission{Value: 1_000, At: now.Add(-30 * time.Second)},
+		},
+		{
+			name:           "rejects values above the maximum",
+			value:          10_001,
+			expectedReason: ReasonMaxValue,
+		},
+		{
+			name:           "rejects increases faster than allowed",
+			value:          1_600,
+			previous:       &Submission{Value: 1_000, At: now.Add(-30 * time.Second)},
+			expectedReason: ReasonMaxIncrease,
+		},
+		{
+			name:           "rejects submissions too close to the previous one",
+			value:          1_000,
+			previous:       &Submission{Value: 1_000, At: now.Add(-5 * time.Second)},
+			expectedReason: ReasonMinInterval,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := new(MockStore)
+			if tt.previous != nil {
+				store.On("LastSubmission", mock.Anything, "player-1").Return(*tt.previous, true, nil).Maybe()
+			} else {
+				store.On("LastSubmission", mock.Anything, "player-1").Return(Submission{}, false, nil).Maybe()
+			}
+			if tt.expectedReason != "" {
+				store.On("Reject", mock.Anything, mock.MatchedBy(func(rejection Rejection) bool {
+					return rejection.Reason == tt.expectedReason
+				})).Return(nil).Once()
+			}
+
+			score := model.Score{ID: "player-1", Name: "Alice", Value: tt.value}
+			err := newTestGuard(store, now, config).Check(context.Background(), score)
+
+			if tt.expectedReason == "" {
+				require.NoError(t, err)
+			} else {
+				require.Equal(t, apierror.CodeValidation, apierror.From(err).Code)
+			}
+			store.AssertExpectations(t)
+		})
+	}
+}
+
+func TestGuard_Release(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	body := []byte(`{"id":"player-1","name":"Alice","value":100}`)
+	store := new(MockStore)
+	store.On("ReleaseNonce", mock.Anything, "nonce-1").Return(nil).Once()
+	guard := newTestGuard(store, now, NewConfig())
+
+	require.NoError(t, guard.Release(context.Background(), signedHeader(now, "nonce-1", body)))
+	require.NoError(t, guard.Release(context.Background(), http.Header{}), "unsigned submissions have no nonce")
+	store.AssertExpectations(t)
+}
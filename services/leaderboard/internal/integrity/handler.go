@@ -0,0 +1,54 @@
+package integrity
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/iamnotrodger/golang-projects/pkg/apierror"
+)
+
+// ScopeScoresReview allows inspecting the rejected submissions.
+const ScopeScoresReview = "scores:review"
+
+const (
+	defaultReviewCount = 50
+	maxReviewCount     = 500
+)
+
+type reviewStore interface {
+	Rejections(ctx context.Context, before string, count int64) ([]Rejection, error)
+}
+
+type Handler struct {
+	store reviewStore
+}
+
+func NewHandler(store reviewStore) *Handler {
+	return &Handler{store: store}
+}
+
+func (h *Handler) RegisterRoutes(r gin.IRouter) {
+	r.GET("/admin/review", h.listRejections)
+}
+
+// listRejections lists the rejected submissions newest first, the next page
+// starts before the last ID of the previous one.
+func (h *Handler) listRejections(c *gin.Context) {
+	count := int64(defaultReviewCount)
+	if value := c.Query("count"); value != "" {
+		parsed, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || parsed < 1 || parsed > maxReviewCount {
+			c.Error(apierror.Validation("count must be between 1 and "+strconv.Itoa(maxReviewCount), err))
+			return
+		}
+		count = parsed
+	}
+
+	rejections, err := h.store.Rejections(c.Request.Context(), c.Query("before"), count)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(200, gin.H{"rejections": rejections})
+}
@@ -0,0 +1,108 @@
+package integrity
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/iamnotrodger/golang-projects/pkg/apierror"
+	"github.com/iamnotrodger/golang-projects/pkg/middleware"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockReviewStore struct {
+	mock.Mock
+}
+
+func (m *MockReviewStore) Rejections(ctx context.Context, before string, count int64) ([]Rejection, error) {
+	args := m.Called(ctx, before, count)
+	rejections, _ := args.Get(0).([]Rejection)
+	return rejections, args.Error(1)
+}
+
+func TestHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	rejections := []Rejection{{
+		ID:         "1700000000000-0",
+		Board:      "leaderboard",
+		PlayerID:   "player-1",
+		Name:       "Alice",
+		Value:      9000,
+		Reason:     ReasonMaxValue,
+		Detail:     "score is above the maximum of 5000",
+		RejectedAt: time.Unix(1_700_000_000, 0).UTC(),
+	}}
+
+	tests := []struct {
+		name               string
+		path               string
+		setupMock          func(store *MockReviewStore)
+		expectedStatusCode int
+	}{
+		{
+			name: "lists the latest rejections",
+			path: "/admin/review",
+			setupMock: func(store *MockReviewStore) {
+				store.On("Rejections", mock.Anything, "", int64(defaultReviewCount)).Return(rejections, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name: "pages before an entry",
+			path: "/admin/review?before=1700000000000-1&count=10",
+			setupMock: func(store *MockReviewStore) {
+				store.On("Rejections", mock.Anything, "1700000000000-1", int64(10)).Return(rejections, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name:               "rejects invalid counts",
+			path:               "/admin/review?count=0",
+			setupMock:          func(store *MockReviewStore) {},
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:               "rejects counts above the maximum",
+			path:               "/admin/review?count=501",
+			setupMock:          func(store *MockReviewStore) {},
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			name: "reports unavailable redis",
+			path: "/admin/review",
+			setupMock: func(store *MockReviewStore) {
+				store.On("Rejections", mock.Anything, "", int64(defaultReviewCount)).Return(nil, apierror.Unavailable("redis", errors.New("connection refused")))
+			},
+			expectedStatusCode: http.StatusServiceUnavailable,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := new(MockReviewStore)
+			tt.setupMock(store)
+			router := gin.New()
+			router.Use(middleware.Errors())
+			NewHandler(store).RegisterRoutes(router)
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+			require.Equal(t, tt.expectedStatusCode, w.Code)
+			if tt.expectedStatusCode == http.StatusOK {
+				var body struct {
+					Rejections []Rejection `json:"rejections"`
+				}
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+				require.Equal(t, rejections, body.Rejections)
+			}
+			store.AssertExpectations(t)
+		})
+	}
+}
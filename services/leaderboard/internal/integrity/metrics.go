@@ -0,0 +1,21 @@
+package integrity
+
+import "github.com/prometheus/client_golang/prometheus"
+
+type metrics struct {
+	RejectionCounter *prometheus.CounterVec
+}
+
+var metric = metrics{
+	RejectionCounter: prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "leaderboard_score_rejections_total",
+			Help: "total number of rejected score submissions",
+		},
+		[]string{"board", "reason"},
+	),
+}
+
+func MustRegister() {
+	prometheus.MustRegister(metric.RejectionCounter)
+}
@@ -0,0 +1,138 @@
+package integrity
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/iamnotrodger/golang-projects/services/leaderboard/internal/redisutil"
+	"github.com/redis/go-redis/v9"
+)
+
+// Submission is the latest accepted submission of a player.
+type Submission struct {
+	Value int       `json:"value"`
+	At    time.Time `json:"at"`
+}
+
+// Rejection is a rejected submission kept for review, ID is its stream entry.
+type Rejection struct {
+	ID         string    `json:"id"`
+	Board      string    `json:"board"`
+	PlayerID   string    `json:"player_id"`
+	Name       string    `json:"name"`
+	Value      int       `json:"value"`
+	Reason     string    `json:"reason"`
+	Detail     string    `json:"detail"`
+	Subject    string    `json:"subject,omitempty"`
+	RejectedAt time.Time `json:"rejected_at"`
+}
+
+// Store keeps the nonces, latest submissions and rejections of a board in redis.
+type Store struct {
+	rdb          *redis.Client
+	board        string
+	reviewLength func() int64
+}
+
+func NewStore(rdb *redis.Client, board string, reviewLength func() int64) *Store {
+	return &Store{rdb: rdb, board: board, reviewLength: reviewLength}
+}
+
+// ClaimNonce returns false when the nonce was already claimed within ttl.
+func (s *Store) ClaimNonce(ctx context.Context, nonce string, ttl time.Duration) (bool, error) {
+	claimed, err := s.rdb.SetNX(ctx, s.nonceKey(nonce), 1, ttl).Result()
+	return claimed, redisutil.Error(err)
+}
+
+// ReleaseNonce forgets a claimed nonce so that the submission can be retried.
+func (s *Store) ReleaseNonce(ctx context.Context, nonce string) error {
+	return redisutil.Error(s.rdb.Del(ctx, s.nonceKey(nonce)).Err())
+}
+
+func (s *Store) LastSubmission(ctx context.Context, player string) (Submission, bool, error) {
+	data, err := s.rdb.HGet(ctx, s.board+":submissions", player).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return Submission{}, false, nil
+	}
+	if err != nil {
+		return Submission{}, false, redisutil.Error(err)
+	}
+
+	var submission Submission
+	if err := json.Unmarshal(data, &submission); err != nil {
+		return Submission{}, false, err
+	}
+	return submission, true, nil
+}
+
+func (s *Store) SaveSubmission(ctx context.Context, player string, submission Submission) error {
+	data, err := json.Marshal(submission)
+	if err != nil {
+		return err
+	}
+	return redisutil.Error(s.rdb.HSet(ctx, s.board+":submissions", player, data).Err())
+}
+
+func (s *Store) Reject(ctx context.Context, rejection Rejection) error {
+	return redisutil.Error(s.rdb.XAdd(ctx, &redis.XAddArgs{
+		Stream: s.reviewStream(),
+		MaxLen: s.reviewLength(),
+		Approx: true,
+		Values: map[string]any{
+			"player_id":   rejection.PlayerID,
+			"name":        rejection.Name,
+			"value":       rejection.Value,
+			"reason":      rejection.Reason,
+			"detail":      rejection.Detail,
+			"subject":     rejection.Subject,
+			"rejected_at": rejection.RejectedAt.UnixMilli(),
+		},
+	}).Err())
+}
+
+// Rejections lists up to count rejections, newest first, starting before the
+// entry before or from the newest one when it is empty.
+func (s *Store) Rejections(ctx context.Context, before string, count int64) ([]Rejection, error) {
+	end := "+"
+	if before != "" {
+		end = "(" + before
+	}
+	messages, err := s.rdb.XRevRangeN(ctx, s.reviewStream(), end, "-", count).Result()
+	if err != nil {
+		return nil, redisutil.Error(err)
+	}
+
+	rejections := make([]Rejection, len(messages))
+	for i, message := range messages {
+		rejections[i] = s.rejection(message)
+	}
+	return rejections, nil
+}
+
+func (s *Store) nonceKey(nonce string) string {
+	return s.board + ":nonce:" + nonce
+}
+
+func (s *Store) reviewStream() string {
+	return s.board + ":review"
+}
+
+func (s *Store) rejection(message redis.XMessage) Rejection {
+	value, _ := strconv.Atoi(redisutil.Field(message, "value"))
+	rejectedAt, _ := strconv.ParseInt(redisutil.Field(message, "rejected_at"), 10, 64)
+
+	return Rejection{
+		ID:         message.ID,
+		Board:      s.board,
+		PlayerID:   redisutil.Field(message, "player_id"),
+		Name:       redisutil.Field(message, "name"),
+		Value:      value,
+		Reason:     redisutil.Field(message, "reason"),
+		Detail:     redisutil.Field(message, "detail"),
+		Subject:    redisutil.Field(message, "subject"),
+		RejectedAt: time.UnixMilli(rejectedAt).UTC(),
+	}
+}
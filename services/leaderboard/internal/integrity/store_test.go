@@ -0,0 +1,92 @@
+package integrity
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-redis/redismock/v9"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/require"
+)
+
+func testReviewLength() int64 {
+	return 100
+}
+
+func TestStore_ClaimNonce(t *testing.T) {
+	rdb, mock := redismock.NewClientMock()
+	mock.ExpectSetNX("leaderboard:nonce:nonce-1", 1, time.Minute).SetVal(true)
+	mock.ExpectSetNX("leaderboard:nonce:nonce-1", 1, time.Minute).SetVal(false)
+	store := NewStore(rdb, "leaderboard", testReviewLength)
+
+	claimed, err := store.ClaimNonce(context.Background(), "nonce-1", time.Minute)
+	require.NoError(t, err)
+	require.True(t, claimed)
+
+	claimed, err = store.ClaimNonce(context.Background(), "nonce-1", time.Minute)
+	require.NoError(t, err)
+	require.False(t, claimed)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestStore_ReleaseNonce(t *testing.T) {
+	rdb, mock := redismock.NewClientMock()
+	mock.ExpectDel("leaderboard:nonce:nonce-1").SetVal(1)
+
+	require.NoError(t, NewStore(rdb, "leaderboard", testReviewLength).ReleaseNonce(context.Background(), "nonce-1"))
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestStore_LastSubmission(t *testing.T) {
+	rdb, mock := redismock.NewClientMock()
+	mock.ExpectHGet("leaderboard:submissions", "player-1").SetVal(`{"value":100,"at":"2023-11-14T22:13:20Z"}`)
+	mock.ExpectHGet("leaderboard:submissions", "player-2").RedisNil()
+	store := NewStore(rdb, "leaderboard", testReviewLength)
+
+	submission, ok, err := store.LastSubmission(context.Background(), "player-1")
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, 100, submission.Value)
+	require.True(t, submission.At.Equal(time.Unix(1_700_000_000, 0)))
+
+	_, ok, err = store.LastSubmission(context.Background(), "player-2")
+	require.NoError(t, err)
+	require.False(t, ok)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestStore_Rejections(t *testing.T) {
+	rdb, mock := redismock.NewClientMock()
+	mock.ExpectXRevRangeN("leaderboard:review", "(1700000000000-1", "-", 10).SetVal([]redis.XMessage{
+		{
+			ID: "1699999999000-0",
+			Values: map[string]any{
+				"player_id":   "player-1",
+				"name":        "Alice",
+				"value":       "9000",
+				"reason":      ReasonMaxIncrease,
+				"detail":      "score may increase by 1000 every 1m0s",
+				"subject":     "game-server",
+				"rejected_at": "1699999999000",
+			},
+		},
+	})
+	store := NewStore(rdb, "leaderboard", testReviewLength)
+
+	rejections, err := store.Rejections(context.Background(), "1700000000000-1", 10)
+
+	require.NoError(t, err)
+	require.Equal(t, []Rejection{{
+		ID:         "1699999999000-0",
+		Board:      "leaderboard",
+		PlayerID:   "player-1",
+		Name:       "Alice",
+		Value:      9000,
+		Reason:     ReasonMaxIncrease,
+		Detail:     "score may increase by 1000 every 1m0s",
+		Subject:    "game-server",
+		RejectedAt: time.UnixMilli(1_699_999_999_000).UTC(),
+	}}, rejections)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
@@ -6,14 +6,20 @@ import (
 	"fmt"
 	"log/slog"
 	"net/http"
+	"strconv"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/iamnotrodger/golang-projects/pkg/apierror"
 	"github.com/iamnotrodger/golang-projects/services/leaderboard/internal/model"
 )
 
+// maxTopK bounds the scores read at once.
+const maxTopK = 100
+
 type Service interface {
 	GetTopK(ctx context.Context, k int) ([]model.Score, error)
+	Rank(ctx context.Context, member string) (model.Rank, error)
 }
 
 type leaderboardHub interface {
@@ -24,27 +30,59 @@ type leaderboardHub interface {
 type Handler struct {
 	service Service
 	hub     leaderboardHub
+	topK    func() int
+}
+
+func NewHandler(service Service, hub leaderboardHub, topK func() int) *Handler {
+	return &Handler{service: service, hub: hub, topK: topK}
 }
 
-func NewHandler(service Service, hub leaderboardHub) *Handler {
-	return &Handler{service: service, hub: hub}
+// RegisterRoutes registers the routes of a board, the player board is served
+// under /leaderboard and the team board under /leaderboard/teams.
+func (h *Handler) RegisterRoutes(r gin.IRouter) {
+	r.GET("/stream", h.HandleSSE)
+	r.GET("/top", h.getTopK)
+	r.GET("/rank/:member", h.getRank)
 }
 
-func (h *Handler) RegisterRoutes(engine *gin.Engine) {
-	engine.GET("/leaderboard/stream", h.HandleSSE)
+func (h *Handler) getTopK(c *gin.Context) {
+	k := h.topK()
+	if value := c.Query("k"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 1 || parsed > maxTopK {
+			c.Error(apierror.Validation("k must be between 1 and "+strconv.Itoa(maxTopK), err))
+			return
+		}
+		k = parsed
+	}
+
+	scores, err := h.service.GetTopK(c.Request.Context(), k)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, scores)
+}
+
+func (h *Handler) getRank(c *gin.Context) {
+	rank, err := h.service.Rank(c.Request.Context(), c.Param("member"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, rank)
 }
 
 func (h *Handler) HandleSSE(c *gin.Context) {
 	c.Writer.Header().Set("Content-Type", "text/event-stream")
 	c.Writer.Header().Set("Cache-Control", "no-cache")
 	c.Writer.Header().Set("Connection", "keep-alive")
-	c.Writer.Header().Set("Access-Control-Allow-Origin", "*")
 
 	id := c.Request.RemoteAddr
 	clientChan := h.hub.RegisterClient(id)
 	defer h.hub.UnregisterClient(id)
 
-	initialScores, err := h.service.GetTopK(c.Request.Context(), 10)
+	initialScores, err := h.service.GetTopK(c.Request.Context(), h.topK())
 	if err != nil {
 		slog.Error("error getting initial leaderboard", "error", err)
 	} else {
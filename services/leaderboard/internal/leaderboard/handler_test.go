@@ -0,0 +1,103 @@
+package leaderboard
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/iamnotrodger/golang-projects/pkg/apierror"
+	"github.com/iamnotrodger/golang-projects/pkg/middleware"
+	"github.com/iamnotrodger/golang-projects/services/leaderboard/internal/model"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockService struct {
+	mock.Mock
+}
+
+func (m *MockService) GetTopK(ctx context.Context, k int) ([]model.Score, error) {
+	args := m.Called(ctx, k)
+	scores, _ := args.Get(0).([]model.Score)
+	return scores, args.Error(1)
+}
+
+func (m *MockService) Rank(ctx context.Context, member string) (model.Rank, error) {
+	args := m.Called(ctx, member)
+	return args.Get(0).(model.Rank), args.Error(1)
+}
+
+func TestHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name               string
+		path               string
+		setupMock          func(service *MockService)
+		expectedStatusCode int
+		expectedBody       string
+	}{
+		{
+			name: "returns the top k by default",
+			path: "/leaderboard/teams/top",
+			setupMock: func(service *MockService) {
+				service.On("GetTopK", mock.Anything, 10).Return([]model.Score{{Name: "red", Value: 130}}, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedBody:       `[{"name":"red","value":130}]`,
+		},
+		{
+			name: "returns the requested number of scores",
+			path: "/leaderboard/teams/top?k=3",
+			setupMock: func(service *MockService) {
+				service.On("GetTopK", mock.Anything, 3).Return([]model.Score{}, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedBody:       `[]`,
+		},
+		{
+			name:               "rejects invalid k",
+			path:               "/leaderboard/teams/top?k=1000",
+			setupMock:          func(service *MockService) {},
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			name: "returns the rank of a member",
+			path: "/leaderboard/teams/rank/red",
+			setupMock: func(service *MockService) {
+				service.On("Rank", mock.Anything, "red").Return(model.Rank{Name: "red", Rank: 1, Value: 130}, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedBody:       `{"name":"red","rank":1,"value":130}`,
+		},
+		{
+			name: "reports members missing from the board",
+			path: "/leaderboard/teams/rank/green",
+			setupMock: func(service *MockService) {
+				service.On("Rank", mock.Anything, "green").Return(model.Rank{}, apierror.NotFound("green is not on the leaderboard"))
+			},
+			expectedStatusCode: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := new(MockService)
+			tt.setupMock(service)
+			router := gin.New()
+			router.Use(middleware.Errors())
+			NewHandler(service, NewHub(), func() int { return 10 }).RegisterRoutes(router.Group("/leaderboard/teams"))
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+			require.Equal(t, tt.expectedStatusCode, w.Code)
+			if tt.expectedBody != "" {
+				require.JSONEq(t, tt.expectedBody, w.Body.String())
+			}
+			service.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,10 @@
+package model
+
+// Rank is the position of a member of a board, starting at 1. ID is the
+// player ID on the player board.
+type Rank struct {
+	ID    string `json:"id,omitempty"`
+	Name  string `json:"name"`
+	Rank  int64  `json:"rank"`
+	Value int    `json:"value"`
+}
@@ -1,7 +1,9 @@
 package model
 
+// Score is the score of a player, the ID identifies them on the leaderboard
+// and the name is displayed.
 type Score struct {
-	ID    string `json:"id"`
-	Name  string `json:"name"`
+	ID    string `json:"id,omitempty" binding:"required"`
+	Name  string `json:"name" binding:"required"`
 	Value int    `json:"value"`
 }
@@ -4,40 +4,124 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"net"
 	"net/http"
+	"sync"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/iamnotrodger/golang-projects/pkg/app"
+	"github.com/iamnotrodger/golang-projects/pkg/auth"
+	pkgconfig "github.com/iamnotrodger/golang-projects/pkg/config"
 	"github.com/iamnotrodger/golang-projects/pkg/health"
+	"github.com/iamnotrodger/golang-projects/pkg/middleware"
+	"github.com/iamnotrodger/golang-projects/pkg/ratelimit"
+	"github.com/iamnotrodger/golang-projects/pkg/telemetry"
+	"github.com/iamnotrodger/golang-projects/services/leaderboard/internal/admin"
 	"github.com/iamnotrodger/golang-projects/services/leaderboard/internal/config"
+	"github.com/iamnotrodger/golang-projects/services/leaderboard/internal/friends"
 	"github.com/iamnotrodger/golang-projects/services/leaderboard/internal/healthcheck"
+	"github.com/iamnotrodger/golang-projects/services/leaderboard/internal/history"
+	"github.com/iamnotrodger/golang-projects/services/leaderboard/internal/integrity"
 	"github.com/iamnotrodger/golang-projects/services/leaderboard/internal/leaderboard"
 	"github.com/iamnotrodger/golang-projects/services/leaderboard/internal/score"
+	"github.com/iamnotrodger/golang-projects/services/leaderboard/internal/team"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 )
 
 type HttpServer struct {
-	server *http.Server
-	hub    *leaderboard.Hub
+	server    *http.Server
+	hub       *leaderboard.Hub
+	teamHub   *leaderboard.Hub
+	ready     chan struct{}
+	readyOnce sync.Once
 }
 
 type HttpServerServices struct {
-	HealthService *health.Service
+	Config        *pkgconfig.Loader[*config.Spec]
+	LogLevel      *slog.LevelVar
+	HealthMonitor *health.Monitor
 	ScoreService  *score.Service
+	AdminService  *admin.Service
+	TeamService   *team.Service
+	// TeamHub streams the top teams.
+	TeamHub        *leaderboard.Hub
+	FriendsService *friends.Service
+	// HistoryService keeps the history of the saved scores.
+	HistoryService *history.Service
+	ScoreGuard     *integrity.Guard
+	// ReviewStore keeps the rejected submissions.
+	ReviewStore *integrity.Store
+	// Authenticator authenticates the writes and admin routes. The writes are
+	// open when it is nil, the admin routes are not registered.
+	Authenticator auth.Authenticator
+	RateLimiter   ratelimit.Limiter
 }
 
 func NewHttpServer(engine *gin.Engine, hub *leaderboard.Hub, services HttpServerServices) *HttpServer {
+	engine.Use(
+		telemetry.Middleware(config.ServiceName),
+		middleware.RequestID(),
+		middleware.Metrics(),
+		middleware.Logger(config.Global.HTTP.AccessLog),
+		middleware.Errors(),
+		middleware.Recovery(),
+		middleware.CORS(config.Global.HTTP.CORS),
+	)
 	engine.StaticFile("/", "./web/index.html")
 	engine.Static("/web", "./web")
 
-	scoreHandler := score.NewHandler(services.ScoreService)
-	scoreHandler.RegisterRoutes(engine)
+	topK := func() int {
+		return services.Config.Current().TopK
+	}
+
+	saveScoreLimit := func() ratelimit.Limit {
+		return services.Config.Current().RateLimit.SaveScore
+	}
+
+	scoreHandler := score.NewHandler(services.ScoreService, services.ScoreGuard, []score.Recorder{services.HistoryService, services.TeamService}, topK)
+	scoreHandler.RegisterRoutes(engine.Group("",
+		auth.Require(services.Authenticator, score.ScopeScoresWrite),
+		ratelimit.Middleware(services.RateLimiter, "save_score", saveScoreLimit),
+	))
+
+	teamHandler := team.NewHandler(services.TeamService)
+	teamHandler.RegisterRoutes(engine)
+
+	if services.Authenticator != nil {
+		reviewHandler := integrity.NewHandler(services.ReviewStore)
+		reviewHandler.RegisterRoutes(engine.Group("", auth.Require(services.Authenticator, integrity.ScopeScoresReview)))
+
+		adminHandler := admin.NewHandler(services.AdminService, services.ScoreService, services.TeamService, topK)
+		adminRoutes := engine.Group("", auth.Require(services.Authenticator, admin.ScopeLeaderboardAdmin))
+		adminHandler.RegisterRoutes(adminRoutes)
+		teamHandler.RegisterAdminRoutes(adminRoutes)
+
+		serviceAdmin := engine.Group("", auth.Require(services.Authenticator, auth.ScopeServiceAdmin))
+		serviceAdmin.GET("/debug/config", gin.WrapH(pkgconfig.Handler(services.Config.Snapshot)))
+		serviceAdmin.Match([]string{"GET", "PUT"}, "/admin/log-level", gin.WrapH(app.LogLevelHandler(services.LogLevel, app.ConfigLogLevel(services.Config.Set, "log_level"))))
+	} else {
+		slog.Warn("admin, review, config and log level routes are disabled because authentication is disabled")
+	}
+
+	historyHandler := history.NewHandler(services.HistoryService)
+	historyHandler.RegisterRoutes(engine)
+
+	friendsHandler := friends.NewHandler(services.FriendsService, hub)
+	friendsHandler.RegisterRoutes(engine)
+	friendsHandler.RegisterWriteRoutes(engine.Group("", auth.Require(services.Authenticator, friends.ScopeFriendsWrite)))
 
-	leaderboardHandler := leaderboard.NewHandler(services.ScoreService, hub)
-	leaderboardHandler.RegisterRoutes(engine)
+	leaderboardHandler := leaderboard.NewHandler(services.ScoreService, hub, topK)
+	leaderboardHandler.RegisterRoutes(engine.Group("/leaderboard"))
 
-	healthHandler := healthcheck.NewHandler(services.HealthService)
+	teamBoardHandler := leaderboard.NewHandler(services.TeamService.Board(), services.TeamHub, topK)
+	teamBoardHandler.RegisterRoutes(engine.Group("/leaderboard/teams"))
+
+	healthHandler := healthcheck.NewHandler(services.HealthMonitor)
 	healthHandler.RegisterRoutes(engine)
 
+	engine.GET("/metrics", gin.WrapH(promhttp.Handler()))
+
 	server := &http.Server{
 		Addr:         fmt.Sprintf("0.0.0.0:%v", config.Global.Port),
 		WriteTimeout: 0,
@@ -46,18 +130,33 @@ func NewHttpServer(engine *gin.Engine, hub *leaderboard.Hub, services HttpServer
 	}
 
 	return &HttpServer{
-		server: server,
-		hub:    hub,
+		server:  server,
+		hub:     hub,
+		teamHub: services.TeamHub,
+		ready:   make(chan struct{}),
 	}
 }
 
 func (h *HttpServer) Run(ctx context.Context, errChan chan error) {
-	go h.start(errChan)
+	listener, err := net.Listen("tcp", h.server.Addr)
+	if err != nil {
+		errChan <- err
+		return
+	}
+	h.readyOnce.Do(func() {
+		close(h.ready)
+	})
+
+	go h.start(listener, errChan)
 	h.stop(ctx)
 }
 
-func (h *HttpServer) start(errChan chan error) {
-	errChan <- h.server.ListenAndServe()
+func (h *HttpServer) Ready() <-chan struct{} {
+	return h.ready
+}
+
+func (h *HttpServer) start(listener net.Listener, errChan chan error) {
+	errChan <- h.server.Serve(listener)
 }
 
 func (h *HttpServer) stop(ctx context.Context) {
@@ -66,6 +165,7 @@ func (h *HttpServer) stop(ctx context.Context) {
 	slog.Info("shutting down http server")
 
 	h.hub.Shutdown()
+	h.teamHub.Shutdown()
 
 	shutdownCtx := context.Background()
 	if err := h.server.Shutdown(shutdownCtx); err != nil {
@@ -3,7 +3,9 @@ package processes
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"log/slog"
+	"sync"
 
 	"github.com/iamnotrodger/golang-projects/services/leaderboard/internal/model"
 	"github.com/redis/go-redis/v9"
@@ -12,14 +14,20 @@ import (
 type MessageHandler func(scores []model.Score)
 
 type LeaderboardSubscriber struct {
-	rdb     *redis.Client
-	handler MessageHandler
+	rdb       *redis.Client
+	channel   string
+	handler   MessageHandler
+	ready     chan struct{}
+	readyOnce sync.Once
 }
 
-func NewLeaderSubscriber(rdb *redis.Client, handler MessageHandler) *LeaderboardSubscriber {
+// NewLeaderSubscriber subscribes to the top scores published to channel.
+func NewLeaderSubscriber(rdb *redis.Client, channel string, handler MessageHandler) *LeaderboardSubscriber {
 	return &LeaderboardSubscriber{
 		rdb:     rdb,
+		channel: channel,
 		handler: handler,
+		ready:   make(chan struct{}),
 	}
 }
 
@@ -28,19 +36,38 @@ func (l *LeaderboardSubscriber) Run(ctx context.Context, errChan chan error) {
 	<-ctx.Done()
 }
 
+func (l *LeaderboardSubscriber) Ready() <-chan struct{} {
+	return l.ready
+}
+
 func (h *LeaderboardSubscriber) start(ctx context.Context, errChan chan error) {
-	pubsub := h.rdb.Subscribe(ctx, "leaderboard:top10")
+	pubsub := h.rdb.Subscribe(ctx, h.channel)
 	defer pubsub.Close()
 
+	if _, err := pubsub.Receive(ctx); err != nil {
+		if ctx.Err() == nil {
+			errChan <- err
+		}
+		return
+	}
+
+	h.readyOnce.Do(func() {
+		close(h.ready)
+	})
+
 	ch := pubsub.Channel()
-	slog.Info("subscribed to leaderboard:top10")
+	slog.Info("subscribed to top scores", "channel", h.channel)
 
 	for {
 		select {
 		case <-ctx.Done():
-			errChan <- ctx.Err()
 			return
-		case msg := <-ch:
+		case msg, ok := <-ch:
+			if !ok {
+				errChan <- errors.New("leaderboard subscription closed")
+				return
+			}
+
 			var scores []model.Score
 			if err := json.Unmarshal([]byte(msg.Payload), &scores); err != nil {
 				slog.Error("Error unmarshaling leaderboard data", "error", err)
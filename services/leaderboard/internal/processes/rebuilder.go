@@ -0,0 +1,59 @@
+package processes
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+type Rebuilder interface {
+	Rebuild(ctx context.Context) error
+}
+
+// TeamRebuilder rebuilds the team board on an interval.
+type TeamRebuilder struct {
+	rebuilder Rebuilder
+	interval  time.Duration
+	ready     chan struct{}
+	readyOnce sync.Once
+}
+
+func NewTeamRebuilder(rebuilder Rebuilder, interval time.Duration) *TeamRebuilder {
+	return &TeamRebuilder{
+		rebuilder: rebuilder,
+		interval:  interval,
+		ready:     make(chan struct{}),
+	}
+}
+
+func (t *TeamRebuilder) Run(ctx context.Context, errChan chan error) {
+	t.readyOnce.Do(func() {
+		close(t.ready)
+	})
+
+	ticker := time.NewTicker(t.interval)
+	defer ticker.Stop()
+
+	for {
+		t.rebuild(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (t *TeamRebuilder) Ready() <-chan struct{} {
+	return t.ready
+}
+
+func (t *TeamRebuilder) rebuild(ctx context.Context) {
+	start := time.Now()
+	if err := t.rebuilder.Rebuild(ctx); err != nil {
+		slog.Error("error rebuilding team board", "error", err.Error())
+		return
+	}
+	slog.Debug("rebuilt team board", "duration", time.Since(start))
+}
@@ -0,0 +1,36 @@
+package redisutil
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+
+	"github.com/iamnotrodger/golang-projects/pkg/apierror"
+	"github.com/redis/go-redis/v9"
+)
+
+// Error reports the connection failures of redis as unavailable, the other
+// errors are returned as they are.
+func Error(err error) error {
+	var netErr net.Error
+	switch {
+	case err == nil:
+		return nil
+	case errors.As(err, &netErr),
+		errors.Is(err, io.EOF),
+		errors.Is(err, context.DeadlineExceeded),
+		errors.Is(err, redis.ErrClosed),
+		errors.Is(err, redis.ErrPoolTimeout):
+		return apierror.Unavailable("redis", err)
+	default:
+		return err
+	}
+}
+
+// Field returns the string value of a field of a stream message, or "" when
+// it is missing.
+func Field(message redis.XMessage, name string) string {
+	value, _ := message.Values[name].(string)
+	return value
+}
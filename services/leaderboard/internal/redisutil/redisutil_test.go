@@ -0,0 +1,38 @@
+package redisutil
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/iamnotrodger/golang-projects/pkg/apierror"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/require"
+)
+
+func TestError(t *testing.T) {
+	tests := []struct {
+		name          string
+		err           error
+		expectedError error
+	}{
+		{name: "nil", err: nil, expectedError: nil},
+		{name: "pool timeout", err: redis.ErrPoolTimeout, expectedError: apierror.Unavailable("redis", redis.ErrPoolTimeout)},
+		{name: "deadline", err: context.DeadlineExceeded, expectedError: apierror.Unavailable("redis", context.DeadlineExceeded)},
+		{name: "command error", err: errors.New("WRONGTYPE"), expectedError: errors.New("WRONGTYPE")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.expectedError, Error(tt.err))
+		})
+	}
+}
+
+func TestField(t *testing.T) {
+	message := redis.XMessage{Values: map[string]any{"name": "Alice", "value": 100}}
+
+	require.Equal(t, "Alice", Field(message, "name"))
+	require.Empty(t, Field(message, "value"))
+	require.Empty(t, Field(message, "rank"))
+}
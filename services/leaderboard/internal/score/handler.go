@@ -3,26 +3,57 @@ package score
 import (
 	"context"
 	"log/slog"
+	"net/http"
 
 	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/iamnotrodger/golang-projects/pkg/apierror"
+	"github.com/iamnotrodger/golang-projects/pkg/auth"
 	"github.com/iamnotrodger/golang-projects/services/leaderboard/internal/model"
 )
 
+const (
+	// ScopeScoresWrite allows players to submit their own scores.
+	ScopeScoresWrite = "scores:write"
+	// ScopeScoresWriteAny allows submitting the scores of any player, it is meant for game servers.
+	ScopeScoresWriteAny = "scores:write:any"
+)
+
 type scoreService interface {
 	SaveScore(ctx context.Context, score *model.Score) error
 	GetTopK(ctx context.Context, k int) ([]model.Score, error)
 	PublishTopScores(ctx context.Context, topScores []model.Score) error
 }
 
+// submissionGuard verifies the submissions before they are saved.
+type submissionGuard interface {
+	Verify(ctx context.Context, header http.Header, body []byte, score model.Score) error
+	Check(ctx context.Context, score model.Score) error
+	Accept(ctx context.Context, score model.Score) error
+	Release(ctx context.Context, header http.Header) error
+}
+
+// Recorder is told about every saved score, like the player history and the
+// team boards.
+type Recorder interface {
+	Record(ctx context.Context, score model.Score) error
+}
+
 type Handler struct {
-	service scoreService
+	service   scoreService
+	guard     submissionGuard
+	recorders []Recorder
+	topK      func() int
 }
 
-func NewHandler(service scoreService) *Handler {
-	return &Handler{service: service}
+// NewHandler creates the score handler, submissions are not verified when
+// guard is nil. topK returns the number of scores to publish and is read on
+// every request so it can change at runtime.
+func NewHandler(service scoreService, guard submissionGuard, recorders []Recorder, topK func() int) *Handler {
+	return &Handler{service: service, guard: guard, recorders: recorders, topK: topK}
 }
 
-func (h *Handler) RegisterRoutes(r *gin.Engine) {
+func (h *Handler) RegisterRoutes(r gin.IRouter) {
 	score := r.Group("/score")
 	{
 		score.POST("/", h.saveScore)
@@ -30,24 +61,64 @@ func (h *Handler) RegisterRoutes(r *gin.Engine) {
 }
 
 func (h *Handler) saveScore(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	// The raw body is kept to verify its signature.
+	body, err := c.GetRawData()
+	if err != nil {
+		c.Error(apierror.Validation("invalid score", err))
+		return
+	}
 	var score model.Score
-	if err := c.ShouldBindJSON(&score); err != nil {
-		slog.Error("saveScore error parsing request", "error", err)
-		c.JSON(400, gin.H{"error": err.Error()})
+	if err := binding.JSON.BindBody(body, &score); err != nil {
+		c.Error(apierror.Validation("invalid score", err))
+		return
+	}
+
+	if principal, ok := auth.FromContext(ctx); ok && !canSubmit(principal, &score) {
+		c.Error(apierror.Forbidden("players may only submit their own scores", nil))
 		return
 	}
 
-	if err := h.service.SaveScore(c.Request.Context(), &score); err != nil {
-		slog.Error("saveScore error saving score", "error", err.Error())
-		c.JSON(500, gin.H{"error": err.Error()})
+	if h.guard != nil {
+		if err := h.guard.Verify(ctx, c.Request.Header, body, score); err != nil {
+			c.Error(err)
+			return
+		}
+		if err := h.guard.Check(ctx, score); err != nil {
+			c.Error(err)
+			return
+		}
+	}
+
+	if err := h.service.SaveScore(ctx, &score); err != nil {
+		if h.guard != nil {
+			if err := h.guard.Release(ctx, c.Request.Header); err != nil {
+				slog.ErrorContext(ctx, "saveScore error releasing the submission nonce", "error", err.Error())
+			}
+		}
+		c.Error(err)
 		return
 	}
 
+	if h.guard != nil {
+		if err := h.guard.Accept(ctx, score); err != nil {
+			slog.ErrorContext(ctx, "saveScore error recording accepted submission", "error", err.Error())
+		}
+	}
+
 	c.Status(204)
 
+	topK := h.topK()
 	go func(ctx context.Context) {
 		ctx = context.WithoutCancel(ctx)
-		topScores, err := h.service.GetTopK(ctx, 10)
+		for _, recorder := range h.recorders {
+			if err := recorder.Record(ctx, score); err != nil {
+				slog.Error("saveScore error recording score", "error", err.Error())
+			}
+		}
+
+		topScores, err := h.service.GetTopK(ctx, topK)
 		if err != nil {
 			slog.Error("saveScore error getting top scores", "error", err.Error())
 			return
@@ -56,5 +127,9 @@ func (h *Handler) saveScore(c *gin.Context) {
 		if err := h.service.PublishTopScores(ctx, topScores); err != nil {
 			slog.Error("saveScore error publishing top scores", "error", err.Error())
 		}
-	}(c.Request.Context())
+	}(ctx)
+}
+
+func canSubmit(principal auth.Principal, score *model.Score) bool {
+	return principal.Subject == score.ID || principal.HasScope(ScopeScoresWriteAny)
 }
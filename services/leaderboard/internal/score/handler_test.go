@@ -8,8 +8,12 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/iamnotrodger/golang-projects/pkg/apierror"
+	"github.com/iamnotrodger/golang-projects/pkg/auth"
+	"github.com/iamnotrodger/golang-projects/pkg/middleware"
 	"github.com/iamnotrodger/golang-projects/services/leaderboard/internal/model"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -38,15 +42,52 @@ func (m *MockScoreService) PublishTopScores(ctx context.Context, topScores []mod
 	return args.Error(0)
 }
 
+type MockGuard struct {
+	mock.Mock
+}
+
+func (m *MockGuard) Verify(ctx context.Context, header http.Header, body []byte, score model.Score) error {
+	args := m.Called(ctx, header, body, score)
+	return args.Error(0)
+}
+
+func (m *MockGuard) Check(ctx context.Context, score model.Score) error {
+	args := m.Called(ctx, score)
+	return args.Error(0)
+}
+
+func (m *MockGuard) Accept(ctx context.Context, score model.Score) error {
+	args := m.Called(ctx, score)
+	return args.Error(0)
+}
+
+func (m *MockGuard) Release(ctx context.Context, header http.Header) error {
+	args := m.Called(ctx, header)
+	return args.Error(0)
+}
+
+// silentT collects mock assertions while waiting for the asynchronous publish of the top scores.
+type silentT struct{}
+
+func (silentT) Logf(format string, args ...any)   {}
+func (silentT) Errorf(format string, args ...any) {}
+func (silentT) FailNow()                          {}
+
+func testTopK() int {
+	return 10
+}
+
 func setupTestRouter() *gin.Engine {
 	gin.SetMode(gin.TestMode)
-	return gin.New()
+	router := gin.New()
+	router.Use(middleware.Errors())
+	return router
 }
 
 func TestNewHandler(t *testing.T) {
 	mockService := new(MockScoreService)
 
-	handler := NewHandler(mockService)
+	handler := NewHandler(mockService, nil, nil, testTopK)
 
 	assert.NotNil(t, handler)
 	assert.Equal(t, mockService, handler.service)
@@ -55,7 +96,7 @@ func TestNewHandler(t *testing.T) {
 func TestHandler_RegisterRoutes(t *testing.T) {
 	t.Run("should register routes correctly", func(t *testing.T) {
 		mockService := new(MockScoreService)
-		handler := NewHandler(mockService)
+		handler := NewHandler(mockService, nil, nil, testTopK)
 		router := setupTestRouter()
 
 		handler.RegisterRoutes(router)
@@ -119,7 +160,36 @@ func TestHandler_SaveScore(t *testing.T) {
 				return mockService
 			},
 			expectedStatusCode: http.StatusInternalServerError,
-			expectedBody:       map[string]any{"error": "redis save score error"},
+			expectedBody:       map[string]any{"error": "internal_error", "code": 500.0, "description": "internal server error"},
+		},
+		{
+			name:        "should return 503 when redis is unavailable",
+			requestBody: requestBody,
+			score:       score,
+			setupMock: func(score *model.Score) *MockScoreService {
+				mockService := new(MockScoreService)
+				mockService.On("SaveScore", mock.Anything, score).Return(apierror.Unavailable("redis", errors.New("connection refused")))
+				return mockService
+			},
+			expectedStatusCode: http.StatusServiceUnavailable,
+			expectedBody:       map[string]any{"error": "dependency_unavailable", "code": 503.0, "description": "redis is unavailable"},
+		},
+		{
+			name:        "should return 400 when the score is invalid",
+			requestBody: []byte(`{"value":`),
+			setupMock: func(score *model.Score) *MockScoreService {
+				return new(MockScoreService)
+			},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedBody:       map[string]any{"error": "validation_failed", "code": 400.0, "description": "invalid score"},
+		},
+		{
+			name:        "should return 400 when the player ID is missing",
+			requestBody: map[string]any{"name": "Alice", "value": 100},
+			setupMock: func(score *model.Score) *MockScoreService {
+				return new(MockScoreService)
+			},
+			expectedStatusCode: http.StatusBadRequest,
 		},
 		{
 			name:        "should return 204 when GetTopK fails",
@@ -151,7 +221,7 @@ func TestHandler_SaveScore(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			mockScoreService := tt.setupMock(tt.score)
-			handler := NewHandler(mockScoreService)
+			handler := NewHandler(mockScoreService, nil, nil, testTopK)
 
 			router := setupTestRouter()
 			handler.RegisterRoutes(router)
@@ -174,7 +244,165 @@ func TestHandler_SaveScore(t *testing.T) {
 				require.EqualValues(t, tt.expectedBody, responseBody)
 			}
 
+			require.Eventually(t, func() bool {
+				return mockScoreService.AssertExpectations(silentT{})
+			}, time.Second, time.Millisecond)
 			mockScoreService.AssertExpectations(t)
 		})
 	}
 }
+
+func TestHandler_SaveScore_Principal(t *testing.T) {
+	score := &model.Score{ID: "player-1", Name: "Alice", Value: 100}
+
+	tests := []struct {
+		name               string
+		principal          auth.Principal
+		expectedStatusCode int
+	}{
+		{
+			name:               "players submit their own scores",
+			principal:          auth.Principal{Subject: "player-1", Scopes: []string{ScopeScoresWrite}},
+			expectedStatusCode: http.StatusNoContent,
+		},
+		{
+			name:               "players cannot submit the scores of others",
+			principal:          auth.Principal{Subject: "player-2", Scopes: []string{ScopeScoresWrite}},
+			expectedStatusCode: http.StatusForbidden,
+		},
+		{
+			name:               "game servers submit any score",
+			principal:          auth.Principal{Subject: "game-server", Scopes: []string{ScopeScoresWrite, ScopeScoresWriteAny}},
+			expectedStatusCode: http.StatusNoContent,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockScoreService)
+			mockService.On("SaveScore", mock.Anything, score).Return(nil).Maybe()
+			mockService.On("GetTopK", mock.Anything, mock.Anything).Return([]model.Score{}, nil).Maybe()
+			mockService.On("PublishTopScores", mock.Anything, mock.Anything).Return(nil).Maybe()
+
+			router := setupTestRouter()
+			router.Use(func(c *gin.Context) {
+				c.Request = c.Request.WithContext(auth.NewContext(c.Request.Context(), tt.principal))
+			})
+			NewHandler(mockService, nil, nil, testTopK).RegisterRoutes(router)
+
+			requestBody, err := json.Marshal(score)
+			require.NoError(t, err)
+			req := httptest.NewRequest(http.MethodPost, "/score/", bytes.NewBuffer(requestBody))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			require.Equal(t, tt.expectedStatusCode, w.Code)
+			if tt.expectedStatusCode == http.StatusForbidden {
+				mockService.AssertNotCalled(t, "SaveScore", mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
+
+func TestHandler_SaveScore_Guard(t *testing.T) {
+	score := model.Score{ID: "player-1", Name: "Alice", Value: 100}
+	body := []byte(`{"id":"player-1","name":"Alice","value":100}`)
+
+	tests := []struct {
+		name               string
+		verifyErr          error
+		checkErr           error
+		saveErr            error
+		expectedStatusCode int
+	}{
+		{
+			name:               "saves verified scores",
+			expectedStatusCode: http.StatusNoContent,
+		},
+		{
+			name:               "rejects invalid signatures",
+			verifyErr:          apierror.Unauthorized("invalid score signature", nil),
+			expectedStatusCode: http.StatusUnauthorized,
+		},
+		{
+			name:               "rejects implausible scores",
+			checkErr:           apierror.Validation("score is above the maximum of 50", nil),
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:               "releases the nonce of unsaved scores",
+			saveErr:            apierror.Unavailable("redis", errors.New("connection refused")),
+			expectedStatusCode: http.StatusServiceUnavailable,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockScoreService)
+			mockService.On("SaveScore", mock.Anything, &score).Return(tt.saveErr).Maybe()
+			mockService.On("GetTopK", mock.Anything, mock.Anything).Return([]model.Score{}, nil).Maybe()
+			mockService.On("PublishTopScores", mock.Anything, mock.Anything).Return(nil).Maybe()
+			mockGuard := new(MockGuard)
+			mockGuard.On("Verify", mock.Anything, mock.Anything, body, score).Return(tt.verifyErr)
+			mockGuard.On("Check", mock.Anything, score).Return(tt.checkErr).Maybe()
+			mockGuard.On("Accept", mock.Anything, score).Return(nil).Maybe()
+			mockGuard.On("Release", mock.Anything, mock.Anything).Return(nil).Maybe()
+
+			router := setupTestRouter()
+			NewHandler(mockService, mockGuard, nil, testTopK).RegisterRoutes(router)
+
+			req := httptest.NewRequest(http.MethodPost, "/score/", bytes.NewBuffer(body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			require.Equal(t, tt.expectedStatusCode, w.Code)
+			switch {
+			case tt.expectedStatusCode == http.StatusNoContent:
+				mockGuard.AssertCalled(t, "Accept", mock.Anything, score)
+				mockGuard.AssertNotCalled(t, "Release", mock.Anything, mock.Anything)
+			case tt.saveErr != nil:
+				mockGuard.AssertCalled(t, "Release", mock.Anything, mock.Anything)
+				mockGuard.AssertNotCalled(t, "Accept", mock.Anything, mock.Anything)
+			default:
+				mockService.AssertNotCalled(t, "SaveScore", mock.Anything, mock.Anything)
+				mockGuard.AssertNotCalled(t, "Accept", mock.Anything, mock.Anything)
+				mockGuard.AssertNotCalled(t, "Release", mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
+
+type MockRecorder struct {
+	mock.Mock
+}
+
+func (m *MockRecorder) Record(ctx context.Context, score model.Score) error {
+	args := m.Called(ctx, score)
+	return args.Error(0)
+}
+
+func TestHandler_SaveScore_Recorders(t *testing.T) {
+	score := model.Score{ID: "player-1", Name: "Alice", Value: 100}
+
+	mockService := new(MockScoreService)
+	mockService.On("SaveScore", mock.Anything, &score).Return(nil)
+	mockService.On("GetTopK", mock.Anything, 10).Return([]model.Score{score}, nil)
+	mockService.On("PublishTopScores", mock.Anything, []model.Score{score}).Return(nil)
+	mockRecorder := new(MockRecorder)
+	mockRecorder.On("Record", mock.Anything, score).Return(errors.New("redis error"))
+
+	router := setupTestRouter()
+	NewHandler(mockService, nil, []Recorder{mockRecorder}, testTopK).RegisterRoutes(router)
+
+	req := httptest.NewRequest(http.MethodPost, "/score/", bytes.NewBufferString(`{"id":"player-1","name":"Alice","value":100}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusNoContent, w.Code)
+	require.Eventually(t, func() bool {
+		return mockService.AssertExpectations(silentT{}) && mockRecorder.AssertExpectations(silentT{})
+	}, time.Second, time.Millisecond, "the top scores are published even when a recorder fails")
+}
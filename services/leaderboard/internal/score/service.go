@@ -3,29 +3,80 @@ package score
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"slices"
 
+	"github.com/iamnotrodger/golang-projects/pkg/apierror"
 	"github.com/iamnotrodger/golang-projects/services/leaderboard/internal/model"
+	"github.com/iamnotrodger/golang-projects/services/leaderboard/internal/redisutil"
 	"github.com/redis/go-redis/v9"
 )
 
+const (
+	// Board is the key of the leaderboard, the keys of its state start with it.
+	Board = "leaderboard"
+	// TopScoresChannel is where the top scores of the leaderboard are published.
+	TopScoresChannel = Board + ":top10"
+	// Bans is the hash of the banned player IDs.
+	Bans = Board + ":bans"
+	// Names is the hash of the name of every player ID, the IDs are the
+	// members of the board.
+	Names = Board + ":names"
+)
+
 type Service struct {
-	rdb *redis.Client
+	rdb     *redis.Client
+	board   string
+	channel string
+	// names is the hash of the names of the members, the members of boards
+	// without one are their names.
+	names string
 }
 
+// NewService creates the service of the player leaderboard.
 func NewService(rdb *redis.Client) *Service {
-	return &Service{rdb: rdb}
+	return &Service{rdb: rdb, board: Board, channel: TopScoresChannel, names: Names}
+}
+
+// NewBoardService creates the service of the sorted set board, its top scores
+// are published to channel and its members are their own names.
+func NewBoardService(rdb *redis.Client, board, channel string) *Service {
+	return &Service{rdb: rdb, board: board, channel: channel}
 }
 
+// SaveScore saves the score of the player ID unless they are banned, their
+// name is kept for display.
 func (s *Service) SaveScore(ctx context.Context, score *model.Score) error {
-	err := s.rdb.ZAdd(ctx, "leaderboard", redis.Z{
-		Score:  float64(score.Value),
-		Member: score.Name,
-	}).Err()
-	return err
+	banned, err := s.rdb.HExists(ctx, Bans, score.ID).Result()
+	if err != nil {
+		return redisutil.Error(err)
+	}
+	if banned {
+		return apierror.Forbidden("player is banned", nil)
+	}
+
+	_, err = s.rdb.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.ZAdd(ctx, s.board, redis.Z{
+			Score:  float64(score.Value),
+			Member: score.ID,
+		})
+		pipe.HSet(ctx, Names, score.ID, score.Name)
+		return nil
+	})
+	return redisutil.Error(err)
 }
 
 func (s *Service) GetTopK(ctx context.Context, k int) ([]model.Score, error) {
-	results, err := s.rdb.ZRevRangeWithScores(ctx, "leaderboard", 0, int64(k-1)).Result()
+	results, err := s.rdb.ZRevRangeWithScores(ctx, s.board, 0, int64(k-1)).Result()
+	if err != nil {
+		return nil, redisutil.Error(err)
+	}
+
+	members := make([]string, len(results))
+	for i, result := range results {
+		members[i] = result.Member.(string)
+	}
+	names, err := s.Names(ctx, members...)
 	if err != nil {
 		return nil, err
 	}
@@ -33,18 +84,63 @@ func (s *Service) GetTopK(ctx context.Context, k int) ([]model.Score, error) {
 	scores := make([]model.Score, len(results))
 	for i, result := range results {
 		scores[i] = model.Score{
-			Name:  result.Member.(string),
+			Name:  names[i],
 			Value: int(result.Score),
 		}
+		if s.names != "" {
+			scores[i].ID = members[i]
+		}
 	}
 
 	return scores, nil
 }
 
+// Rank returns the rank, starting at 1, and the score of member.
+func (s *Service) Rank(ctx context.Context, member string) (model.Rank, error) {
+	pipe := s.rdb.Pipeline()
+	rank := pipe.ZRevRank(ctx, s.board, member)
+	value := pipe.ZScore(ctx, s.board, member)
+	if _, err := pipe.Exec(ctx); errors.Is(err, redis.Nil) {
+		return model.Rank{}, apierror.NotFound(member + " is not on the leaderboard")
+	} else if err != nil {
+		return model.Rank{}, redisutil.Error(err)
+	}
+
+	names, err := s.Names(ctx, member)
+	if err != nil {
+		return model.Rank{}, err
+	}
+	result := model.Rank{Name: names[0], Rank: rank.Val() + 1, Value: int(value.Val())}
+	if s.names != "" {
+		result.ID = member
+	}
+	return result, nil
+}
+
+// Names returns the names of the members of the board. Members without a
+// known name, like the ones saved before names were kept, are their own names.
+func (s *Service) Names(ctx context.Context, members ...string) ([]string, error) {
+	names := slices.Clone(members)
+	if s.names == "" || len(members) == 0 {
+		return names, nil
+	}
+
+	values, err := s.rdb.HMGet(ctx, s.names, members...).Result()
+	if err != nil {
+		return nil, redisutil.Error(err)
+	}
+	for i, value := range values {
+		if name, ok := value.(string); ok && name != "" {
+			names[i] = name
+		}
+	}
+	return names, nil
+}
+
 func (s *Service) PublishTopScores(ctx context.Context, topScores []model.Score) error {
 	leaderboardData, err := json.Marshal(topScores)
 	if err != nil {
 		return err
 	}
-	return s.rdb.Publish(ctx, "leaderboard:top10", leaderboardData).Err()
+	return redisutil.Error(s.rdb.Publish(ctx, s.channel, leaderboardData).Err())
 }
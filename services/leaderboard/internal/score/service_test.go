@@ -6,6 +6,7 @@ import (
 	"testing"
 
 	"github.com/go-redis/redismock/v9"
+	"github.com/iamnotrodger/golang-projects/pkg/apierror"
 	"github.com/iamnotrodger/golang-projects/services/leaderboard/internal/model"
 	"github.com/redis/go-redis/v9"
 	"github.com/stretchr/testify/assert"
@@ -30,11 +31,15 @@ func TestService_SaveScore(t *testing.T) {
 			},
 			setupMock: func() (*redis.Client, redismock.ClientMock) {
 				rdb, mock := redismock.NewClientMock()
+				mock.ExpectHExists("leaderboard:bans", "user1").SetVal(false)
 
+				mock.ExpectTxPipeline()
 				mock.ExpectZAdd("leaderboard", redis.Z{
 					Score:  100.0,
-					Member: "Alice",
+					Member: "user1",
 				}).SetVal(1)
+				mock.ExpectHSet("leaderboard:names", "user1", "Alice").SetVal(1)
+				mock.ExpectTxPipelineExec()
 
 				return rdb, mock
 			},
@@ -49,25 +54,63 @@ func TestService_SaveScore(t *testing.T) {
 			},
 			setupMock: func() (*redis.Client, redismock.ClientMock) {
 				rdb, mock := redismock.NewClientMock()
+				mock.ExpectHExists("leaderboard:bans", "user1").SetVal(false)
 
+				mock.ExpectTxPipeline()
 				mock.ExpectZAdd("leaderboard", redis.Z{
 					Score:  100.0,
-					Member: "Alice",
+					Member: "user1",
 				}).SetErr(redis.Nil)
 
 				return rdb, mock
 			},
 			expectedError: redis.Nil,
 		},
+		{
+			name: "should report unavailable redis",
+			score: &model.Score{
+				ID:    "user1",
+				Name:  "Alice",
+				Value: 100,
+			},
+			setupMock: func() (*redis.Client, redismock.ClientMock) {
+				rdb, mock := redismock.NewClientMock()
+				mock.ExpectHExists("leaderboard:bans", "user1").SetVal(false)
+
+				mock.ExpectTxPipeline()
+				mock.ExpectZAdd("leaderboard", redis.Z{
+					Score:  100.0,
+					Member: "user1",
+				}).SetErr(redis.ErrPoolTimeout)
+
+				return rdb, mock
+			},
+			expectedError: apierror.Unavailable("redis", redis.ErrPoolTimeout),
+		},
+		{
+			name: "should reject banned players",
+			score: &model.Score{
+				ID:    "user1",
+				Name:  "Alice",
+				Value: 100,
+			},
+			setupMock: func() (*redis.Client, redismock.ClientMock) {
+				rdb, mock := redismock.NewClientMock()
+				mock.ExpectHExists("leaderboard:bans", "user1").SetVal(true)
+				return rdb, mock
+			},
+			expectedError: apierror.Forbidden("player is banned", nil),
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			rdb, _ := tt.setupMock()
+			rdb, mock := tt.setupMock()
 			service := NewService(rdb)
 			ctx := context.Background()
 			err := service.SaveScore(ctx, tt.score)
 			require.Equal(t, tt.expectedError, err)
+			require.NoError(t, mock.ExpectationsWereMet())
 
 		})
 	}
@@ -91,18 +134,19 @@ func TestService_GetTopK(t *testing.T) {
 			setupMock: func(k int) (*redis.Client, redismock.ClientMock) {
 				rdb, mock := redismock.NewClientMock()
 				mock.ExpectZRevRangeWithScores("leaderboard", 0, int64(k-1)).SetVal([]redis.Z{
-					{Score: 100, Member: "Alice"},
-					{Score: 90, Member: "Bob"},
+					{Score: 100, Member: "user1"},
+					{Score: 90, Member: "user2"},
 					{Score: 80, Member: "Charlie"},
 				})
+				mock.ExpectHMGet("leaderboard:names", "user1", "user2", "Charlie").SetVal([]any{"Alice", "Bob", nil})
 
 				return rdb, mock
 			},
 			expectedResult: result{
 				scores: []model.Score{
-					{Name: "Alice", Value: 100},
-					{Name: "Bob", Value: 90},
-					{Name: "Charlie", Value: 80},
+					{ID: "user1", Name: "Alice", Value: 100},
+					{ID: "user2", Name: "Bob", Value: 90},
+					{ID: "Charlie", Name: "Charlie", Value: 80},
 				},
 				err: nil,
 			},
@@ -136,21 +180,22 @@ func TestService_GetTopK(t *testing.T) {
 			setupMock: func(k int) (*redis.Client, redismock.ClientMock) {
 				rdb, mock := redismock.NewClientMock()
 				mock.ExpectZRevRangeWithScores("leaderboard", 0, int64(k-1)).SetVal([]redis.Z{
-					{Score: 100, Member: "Alice"},
-					{Score: 90, Member: "Bob"},
-					{Score: 80, Member: "Charlie"},
-					{Score: 70, Member: "David"},
-					{Score: 60, Member: "Eve"},
+					{Score: 100, Member: "user1"},
+					{Score: 90, Member: "user2"},
+					{Score: 80, Member: "user3"},
+					{Score: 70, Member: "user4"},
+					{Score: 60, Member: "user5"},
 				})
+				mock.ExpectHMGet("leaderboard:names", "user1", "user2", "user3", "user4", "user5").SetVal([]any{"Alice", "Bob", "Charlie", "David", "Eve"})
 				return rdb, mock
 			},
 			expectedResult: result{
 				scores: []model.Score{
-					{Name: "Alice", Value: 100},
-					{Name: "Bob", Value: 90},
-					{Name: "Charlie", Value: 80},
-					{Name: "David", Value: 70},
-					{Name: "Eve", Value: 60},
+					{ID: "user1", Name: "Alice", Value: 100},
+					{ID: "user2", Name: "Bob", Value: 90},
+					{ID: "user3", Name: "Charlie", Value: 80},
+					{ID: "user4", Name: "David", Value: 70},
+					{ID: "user5", Name: "Eve", Value: 60},
 				},
 				err: nil,
 			},
@@ -249,3 +294,42 @@ func TestService_PublishTopScores(t *testing.T) {
 		})
 	}
 }
+
+func TestService_Rank(t *testing.T) {
+	tests := []struct {
+		name          string
+		setupMock     func(mock redismock.ClientMock)
+		expectedRank  model.Rank
+		expectedError error
+	}{
+		{
+			name: "returns the rank starting at 1",
+			setupMock: func(mock redismock.ClientMock) {
+				mock.ExpectZRevRank("leaderboard", "user1").SetVal(0)
+				mock.ExpectZScore("leaderboard", "user1").SetVal(100)
+				mock.ExpectHMGet("leaderboard:names", "user1").SetVal([]any{"Alice"})
+			},
+			expectedRank: model.Rank{ID: "user1", Name: "Alice", Rank: 1, Value: 100},
+		},
+		{
+			name: "reports members missing from the board",
+			setupMock: func(mock redismock.ClientMock) {
+				mock.ExpectZRevRank("leaderboard", "user1").RedisNil()
+			},
+			expectedError: apierror.NotFound("user1 is not on the leaderboard"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rdb, mock := redismock.NewClientMock()
+			tt.setupMock(mock)
+
+			rank, err := NewService(rdb).Rank(context.Background(), "user1")
+
+			require.Equal(t, tt.expectedError, err)
+			require.Equal(t, tt.expectedRank, rank)
+			require.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
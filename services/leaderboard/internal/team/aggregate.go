@@ -0,0 +1,32 @@
+package team
+
+import (
+	"slices"
+)
+
+// Aggregate computes the score of a team from the scores of its members.
+func Aggregate(scores []float64, aggregation string, topN int) float64 {
+	if len(scores) == 0 {
+		return 0
+	}
+
+	switch aggregation {
+	case AggregationMax:
+		return slices.Max(scores)
+	case AggregationTopNAvg:
+		sorted := slices.Sorted(slices.Values(scores))
+		slices.Reverse(sorted)
+		best := sorted[:min(topN, len(sorted))]
+		return sum(best) / float64(len(best))
+	default:
+		return sum(scores)
+	}
+}
+
+func sum(scores []float64) float64 {
+	total := 0.0
+	for _, score := range scores {
+		total += score
+	}
+	return total
+}
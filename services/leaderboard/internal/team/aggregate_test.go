@@ -0,0 +1,31 @@
+package team
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAggregate(t *testing.T) {
+	scores := []float64{10, 40, 20, 30}
+
+	tests := []struct {
+		name        string
+		scores      []float64
+		aggregation string
+		topN        int
+		expected    float64
+	}{
+		{name: "sum", scores: scores, aggregation: AggregationSum, expected: 100},
+		{name: "max", scores: scores, aggregation: AggregationMax, expected: 40},
+		{name: "average of the top members", scores: scores, aggregation: AggregationTopNAvg, topN: 2, expected: 35},
+		{name: "average of teams smaller than n", scores: scores, aggregation: AggregationTopNAvg, topN: 10, expected: 25},
+		{name: "empty team", aggregation: AggregationMax, expected: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.expected, Aggregate(tt.scores, tt.aggregation, tt.topN))
+		})
+	}
+}
@@ -0,0 +1,35 @@
+package team
+
+import "time"
+
+const (
+	// ModeIncremental recomputes the score of a team whenever one of its
+	// members scores or its membership changes.
+	ModeIncremental = "incremental"
+	// ModeRebuild recomputes every team on an interval, membership changes
+	// are still applied right away.
+	ModeRebuild = "rebuild"
+)
+
+const (
+	AggregationSum     = "sum"
+	AggregationMax     = "max"
+	AggregationTopNAvg = "top_n_avg"
+)
+
+type Config struct {
+	Mode        string `mapstructure:"mode" validate:"oneof=incremental rebuild"`
+	Aggregation string `mapstructure:"aggregation" validate:"oneof=sum max top_n_avg" reload:"true"`
+	// TopN is the number of best members averaged by the top_n_avg aggregation.
+	TopN            int           `mapstructure:"top_n" validate:"min=1" reload:"true"`
+	RebuildInterval time.Duration `mapstructure:"rebuild_interval" validate:"min=1s"`
+}
+
+func NewConfig() Config {
+	return Config{
+		Mode:            ModeIncremental,
+		Aggregation:     AggregationSum,
+		TopN:            5,
+		RebuildInterval: time.Minute,
+	}
+}
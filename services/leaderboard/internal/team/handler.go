@@ -0,0 +1,71 @@
+package team
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/iamnotrodger/golang-projects/pkg/apierror"
+)
+
+type teamService interface {
+	Join(ctx context.Context, playerID, team string) error
+	Leave(ctx context.Context, playerID string) error
+	Members(ctx context.Context, team string) ([]Member, error)
+}
+
+type joinRequest struct {
+	Team string `json:"team" binding:"required"`
+}
+
+type Handler struct {
+	service teamService
+}
+
+func NewHandler(service teamService) *Handler {
+	return &Handler{service: service}
+}
+
+// RegisterRoutes registers the routes listing the members of the teams.
+func (h *Handler) RegisterRoutes(r gin.IRouter) {
+	r.GET("/leaderboard/teams/:team/members", h.listMembers)
+}
+
+// RegisterAdminRoutes registers the routes changing the membership of the players.
+func (h *Handler) RegisterAdminRoutes(r gin.IRouter) {
+	players := r.Group("/admin/leaderboard/players/:id")
+	{
+		players.PUT("/team", h.join)
+		players.DELETE("/team", h.leave)
+	}
+}
+
+func (h *Handler) listMembers(c *gin.Context) {
+	members, err := h.service.Members(c.Request.Context(), c.Param("team"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"team": c.Param("team"), "members": members})
+}
+
+func (h *Handler) join(c *gin.Context) {
+	var request joinRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.Error(apierror.Validation("a team is required", err))
+		return
+	}
+	if err := h.service.Join(c.Request.Context(), c.Param("id"), request.Team); err != nil {
+		c.Error(err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func (h *Handler) leave(c *gin.Context) {
+	if err := h.service.Leave(c.Request.Context(), c.Param("id")); err != nil {
+		c.Error(err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
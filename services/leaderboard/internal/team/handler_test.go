@@ -0,0 +1,123 @@
+package team
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/iamnotrodger/golang-projects/pkg/apierror"
+	"github.com/iamnotrodger/golang-projects/pkg/middleware"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockTeamService struct {
+	mock.Mock
+}
+
+func (m *MockTeamService) Join(ctx context.Context, playerID, team string) error {
+	return m.Called(ctx, playerID, team).Error(0)
+}
+
+func (m *MockTeamService) Leave(ctx context.Context, playerID string) error {
+	return m.Called(ctx, playerID).Error(0)
+}
+
+func (m *MockTeamService) Members(ctx context.Context, team string) ([]Member, error) {
+	args := m.Called(ctx, team)
+	members, _ := args.Get(0).([]Member)
+	return members, args.Error(1)
+}
+
+func TestHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name               string
+		method             string
+		path               string
+		body               string
+		setupMock          func(service *MockTeamService)
+		expectedStatusCode int
+		expectedBody       map[string]any
+	}{
+		{
+			name:   "joins a team",
+			method: http.MethodPut,
+			path:   "/admin/leaderboard/players/player-1/team",
+			body:   `{"team":"red"}`,
+			setupMock: func(service *MockTeamService) {
+				service.On("Join", mock.Anything, "player-1", "red").Return(nil)
+			},
+			expectedStatusCode: http.StatusNoContent,
+		},
+		{
+			name:               "requires a team to join",
+			method:             http.MethodPut,
+			path:               "/admin/leaderboard/players/player-1/team",
+			body:               `{}`,
+			setupMock:          func(service *MockTeamService) {},
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:   "leaves the team",
+			method: http.MethodDelete,
+			path:   "/admin/leaderboard/players/player-1/team",
+			setupMock: func(service *MockTeamService) {
+				service.On("Leave", mock.Anything, "player-1").Return(nil)
+			},
+			expectedStatusCode: http.StatusNoContent,
+		},
+		{
+			name:   "reports players without a team",
+			method: http.MethodDelete,
+			path:   "/admin/leaderboard/players/player-2/team",
+			setupMock: func(service *MockTeamService) {
+				service.On("Leave", mock.Anything, "player-2").Return(apierror.NotFound("player is not in a team"))
+			},
+			expectedStatusCode: http.StatusNotFound,
+		},
+		{
+			name:   "lists the members",
+			method: http.MethodGet,
+			path:   "/leaderboard/teams/red/members",
+			setupMock: func(service *MockTeamService) {
+				service.On("Members", mock.Anything, "red").Return([]Member{{ID: "player-1", Name: "Alice"}}, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedBody: map[string]any{
+				"team":    "red",
+				"members": []any{map[string]any{"id": "player-1", "name": "Alice"}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := new(MockTeamService)
+			tt.setupMock(service)
+			router := gin.New()
+			router.Use(middleware.Errors())
+			handler := NewHandler(service)
+			handler.RegisterRoutes(router)
+			handler.RegisterAdminRoutes(router)
+
+			req := httptest.NewRequest(tt.method, tt.path, bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			require.Equal(t, tt.expectedStatusCode, w.Code)
+			if tt.expectedBody != nil {
+				body := map[string]any{}
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+				require.Equal(t, tt.expectedBody, body)
+			}
+			service.AssertExpectations(t)
+		})
+	}
+}
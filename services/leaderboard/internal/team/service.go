@@ -0,0 +1,244 @@
+package team
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"slices"
+
+	"github.com/iamnotrodger/golang-projects/pkg/apierror"
+	"github.com/iamnotrodger/golang-projects/services/leaderboard/internal/model"
+	"github.com/iamnotrodger/golang-projects/services/leaderboard/internal/redisutil"
+	"github.com/iamnotrodger/golang-projects/services/leaderboard/internal/score"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	// Board is the sorted set of the team scores.
+	Board = score.Board + ":teams"
+	// TopScoresChannel is where the top team scores are published.
+	TopScoresChannel = Board + ":top10"
+
+	// membership is the hash of the team of every player ID.
+	membership = Board + ":membership"
+	// membersPrefix starts the keys of the sets of the player IDs of every team.
+	membersPrefix = Board + ":members:"
+	rebuildKey    = Board + ":rebuild"
+)
+
+// Member is a player of a team.
+type Member struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+type Service struct {
+	rdb     *redis.Client
+	board   *score.Service
+	players *score.Service
+	config  func() Config
+	topK    func() int
+}
+
+// NewService creates the team service, config and topK are read on every
+// update. Players are identified by their ID like on the player board.
+func NewService(rdb *redis.Client, config func() Config, topK func() int) *Service {
+	return &Service{
+		rdb:     rdb,
+		board:   score.NewBoardService(rdb, Board, TopScoresChannel),
+		players: score.NewService(rdb),
+		config:  config,
+		topK:    topK,
+	}
+}
+
+// Board returns the service of the team board.
+func (s *Service) Board() *score.Service {
+	return s.board
+}
+
+// Join moves the player to team, the scores of the team and of the previous
+// one are recomputed.
+func (s *Service) Join(ctx context.Context, playerID, team string) error {
+	previous, err := s.rdb.HGet(ctx, membership, playerID).Result()
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return redisutil.Error(err)
+	}
+
+	_, err = s.rdb.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.HSet(ctx, membership, playerID, team)
+		if previous != "" && previous != team {
+			pipe.SRem(ctx, membersPrefix+previous, playerID)
+		}
+		pipe.SAdd(ctx, membersPrefix+team, playerID)
+		return nil
+	})
+	if err != nil {
+		return redisutil.Error(err)
+	}
+
+	teams := []string{team}
+	if previous != "" && previous != team {
+		teams = append(teams, previous)
+	}
+	return s.update(ctx, teams...)
+}
+
+// Leave removes the player from their team and recomputes its score.
+func (s *Service) Leave(ctx context.Context, playerID string) error {
+	team, err := s.rdb.HGet(ctx, membership, playerID).Result()
+	if errors.Is(err, redis.Nil) {
+		return apierror.NotFound("player is not in a team")
+	}
+	if err != nil {
+		return redisutil.Error(err)
+	}
+
+	_, err = s.rdb.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.HDel(ctx, membership, playerID)
+		pipe.SRem(ctx, membersPrefix+team, playerID)
+		return nil
+	})
+	if err != nil {
+		return redisutil.Error(err)
+	}
+	return s.update(ctx, team)
+}
+
+// Members returns the players of team ordered by ID, with their names.
+func (s *Service) Members(ctx context.Context, team string) ([]Member, error) {
+	ids, err := s.rdb.SMembers(ctx, membersPrefix+team).Result()
+	if err != nil {
+		return nil, redisutil.Error(err)
+	}
+	if len(ids) == 0 {
+		return nil, apierror.NotFound("team not found")
+	}
+	slices.Sort(ids)
+
+	names, err := s.players.Names(ctx, ids...)
+	if err != nil {
+		return nil, err
+	}
+	members := make([]Member, len(ids))
+	for i, id := range ids {
+		members[i] = Member{ID: id, Name: names[i]}
+	}
+	return members, nil
+}
+
+// Record recomputes the team of the player who scored, only in incremental mode.
+func (s *Service) Record(ctx context.Context, player model.Score) error {
+	return s.Refresh(ctx, player.ID)
+}
+
+// Refresh recomputes the team of the player after their score changed, only
+// in incremental mode.
+func (s *Service) Refresh(ctx context.Context, playerID string) error {
+	if s.config().Mode != ModeIncremental {
+		return nil
+	}
+
+	team, err := s.rdb.HGet(ctx, membership, playerID).Result()
+	if errors.Is(err, redis.Nil) {
+		return nil
+	}
+	if err != nil {
+		return redisutil.Error(err)
+	}
+	return s.update(ctx, team)
+}
+
+// Rebuild recomputes every team and replaces the team board at once.
+func (s *Service) Rebuild(ctx context.Context) error {
+	memberships, err := s.rdb.HVals(ctx, membership).Result()
+	if err != nil {
+		return redisutil.Error(err)
+	}
+	slices.Sort(memberships)
+	teams := slices.Compact(memberships)
+
+	scores := make([]redis.Z, 0, len(teams))
+	for _, team := range teams {
+		value, ok, err := s.teamScore(ctx, team)
+		if err != nil {
+			return err
+		}
+		if ok {
+			scores = append(scores, redis.Z{Score: value, Member: team})
+		}
+	}
+
+	_, err = s.rdb.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		if len(scores) == 0 {
+			pipe.Del(ctx, Board)
+			return nil
+		}
+		pipe.Del(ctx, rebuildKey)
+		pipe.ZAdd(ctx, rebuildKey, scores...)
+		pipe.Rename(ctx, rebuildKey, Board)
+		return nil
+	})
+	if err != nil {
+		return redisutil.Error(err)
+	}
+	s.publish(ctx)
+	return nil
+}
+
+// update recomputes the scores of teams and publishes the top teams, teams
+// without scored members are removed from the board.
+func (s *Service) update(ctx context.Context, teams ...string) error {
+	for _, team := range teams {
+		value, ok, err := s.teamScore(ctx, team)
+		if err != nil {
+			return err
+		}
+		if ok {
+			err = s.rdb.ZAdd(ctx, Board, redis.Z{Score: value, Member: team}).Err()
+		} else {
+			err = s.rdb.ZRem(ctx, Board, team).Err()
+		}
+		if err != nil {
+			return redisutil.Error(err)
+		}
+	}
+	s.publish(ctx)
+	return nil
+}
+
+// teamScore aggregates the scores of the members of team found on the player
+// board, it returns false when none of them scored.
+func (s *Service) teamScore(ctx context.Context, team string) (float64, bool, error) {
+	// The members set weighs nothing, the intersection holds the player scores.
+	members, err := s.rdb.ZInterWithScores(ctx, &redis.ZStore{
+		Keys:    []string{score.Board, membersPrefix + team},
+		Weights: []float64{1, 0},
+	}).Result()
+	if err != nil {
+		return 0, false, redisutil.Error(err)
+	}
+	if len(members) == 0 {
+		return 0, false, nil
+	}
+
+	scores := make([]float64, len(members))
+	for i, member := range members {
+		scores[i] = member.Score
+	}
+	config := s.config()
+	return Aggregate(scores, config.Aggregation, config.TopN), true, nil
+}
+
+// publish sends the top teams to the connected clients, the board was
+// updated already so a failure is only logged.
+func (s *Service) publish(ctx context.Context) {
+	topScores, err := s.board.GetTopK(ctx, s.topK())
+	if err != nil {
+		slog.ErrorContext(ctx, "error getting top teams", "error", err.Error())
+		return
+	}
+	if err := s.board.PublishTopScores(ctx, topScores); err != nil {
+		slog.ErrorContext(ctx, "error publishing top teams", "error", err.Error())
+	}
+}
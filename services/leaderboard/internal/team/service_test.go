@@ -0,0 +1,124 @@
+package team
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/go-redis/redismock/v9"
+	"github.com/iamnotrodger/golang-projects/services/leaderboard/internal/model"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestService(rdb *redis.Client, mode string) *Service {
+	config := NewConfig()
+	config.Mode = mode
+	return NewService(rdb, func() Config { return config }, func() int { return 10 })
+}
+
+func expectTeamScore(mock redismock.ClientMock, team string, scores ...redis.Z) {
+	mock.ExpectZInterWithScores(&redis.ZStore{
+		Keys:    []string{"leaderboard", "leaderboard:teams:members:" + team},
+		Weights: []float64{1, 0},
+	}).SetVal(scores)
+}
+
+func expectPublish(mock redismock.ClientMock, topTeams []redis.Z, published []model.Score) {
+	mock.ExpectZRevRangeWithScores("leaderboard:teams", 0, 9).SetVal(topTeams)
+	data, _ := json.Marshal(published)
+	mock.ExpectPublish("leaderboard:teams:top10", data).SetVal(1)
+}
+
+func TestService_Join(t *testing.T) {
+	rdb, mock := redismock.NewClientMock()
+	mock.ExpectHGet("leaderboard:teams:membership", "player-1").SetVal("red")
+	mock.ExpectTxPipeline()
+	mock.ExpectHSet("leaderboard:teams:membership", "player-1", "blue").SetVal(0)
+	mock.ExpectSRem("leaderboard:teams:members:red", "player-1").SetVal(1)
+	mock.ExpectSAdd("leaderboard:teams:members:blue", "player-1").SetVal(1)
+	mock.ExpectTxPipelineExec()
+	expectTeamScore(mock, "blue", redis.Z{Member: "player-1", Score: 100}, redis.Z{Member: "player-2", Score: 50})
+	mock.ExpectZAdd("leaderboard:teams", redis.Z{Member: "blue", Score: 150}).SetVal(1)
+	expectTeamScore(mock, "red")
+	mock.ExpectZRem("leaderboard:teams", "red").SetVal(1)
+	expectPublish(mock, []redis.Z{{Member: "blue", Score: 150}}, []model.Score{{Name: "blue", Value: 150}})
+
+	err := newTestService(rdb, ModeIncremental).Join(context.Background(), "player-1", "blue")
+
+	require.NoError(t, err)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestService_Record(t *testing.T) {
+	tests := []struct {
+		name      string
+		mode      string
+		setupMock func(mock redismock.ClientMock)
+	}{
+		{
+			name: "recomputes the team of the player",
+			mode: ModeIncremental,
+			setupMock: func(mock redismock.ClientMock) {
+				mock.ExpectHGet("leaderboard:teams:membership", "player-1").SetVal("blue")
+				expectTeamScore(mock, "blue", redis.Z{Member: "player-1", Score: 120})
+				mock.ExpectZAdd("leaderboard:teams", redis.Z{Member: "blue", Score: 120}).SetVal(0)
+				expectPublish(mock, []redis.Z{{Member: "blue", Score: 120}}, []model.Score{{Name: "blue", Value: 120}})
+			},
+		},
+		{
+			name: "ignores players without a team",
+			mode: ModeIncremental,
+			setupMock: func(mock redismock.ClientMock) {
+				mock.ExpectHGet("leaderboard:teams:membership", "player-1").RedisNil()
+			},
+		},
+		{
+			name:      "leaves the rebuild to the rebuilder",
+			mode:      ModeRebuild,
+			setupMock: func(mock redismock.ClientMock) {},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rdb, mock := redismock.NewClientMock()
+			tt.setupMock(mock)
+
+			err := newTestService(rdb, tt.mode).Record(context.Background(), model.Score{ID: "player-1", Name: "Alice", Value: 120})
+
+			require.NoError(t, err)
+			require.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestService_Rebuild(t *testing.T) {
+	rdb, mock := redismock.NewClientMock()
+	mock.ExpectHVals("leaderboard:teams:membership").SetVal([]string{"red", "blue", "red"})
+	expectTeamScore(mock, "blue", redis.Z{Member: "player-1", Score: 100})
+	expectTeamScore(mock, "red", redis.Z{Member: "player-2", Score: 70}, redis.Z{Member: "player-3", Score: 60})
+	mock.ExpectTxPipeline()
+	mock.ExpectDel("leaderboard:teams:rebuild").SetVal(0)
+	mock.ExpectZAdd("leaderboard:teams:rebuild", redis.Z{Member: "blue", Score: 100}, redis.Z{Member: "red", Score: 130}).SetVal(2)
+	mock.ExpectRename("leaderboard:teams:rebuild", "leaderboard:teams").SetVal("OK")
+	mock.ExpectTxPipelineExec()
+	expectPublish(mock, []redis.Z{{Member: "red", Score: 130}, {Member: "blue", Score: 100}}, []model.Score{{Name: "red", Value: 130}, {Name: "blue", Value: 100}})
+
+	err := newTestService(rdb, ModeRebuild).Rebuild(context.Background())
+
+	require.NoError(t, err)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestService_Members(t *testing.T) {
+	rdb, mock := redismock.NewClientMock()
+	mock.ExpectSMembers("leaderboard:teams:members:blue").SetVal([]string{"player-2", "player-1"})
+	mock.ExpectHMGet("leaderboard:names", "player-1", "player-2").SetVal([]any{"Alice", nil})
+
+	members, err := newTestService(rdb, ModeIncremental).Members(context.Background(), "blue")
+
+	require.NoError(t, err)
+	require.Equal(t, []Member{{ID: "player-1", Name: "Alice"}, {ID: "player-2", Name: "player-2"}}, members)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
@@ -2,42 +2,129 @@ package appctx
 
 import (
 	"context"
+	"errors"
+	"log/slog"
+	"time"
 
 	"github.com/iamnotrodger/golang-projects/pkg/app"
+	"github.com/iamnotrodger/golang-projects/pkg/auth"
+	pkgconfig "github.com/iamnotrodger/golang-projects/pkg/config"
 	"github.com/iamnotrodger/golang-projects/pkg/health"
+	"github.com/iamnotrodger/golang-projects/pkg/middleware"
+	"github.com/iamnotrodger/golang-projects/pkg/proto/topics"
+	"github.com/iamnotrodger/golang-projects/pkg/ratelimit"
+	"github.com/iamnotrodger/golang-projects/pkg/schemaregistry"
+	"github.com/iamnotrodger/golang-projects/pkg/telemetry"
+	"github.com/iamnotrodger/golang-projects/services/producer/internal/config"
 	"github.com/iamnotrodger/golang-projects/services/producer/internal/healthcheck"
 	"github.com/iamnotrodger/golang-projects/services/producer/internal/metrics"
 	"github.com/iamnotrodger/golang-projects/services/producer/internal/processes"
 	"github.com/iamnotrodger/golang-projects/services/producer/internal/ticket"
 )
 
+const (
+	healthCheckInterval = 10 * time.Second
+	healthCheckMaxAge   = 30 * time.Second
+)
+
+type Options struct {
+	Config   *pkgconfig.Loader[*config.Spec]
+	LogLevel *slog.LevelVar
+}
+
 type AppContext struct {
-	ticketService *ticket.Service
-	healthService *health.Service
+	options           Options
+	shutdownTelemetry func(context.Context) error
+	ticketService     *ticket.Service
+	schemaRegistry    schemaregistry.Registry
+	authenticator     auth.Authenticator
+	rateLimiter       ratelimit.Limiter
+	healthService     *health.Service
+	healthMonitor     *health.Monitor
 }
 
-func BuildAppProcesses(appCtx *AppContext) map[string]app.Runnable {
+func (appCtx *AppContext) Processes() map[string]app.Runnable {
 	return map[string]app.Runnable{
 		"http": processes.NewHttpServer(processes.HttpServerServices{
-			HealthService: appCtx.healthService,
-			TicketService: appCtx.ticketService,
+			Config:         appCtx.options.Config,
+			LogLevel:       appCtx.options.LogLevel,
+			HealthMonitor:  appCtx.healthMonitor,
+			TicketService:  appCtx.ticketService,
+			SchemaRegistry: appCtx.schemaRegistry,
+			Authenticator:  appCtx.authenticator,
+			RateLimiter:    appCtx.rateLimiter,
 		}),
+		"health-monitor": appCtx.healthMonitor,
+		"config-watcher": app.Supervise(
+			appCtx.options.Config,
+			app.ProcessOptions{
+				Restart:     app.RestartOnFailure,
+				MaxRestarts: 5,
+				Window:      time.Minute,
+			},
+		),
 	}
 }
 
-func NewAppContext(ctx context.Context) *AppContext {
-	appCtx := AppContext{}
+func NewAppContext(ctx context.Context, options Options) (*AppContext, error) {
+	appCtx := AppContext{options: options}
 
 	metrics.MustRegister()
+	health.MustRegister()
+	middleware.MustRegister()
+
+	var err error
+	appCtx.shutdownTelemetry, err = telemetry.Setup(ctx, config.ServiceName, config.Global.Telemetry)
+	if err != nil {
+		return nil, err
+	}
+
+	kafkaClient, err := config.Global.Kafka.Client()
+	if err != nil {
+		return nil, err
+	}
 
-	appCtx.ticketService = ticket.NewService()
+	appCtx.authenticator, err = config.Global.Auth.NewAuthenticator()
+	if err != nil {
+		return nil, err
+	}
+	appCtx.rateLimiter = ratelimit.NewMemory()
+
+	if config.Global.SchemaRegistry.Embedded && config.Global.Env != config.EnvDevelopment {
+		return nil, errors.New("the embedded schema registry is only for development, set SCHEMA_REGISTRY_URL")
+	}
+	appCtx.schemaRegistry = config.Global.SchemaRegistry.NewRegistry()
+	serializer := schemaregistry.NewSerializer(
+		appCtx.schemaRegistry,
+		schemaregistry.TopicSubject(config.Global.KafkaTicketTopic),
+		&topics.Ticket{},
+	)
+	if err := serializer.Register(ctx); err != nil {
+		slog.Error("failed to register ticket schema", "error", err.Error())
+		return nil, err
+	}
+
+	appCtx.ticketService, err = ticket.NewService(serializer)
+	if err != nil {
+		return nil, err
+	}
 	appCtx.healthService = health.NewService(map[string]health.HealthCheck{
-		"kafka": healthcheck.NewKafkaCheck(),
+		"kafka": healthcheck.NewKafkaCheck(kafkaClient, config.Global.KafkaTicketTopic),
 	})
+	appCtx.healthMonitor = health.NewMonitor(appCtx.healthService, healthCheckInterval, healthCheckMaxAge)
 
-	return &appCtx
+	return &appCtx, nil
 }
 
 func (a *AppContext) Shutdown(ctx context.Context) error {
-	return nil
+	var errs []error
+	if err := a.ticketService.Close(); err != nil {
+		slog.Error("error closing kafka writer", "error", err.Error())
+		errs = append(errs, err)
+	}
+	if err := a.shutdownTelemetry(ctx); err != nil {
+		slog.Error("error flushing traces", "error", err.Error())
+		errs = append(errs, err)
+	}
+	return errors.Join(errs...)
 }
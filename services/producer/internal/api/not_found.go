@@ -1,18 +1,12 @@
 package api
 
 import (
-	"net/http"
-
 	"github.com/gin-gonic/gin"
-	"github.com/iamnotrodger/golang-projects/services/producer/internal/model"
+	"github.com/iamnotrodger/golang-projects/pkg/apierror"
 )
 
 func NotFound() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		c.JSON(http.StatusNotFound, &model.Error{
-			Error:       http.StatusText(http.StatusNotFound),
-			Code:        http.StatusNotFound,
-			Description: "route not found",
-		})
+		c.Error(apierror.NotFound("route not found"))
 	}
 }
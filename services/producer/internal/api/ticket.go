@@ -1,12 +1,19 @@
 package api
 
 import (
+	"context"
+
 	"github.com/gin-gonic/gin"
+	"github.com/iamnotrodger/golang-projects/pkg/apierror"
+	"github.com/iamnotrodger/golang-projects/services/producer/internal/metrics"
 	"github.com/iamnotrodger/golang-projects/services/producer/internal/model"
 )
 
+// ScopeTicketsWrite allows creating tickets.
+const ScopeTicketsWrite = "tickets:write"
+
 type ticketService interface {
-	CreateTicket(ticket *model.Ticket) error
+	CreateTicket(ctx context.Context, ticket *model.Ticket) error
 }
 
 type TicketAPI struct {
@@ -22,12 +29,13 @@ func NewTicketAPI(service ticketService) *TicketAPI {
 func (a *TicketAPI) CreateTicket(ctx *gin.Context) {
 	ticket := &model.Ticket{}
 	if err := ctx.ShouldBindJSON(ticket); err != nil {
-		ctx.AbortWithError(400, err)
+		metrics.RecordError(metrics.ErrorValidation)
+		ctx.Error(apierror.Validation("invalid ticket", err))
 		return
 	}
 
-	if err := a.service.CreateTicket(ticket); err != nil {
-		ctx.AbortWithError(500, err)
+	if err := a.service.CreateTicket(ctx.Request.Context(), ticket); err != nil {
+		ctx.Error(err)
 		return
 	}
 
@@ -2,6 +2,7 @@ package api
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"net/http"
@@ -9,18 +10,20 @@ import (
 	"testing"
 
 	"github.com/gin-gonic/gin"
+	"github.com/iamnotrodger/golang-projects/pkg/apierror"
+	"github.com/iamnotrodger/golang-projects/pkg/middleware"
 	"github.com/iamnotrodger/golang-projects/services/producer/internal/model"
 	"github.com/stretchr/testify/require"
 )
 
 // MockTicketService is a mock implementation of TicketService
 type MockTicketService struct {
-	CreateTicketFunc func(ticket *model.Ticket) error
+	CreateTicketFunc func(ctx context.Context, ticket *model.Ticket) error
 }
 
-func (m *MockTicketService) CreateTicket(ticket *model.Ticket) error {
+func (m *MockTicketService) CreateTicket(ctx context.Context, ticket *model.Ticket) error {
 	if m.CreateTicketFunc != nil {
-		return m.CreateTicketFunc(ticket)
+		return m.CreateTicketFunc(ctx, ticket)
 	}
 	return nil
 }
@@ -47,14 +50,21 @@ func TestTicketAPI_CreateTicket(t *testing.T) {
 			requestBody:    []byte(`{"id":"123","title":}`),
 			serviceError:   nil,
 			expectedStatus: http.StatusBadRequest,
-			expectedBody:   nil,
+			expectedBody:   map[string]any{"error": "validation_failed", "code": 400.0, "description": "invalid ticket"},
 		},
 		{
 			name:           "service error returns 500",
 			requestBody:    map[string]any{"id": "456", "title": "Sports Ticket", "price": 75.50},
 			serviceError:   errors.New("service error"),
 			expectedStatus: http.StatusInternalServerError,
-			expectedBody:   nil,
+			expectedBody:   map[string]any{"error": "internal_error", "code": 500.0, "description": "internal server error"},
+		},
+		{
+			name:           "unavailable kafka returns 503",
+			requestBody:    map[string]any{"id": "789", "title": "Theatre Ticket", "price": 25.00},
+			serviceError:   apierror.Unavailable("kafka", errors.New("connection refused")),
+			expectedStatus: http.StatusServiceUnavailable,
+			expectedBody:   map[string]any{"error": "dependency_unavailable", "code": 503.0, "description": "kafka is unavailable"},
 		},
 		{
 			name:           "missing fields still processes",
@@ -68,23 +78,24 @@ func TestTicketAPI_CreateTicket(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			mockService := &MockTicketService{
-				CreateTicketFunc: func(ticket *model.Ticket) error {
+				CreateTicketFunc: func(ctx context.Context, ticket *model.Ticket) error {
 					return tt.serviceError
 				},
 			}
 
 			ticketAPI := NewTicketAPI(mockService)
 
-			w := httptest.NewRecorder()
-			ctx, _ := gin.CreateTestContext(w)
+			engine := gin.New()
+			engine.Use(middleware.Errors())
+			engine.POST("/tickets", ticketAPI.CreateTicket)
 
 			requestBody, err := json.Marshal(tt.requestBody)
 			require.NoError(t, err)
 
-			ctx.Request = httptest.NewRequest(http.MethodPost, "/tickets", bytes.NewBuffer(requestBody))
-			ctx.Request.Header.Set("Content-Type", "application/json")
-
-			ticketAPI.CreateTicket(ctx)
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, "/tickets", bytes.NewBuffer(requestBody))
+			req.Header.Set("Content-Type", "application/json")
+			engine.ServeHTTP(w, req)
 
 			require.Equal(t, tt.expectedStatus, w.Code)
 
@@ -1,29 +1,57 @@
 package config
 
 import (
-	"fmt"
+	"time"
 
-	"github.com/go-viper/mapstructure/v2"
-	"github.com/spf13/viper"
+	"github.com/iamnotrodger/golang-projects/pkg/auth"
+	pkgconfig "github.com/iamnotrodger/golang-projects/pkg/config"
+	"github.com/iamnotrodger/golang-projects/pkg/kafkaclient"
+	"github.com/iamnotrodger/golang-projects/pkg/middleware"
+	"github.com/iamnotrodger/golang-projects/pkg/ratelimit"
+	"github.com/iamnotrodger/golang-projects/pkg/schemaregistry"
+	"github.com/iamnotrodger/golang-projects/pkg/telemetry"
+	"github.com/iamnotrodger/golang-projects/services/producer/internal/writer"
+	"github.com/spf13/pflag"
 )
 
+// ServiceName identifies the producer in traces and event metadata.
+const ServiceName = "producer"
+
+// EnvDevelopment is the environment the embedded schema registry is allowed in.
+const EnvDevelopment = "development"
+
 const (
-	defaultEnv              = "development"
+	defaultEnv              = EnvDevelopment
 	defaultPort             = 8080
 	defaultLogLevel         = "info"
 	defaultKafkaBroker      = "localhost:9092"
 	defaultKafkaTicketTopic = "tickets"
+	// defaultSchemaRegistryURL is the registry of docker-compose.
+	defaultSchemaRegistryURL = "http://localhost:8085"
 )
 
+var defaultCreateTicketLimit = ratelimit.Limit{Requests: 60, Window: time.Minute}
+
 type Secret struct{}
 
+// RateLimit limits the writes of every client, per replica.
+type RateLimit struct {
+	CreateTicket ratelimit.Limit `mapstructure:"create_ticket"`
+}
+
 type Spec struct {
 	*Secret          `json:"-"`
-	Env              string `mapstructure:"env"`
-	Port             int    `mapstructure:"port"`
-	LogLevel         string `mapstructure:"log_level"`
-	KafkaBroker      string `mapstructure:"kafka_broker"`
-	KafkaTicketTopic string `mapstructure:"kafka_topic"`
+	Env              string                `mapstructure:"env" validate:"required"`
+	Port             int                   `mapstructure:"port" validate:"min=1,max=65535"`
+	LogLevel         string                `mapstructure:"log_level" validate:"oneof=debug info warn error" reload:"true"`
+	Kafka            kafkaclient.Config    `mapstructure:"kafka"`
+	KafkaTicketTopic string                `mapstructure:"kafka_topic" validate:"required"`
+	KafkaWriter      writer.Config         `mapstructure:"kafka_writer"`
+	SchemaRegistry   schemaregistry.Config `mapstructure:"schema_registry"`
+	Telemetry        telemetry.Config      `mapstructure:"telemetry"`
+	HTTP             middleware.Config     `mapstructure:"http"`
+	Auth             auth.Config           `mapstructure:"auth"`
+	RateLimit        RateLimit             `mapstructure:"rate_limit"`
 }
 
 func New() *Spec {
@@ -33,36 +61,29 @@ func New() *Spec {
 		Env:              defaultEnv,
 		Port:             defaultPort,
 		LogLevel:         defaultLogLevel,
-		KafkaBroker:      defaultKafkaBroker,
+		Kafka:            kafkaclient.New(defaultKafkaBroker),
 		KafkaTicketTopic: defaultKafkaTicketTopic,
+		KafkaWriter:      writer.NewConfig(),
+		SchemaRegistry:   schemaregistry.Config{URL: defaultSchemaRegistryURL},
+		Telemetry:        telemetry.NewConfig(),
+		HTTP:             middleware.NewConfig(),
+		Auth:             auth.NewConfig(),
+		RateLimit:        RateLimit{CreateTicket: defaultCreateTicketLimit},
 	}
 }
 
+// Global is the configuration loaded at startup, it is not updated on reload.
+// The settings tagged with reload:"true" are read from the loader.
 var Global = New()
 
-func LoadConfig(spec *Spec) {
-	v := viper.New()
-	v.SetConfigFile(".env")
-	v.ReadInConfig()
-	v.AutomaticEnv()
-
-	setDefaults(v, spec)
-
-	if err := v.Unmarshal(spec); err != nil {
-		panic(fmt.Errorf("fatal error unmarshalling config %s", err))
-	}
-}
-
-func setDefaults(v *viper.Viper, i any) {
-	values := map[string]any{}
-	if err := mapstructure.Decode(i, &values); err != nil {
-		panic(err)
-	}
-	for key, defaultValue := range values {
-		v.SetDefault(key, defaultValue)
-	}
+// NewLoader creates the loader of the configuration from its defaults, the YAML
+// file, .env, the environment and args. flags are parsed along with the
+// configuration flags. Settings tagged with reload:"true" can change at runtime.
+func NewLoader(args []string, flags *pflag.FlagSet) *pkgconfig.Loader[*Spec] {
+	return pkgconfig.NewLoader(New, pkgconfig.Options{Args: args, FlagSet: flags})
 }
 
-func init() {
-	LoadConfig(Global)
+// Load reads and validates the configuration.
+func Load(args []string, flags *pflag.FlagSet) (*Spec, error) {
+	return NewLoader(args, flags).Load()
 }
@@ -1,9 +1,9 @@
 package config
 
 import (
-	"os"
 	"testing"
 
+	"github.com/iamnotrodger/golang-projects/services/producer/internal/writer"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -11,26 +11,20 @@ func TestGlobal(t *testing.T) {
 	assert.Equal(t, Global.Env, defaultEnv)
 	assert.Equal(t, Global.Port, defaultPort)
 	assert.Equal(t, Global.LogLevel, defaultLogLevel)
-	assert.Equal(t, Global.KafkaBroker, defaultKafkaBroker)
+	assert.Equal(t, Global.Kafka.Brokers, []string{defaultKafkaBroker})
 	assert.Equal(t, Global.KafkaTicketTopic, defaultKafkaTicketTopic)
+	assert.Equal(t, Global.KafkaWriter, writer.NewConfig())
+	assert.Equal(t, Global.SchemaRegistry.URL, defaultSchemaRegistryURL)
+	assert.False(t, Global.SchemaRegistry.Embedded)
+	assert.Equal(t, Global.RateLimit.CreateTicket, defaultCreateTicketLimit)
 }
 
-func TestLoadConfig(t *testing.T) {
-	testConfig := New()
-
-	originalPort := os.Getenv("PORT")
-	originalLogLevel := os.Getenv("LOG_LEVEL")
-
-	defer func() {
-		os.Setenv("PORT", originalPort)
-		os.Setenv("LOG_LEVEL", originalLogLevel)
-	}()
-
+func TestLoad(t *testing.T) {
 	envLogLevel := "debug"
-	os.Setenv("LOG_LEVEL", envLogLevel)
-	os.Unsetenv("PORT")
+	t.Setenv("LOG_LEVEL", envLogLevel)
 
-	LoadConfig(testConfig)
+	testConfig, err := Load([]string{"--env", "test"}, nil)
+	assert.NoError(t, err)
 
 	cases := []struct {
 		name        string
@@ -47,6 +41,11 @@ func TestLoadConfig(t *testing.T) {
 			configValue: testConfig.Port,
 			expected:    defaultPort,
 		},
+		{
+			name:        "uses flag value when defined",
+			configValue: testConfig.Env,
+			expected:    "test",
+		},
 	}
 
 	for _, tc := range cases {
@@ -55,3 +54,14 @@ func TestLoadConfig(t *testing.T) {
 		})
 	}
 }
+
+func TestLoad_Invalid(t *testing.T) {
+	t.Setenv("PORT", "0")
+	t.Setenv("KAFKA_SASL_MECHANISM", "plain")
+	t.Setenv("KAFKA_WRITER_REQUIRED_ACKS", "two")
+	t.Setenv("KAFKA_WRITER_BATCH_TIMEOUT", "0s")
+
+	_, err := Load(nil, nil)
+
+	assert.EqualError(t, err, "invalid configuration:\n  port: must be at least 1, got 0\n  kafka.sasl.username: is required when mechanism is set\n  kafka.sasl.password: is required when mechanism is set\n  kafka_writer.required_acks: must be one of [none one all], got \"two\"\n  kafka_writer.batch_timeout: must be at least 1ms, got 0s")
+}
@@ -3,21 +3,27 @@ package healthcheck
 import (
 	"context"
 
-	"github.com/iamnotrodger/golang-projects/services/producer/internal/config"
+	"github.com/iamnotrodger/golang-projects/pkg/kafkaclient"
 	"github.com/segmentio/kafka-go"
 )
 
-type KafkaCheck struct{}
+type KafkaCheck struct {
+	client *kafka.Client
+	topic  string
+}
 
-func NewKafkaCheck() *KafkaCheck {
-	return &KafkaCheck{}
+func NewKafkaCheck(client *kafka.Client, topic string) *KafkaCheck {
+	return &KafkaCheck{
+		client: client,
+		topic:  topic,
+	}
 }
 
 func (k *KafkaCheck) Ping(ctx context.Context) error {
-	conn, err := kafka.Dial("tcp", config.Global.KafkaBroker)
+	metadata, err := k.client.Metadata(ctx, &kafka.MetadataRequest{Topics: []string{k.topic}})
 	if err != nil {
 		return err
 	}
-	defer conn.Close()
-	return nil
+	_, err = kafkaclient.ValidateTopic(k.topic, metadata)
+	return err
 }
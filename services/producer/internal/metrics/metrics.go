@@ -2,9 +2,18 @@ package metrics
 
 import "github.com/prometheus/client_golang/prometheus"
 
+const (
+	TicketCreated = "ticket.created"
+
+	ErrorValidation = "validation"
+	ErrorMarshal    = "marshal"
+	ErrorKafkaWrite = "kafka_write"
+)
+
 type metrics struct {
 	TicketsCreatedCounter *prometheus.CounterVec
 	ErrorCounter          *prometheus.CounterVec
+	DeliveryCounter       *prometheus.CounterVec
 }
 
 var metric = metrics{
@@ -22,15 +31,27 @@ var metric = metrics{
 		},
 		[]string{"type"},
 	),
+	DeliveryCounter: prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "producer_kafka_messages_total",
+			Help: "total number of kafka messages by delivery result",
+		},
+		[]string{"result"},
+	),
 }
 
 func MustRegister() {
 	prometheus.MustRegister(metric.TicketsCreatedCounter)
 	prometheus.MustRegister(metric.ErrorCounter)
+	prometheus.MustRegister(metric.DeliveryCounter)
 	initCounters()
 }
 
 func initCounters() {
-	metric.TicketsCreatedCounter.WithLabelValues("REPLACE_ME").Add(0)
-	metric.ErrorCounter.WithLabelValues("REPLACE_ME").Add(0)
+	metric.TicketsCreatedCounter.WithLabelValues(TicketCreated).Add(0)
+	for _, errorType := range []string{ErrorValidation, ErrorMarshal, ErrorKafkaWrite} {
+		metric.ErrorCounter.WithLabelValues(errorType).Add(0)
+	}
+	metric.DeliveryCounter.WithLabelValues("delivered").Add(0)
+	metric.DeliveryCounter.WithLabelValues("failed").Add(0)
 }
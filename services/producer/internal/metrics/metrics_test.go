@@ -12,4 +12,5 @@ func TestMustRegister(t *testing.T) {
 
 	assert.True(t, prometheus.Unregister(metric.TicketsCreatedCounter))
 	assert.True(t, prometheus.Unregister(metric.ErrorCounter))
+	assert.True(t, prometheus.Unregister(metric.DeliveryCounter))
 }
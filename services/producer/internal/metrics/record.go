@@ -7,3 +7,7 @@ func RecordTicketCreated(ticketType string) {
 func RecordError(errorType string) {
 	metric.ErrorCounter.WithLabelValues(errorType).Inc()
 }
+
+func RecordDelivery(result string, count int) {
+	metric.DeliveryCounter.WithLabelValues(result).Add(float64(count))
+}
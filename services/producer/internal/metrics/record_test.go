@@ -48,12 +48,18 @@ func assertCounterResults(t *testing.T, collector prometheus.Collector, name str
 
 func TestRecordTicketCreated(t *testing.T) {
 	metric.TicketsCreatedCounter.Reset()
-	RecordTicketCreated("ticketType")
-	assertCounterResults(t, metric.TicketsCreatedCounter, "producer_tickets_created_total", 1, prometheus.Labels{"type": "ticketType"})
+	RecordTicketCreated(TicketCreated)
+	assertCounterResults(t, metric.TicketsCreatedCounter, "producer_tickets_created_total", 1, prometheus.Labels{"type": TicketCreated})
 }
 
 func TestRecordError(t *testing.T) {
 	metric.ErrorCounter.Reset()
-	RecordError("errorType")
-	assertCounterResults(t, metric.ErrorCounter, "producer_error_total", 1, prometheus.Labels{"type": "errorType"})
+	RecordError(ErrorKafkaWrite)
+	assertCounterResults(t, metric.ErrorCounter, "producer_error_total", 1, prometheus.Labels{"type": ErrorKafkaWrite})
+}
+
+func TestRecordDelivery(t *testing.T) {
+	metric.DeliveryCounter.Reset()
+	RecordDelivery("delivered", 3)
+	assertCounterResults(t, metric.DeliveryCounter, "producer_kafka_messages_total", 3, prometheus.Labels{"result": "delivered"})
 }
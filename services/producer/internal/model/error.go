@@ -1,7 +0,0 @@
-package model
-
-type Error struct {
-	Error       string `json:"error"`
-	Code        int    `json:"code"`
-	Description string `json:"description"`
-}
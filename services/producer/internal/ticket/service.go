@@ -3,8 +3,12 @@ package ticket
 import (
 	"context"
 	"log/slog"
+	"time"
 
+	"github.com/iamnotrodger/golang-projects/pkg/apierror"
+	"github.com/iamnotrodger/golang-projects/pkg/eventmeta"
 	"github.com/iamnotrodger/golang-projects/pkg/proto/topics"
+	"github.com/iamnotrodger/golang-projects/pkg/telemetry"
 	"github.com/iamnotrodger/golang-projects/services/producer/internal/config"
 	"github.com/iamnotrodger/golang-projects/services/producer/internal/metrics"
 	"github.com/iamnotrodger/golang-projects/services/producer/internal/model"
@@ -14,23 +18,44 @@ import (
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
+const (
+	contentType  = "application/x-protobuf"
+	eventType    = metrics.TicketCreated
+	eventVersion = "1"
+)
+
+type ticketSerializer interface {
+	Serialize(message proto.Message) ([]byte, error)
+	SchemaID() int
+}
+
 type Service struct {
 	kafkaWriter writer.KafkaWriter
+	serializer  ticketSerializer
+	instance    string
 }
 
-func NewService() *Service {
-	kafkaWriter := &kafka.Writer{
-		Topic:    config.Global.KafkaTicketTopic,
-		Addr:     kafka.TCP(config.Global.KafkaBroker),
-		Balancer: &kafka.LeastBytes{},
+func NewService(serializer ticketSerializer) (*Service, error) {
+	transport, err := config.Global.Kafka.Transport()
+	if err != nil {
+		return nil, err
+	}
+
+	kafkaWriter, err := config.Global.KafkaWriter.NewKafkaWriter(config.Global.KafkaTicketTopic, config.Global.Kafka.Addr(), transport)
+	if err != nil {
+		return nil, err
 	}
 
 	return &Service{
 		kafkaWriter: writer.NewKafkaWriterAdapter(kafkaWriter),
-	}
+		serializer:  serializer,
+		instance:    eventmeta.Instance(),
+	}, nil
 }
 
-func (t *Service) CreateTicket(ticket *model.Ticket) error {
+// CreateTicket publishes the ticket along with the request id and trace
+// context of ctx. The write is not cancelled along with ctx.
+func (t *Service) CreateTicket(ctx context.Context, ticket *model.Ticket) error {
 	protoTicket := &topics.Ticket{
 		Id:        ticket.ID,
 		Title:     ticket.Title,
@@ -38,23 +63,44 @@ func (t *Service) CreateTicket(ticket *model.Ticket) error {
 		CreatedAt: timestamppb.New(ticket.CreatedAt),
 	}
 
-	ticketBytes, err := proto.Marshal(protoTicket)
+	ticketBytes, err := t.serializer.Serialize(protoTicket)
 	if err != nil {
-		slog.Error("failed to marshal ticket to protobuf", "error", err.Error())
+		slog.ErrorContext(ctx, "failed to serialize ticket", "error", err.Error())
+		metrics.RecordError(metrics.ErrorMarshal)
 		return err
 	}
 
+	metadata := eventmeta.Metadata{
+		ContentType:      contentType,
+		SchemaID:         t.serializer.SchemaID(),
+		EventType:        eventType,
+		EventVersion:     eventVersion,
+		ProducerService:  config.ServiceName,
+		ProducerInstance: t.instance,
+		RequestID:        eventmeta.RequestID(ctx),
+		EventTime:        time.Now().UTC(),
+	}
+
 	msg := kafka.Message{
-		Key:   []byte(ticket.ID),
-		Value: ticketBytes,
+		Key:     []byte(ticket.ID),
+		Value:   ticketBytes,
+		Headers: metadata.Headers(),
 	}
 
-	err = t.kafkaWriter.WriteMessages(context.Background(), msg)
+	ctx, span := telemetry.StartProducerSpan(ctx, config.Global.KafkaTicketTopic, &msg)
+	err = t.kafkaWriter.WriteMessages(context.WithoutCancel(ctx), msg)
+	telemetry.EndSpan(span, err)
 	if err != nil {
-		slog.Error("failed to write ticket message to kafka", "error", err.Error())
-		return err
+		slog.ErrorContext(ctx, "failed to write ticket message to kafka", append(metadata.LogAttrs(), "error", err.Error())...)
+		metrics.RecordError(metrics.ErrorKafkaWrite)
+		return apierror.Unavailable("kafka", err)
 	}
 
-	metrics.RecordTicketCreated("ticket_type")
+	metrics.RecordTicketCreated(eventType)
 	return nil
 }
+
+// Close flushes the pending messages and closes the writer.
+func (t *Service) Close() error {
+	return t.kafkaWriter.Close()
+}
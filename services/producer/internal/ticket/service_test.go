@@ -1,21 +1,31 @@
 package ticket
 
 import (
+	"context"
 	"errors"
 	"testing"
 	"time"
 
+	"github.com/iamnotrodger/golang-projects/pkg/apierror"
+	"github.com/iamnotrodger/golang-projects/pkg/eventmeta"
+	"github.com/iamnotrodger/golang-projects/pkg/proto/topics"
+	"github.com/iamnotrodger/golang-projects/pkg/schemaregistry"
 	"github.com/iamnotrodger/golang-projects/services/producer/internal/config"
 	"github.com/iamnotrodger/golang-projects/services/producer/internal/model"
 	"github.com/iamnotrodger/golang-projects/services/producer/internal/writer"
+	"github.com/segmentio/kafka-go"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
 )
 
 func TestCreateTicket(t *testing.T) {
 	type testCase struct {
 		name          string
 		ticket        *model.Ticket
+		unregistered  bool
+		writeError    error
 		expectedError error
 	}
 
@@ -38,23 +48,69 @@ func TestCreateTicket(t *testing.T) {
 				Price:     100.0,
 				CreatedAt: time.Date(2025, 10, 25, 12, 0, 0, 0, time.UTC),
 			},
-			expectedError: errors.New("kafka connection failed"),
+			writeError:    errors.New("kafka connection failed"),
+			expectedError: apierror.Unavailable("kafka", errors.New("kafka connection failed")),
+		},
+		{
+			name: "unregistered schema",
+			ticket: &model.Ticket{
+				ID:        "ticket3",
+				Title:     "Theatre",
+				Price:     25.0,
+				CreatedAt: time.Date(2025, 10, 25, 12, 0, 0, 0, time.UTC),
+			},
+			unregistered:  true,
+			expectedError: schemaregistry.ErrNotRegistered,
 		},
 	}
 
 	config.Global.KafkaTicketTopic = "test-topic"
+	traceparent := "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	defer otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator())
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
+			serializer := schemaregistry.NewSerializer(schemaregistry.NewMemory(), "test-topic-value", &topics.Ticket{})
+			if !tt.unregistered {
+				require.NoError(t, serializer.Register(context.Background()))
+			}
+
 			mockWriter := &writer.MockKafkaWriter{}
 			service := &Service{
 				kafkaWriter: mockWriter,
+				serializer:  serializer,
 			}
 
-			mockWriter.On("WriteMessages", mock.Anything, mock.Anything).Return(tt.expectedError)
+			if !tt.unregistered {
+				mockWriter.On("WriteMessages", mock.Anything, mock.MatchedBy(func(msgs []kafka.Message) bool {
+					header, _, err := schemaregistry.Decode(msgs[0].Value)
+					metadata := eventmeta.FromHeaders(msgs[0].Headers)
+					return err == nil && header.SchemaID == 1 && string(msgs[0].Key) == tt.ticket.ID &&
+						metadata.SchemaID == 1 &&
+						metadata.RequestID == "request-1" &&
+						metadata.Traceparent == traceparent &&
+						metadata.ProducerService == "producer" &&
+						metadata.EventType == "ticket.created" &&
+						!metadata.EventTime.IsZero()
+				})).Return(tt.writeError)
+			}
 
-			err := service.CreateTicket(tt.ticket)
+			ctx := eventmeta.WithRequestID(context.Background(), "request-1")
+			ctx = propagation.TraceContext{}.Extract(ctx, propagation.MapCarrier{"traceparent": traceparent})
+			err := service.CreateTicket(ctx, tt.ticket)
 			require.Equal(t, tt.expectedError, err)
+			mockWriter.AssertExpectations(t)
 		})
 	}
 }
+
+func TestClose(t *testing.T) {
+	mockWriter := &writer.MockKafkaWriter{}
+	service := &Service{kafkaWriter: mockWriter}
+
+	mockWriter.On("Close").Return(nil).Once()
+
+	require.NoError(t, service.Close())
+	mockWriter.AssertExpectations(t)
+}
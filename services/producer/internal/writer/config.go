@@ -0,0 +1,80 @@
+package writer
+
+import (
+	"log/slog"
+	"net"
+	"time"
+
+	"github.com/iamnotrodger/golang-projects/services/producer/internal/metrics"
+	"github.com/segmentio/kafka-go"
+)
+
+const (
+	DeliveryDelivered = "delivered"
+	DeliveryFailed    = "failed"
+)
+
+// Config tunes the delivery guarantees and batching of the kafka writer.
+type Config struct {
+	RequiredAcks string        `mapstructure:"required_acks" validate:"oneof=none one all"`
+	Compression  string        `mapstructure:"compression" validate:"oneof=none gzip snappy lz4 zstd"`
+	BatchSize    int           `mapstructure:"batch_size" validate:"min=1"`
+	BatchTimeout time.Duration `mapstructure:"batch_timeout" validate:"min=1ms"`
+	MaxAttempts  int           `mapstructure:"max_attempts" validate:"min=1"`
+	// Async makes WriteMessages return before the messages are delivered, the
+	// delivery results are only reported through the metrics and logs.
+	Async bool `mapstructure:"async"`
+}
+
+func NewConfig() Config {
+	return Config{
+		RequiredAcks: "all",
+		Compression:  "snappy",
+		BatchSize:    100,
+		BatchTimeout: 10 * time.Millisecond,
+		MaxAttempts:  10,
+	}
+}
+
+// NewKafkaWriter creates a writer for topic, messages with the same key are
+// always written to the same partition.
+func (c Config) NewKafkaWriter(topic string, addr net.Addr, transport kafka.RoundTripper) (*kafka.Writer, error) {
+	var requiredAcks kafka.RequiredAcks
+	if err := requiredAcks.UnmarshalText([]byte(c.RequiredAcks)); err != nil {
+		return nil, err
+	}
+
+	var compression kafka.Compression
+	if err := compression.UnmarshalText([]byte(c.Compression)); err != nil {
+		return nil, err
+	}
+
+	return &kafka.Writer{
+		Topic:        topic,
+		Addr:         addr,
+		Balancer:     &kafka.Hash{},
+		Transport:    transport,
+		RequiredAcks: requiredAcks,
+		Compression:  compression,
+		BatchSize:    c.BatchSize,
+		BatchTimeout: c.BatchTimeout,
+		MaxAttempts:  c.MaxAttempts,
+		Async:        c.Async,
+		Completion:   completion(c.Async),
+	}, nil
+}
+
+// completion records the delivery results, failures are only logged in async
+// mode since the error is otherwise returned by WriteMessages.
+func completion(async bool) func(messages []kafka.Message, err error) {
+	return func(messages []kafka.Message, err error) {
+		if err != nil {
+			metrics.RecordDelivery(DeliveryFailed, len(messages))
+			if async {
+				slog.Error("failed to deliver kafka messages", "messages", len(messages), "error", err.Error())
+			}
+			return
+		}
+		metrics.RecordDelivery(DeliveryDelivered, len(messages))
+	}
+}
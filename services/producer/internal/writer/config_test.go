@@ -0,0 +1,66 @@
+package writer
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfig_NewKafkaWriter(t *testing.T) {
+	config := Config{
+		RequiredAcks: "one",
+		Compression:  "zstd",
+		BatchSize:    50,
+		BatchTimeout: 5 * time.Millisecond,
+		MaxAttempts:  3,
+		Async:        true,
+	}
+
+	kafkaWriter, err := config.NewKafkaWriter("tickets", kafka.TCP("broker:9092"), nil)
+
+	require.NoError(t, err)
+	require.Equal(t, "tickets", kafkaWriter.Topic)
+	require.Equal(t, "broker:9092", kafkaWriter.Addr.String())
+	require.IsType(t, &kafka.Hash{}, kafkaWriter.Balancer)
+	require.Equal(t, kafka.RequireOne, kafkaWriter.RequiredAcks)
+	require.Equal(t, kafka.Zstd, kafkaWriter.Compression)
+	require.Equal(t, 50, kafkaWriter.BatchSize)
+	require.Equal(t, 5*time.Millisecond, kafkaWriter.BatchTimeout)
+	require.Equal(t, 3, kafkaWriter.MaxAttempts)
+	require.True(t, kafkaWriter.Async)
+	require.NotNil(t, kafkaWriter.Completion)
+
+	require.NotPanics(t, func() {
+		kafkaWriter.Completion([]kafka.Message{{}}, nil)
+		kafkaWriter.Completion([]kafka.Message{{}}, errors.New("leader not available"))
+	})
+}
+
+func TestConfig_NewKafkaWriter_Invalid(t *testing.T) {
+	tests := []struct {
+		name          string
+		config        Config
+		expectedError string
+	}{
+		{
+			name:          "required acks",
+			config:        Config{RequiredAcks: "some", Compression: "none"},
+			expectedError: `"some"`,
+		},
+		{
+			name:          "compression",
+			config:        Config{RequiredAcks: "all", Compression: "brotli"},
+			expectedError: `"brotli"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.config.NewKafkaWriter("tickets", kafka.TCP("broker:9092"), nil)
+			require.ErrorContains(t, err, tt.expectedError)
+		})
+	}
+}